@@ -2,6 +2,7 @@ package v1alpha1
 
 import (
 	"fmt"
+	"regexp"
 	"slices"
 	"strings"
 
@@ -48,6 +49,12 @@ func (chart ReleaseChart) Validate(validRefs []string) error {
 	return nil
 }
 
+// Paths of release spec fields within release files, as expected by yml functions.
+const (
+	ReleaseVersionPath = "spec.version"
+	ReleaseDigestPath  = "spec.digest"
+)
+
 type ReleaseSpec struct {
 	// Project is the name of the project that the release belongs to.
 	Project string `yaml:"project,omitempty" json:"project,omitempty"`
@@ -55,6 +62,15 @@ type ReleaseSpec struct {
 	// Version of the release, typically corresponding to the image build version being deployed.
 	Version string `yaml:"version,omitempty" json:"version,omitempty"`
 
+	// Digest is the optional image digest (ie: "sha256:...") of the build version being deployed, pinning the image
+	// that version refers to. It is always promoted along with the version and is exposed to chart mappings and
+	// values templates as {{ .Release.Spec.Digest }}.
+	Digest string `yaml:"digest,omitempty" json:"digest,omitempty"`
+
+	// DependsOn is the list of names of releases that must reach an environment before this release can be
+	// promoted to it, such as a schema migrator required by an API.
+	DependsOn []string `yaml:"dependsOn,omitempty" json:"dependsOn,omitempty"`
+
 	// Chart is the chart that the release is based on.
 	Chart ReleaseChart `yaml:"chart,omitempty" json:"chart,omitempty"`
 
@@ -100,6 +116,16 @@ func (release *Release) UnmarshalYAML(node *yaml.Node) error {
 	return stripCustomTags(yml.Clone(node)).Decode((*raw)(release))
 }
 
+var digestRegex = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+
+// ValidateDigest returns an error if given image digest is not of the "sha256:<64 hex chars>" form.
+func ValidateDigest(digest string) error {
+	if !digestRegex.MatchString(digest) {
+		return fmt.Errorf("invalid digest %q: expected sha256:<64 hex characters>", digest)
+	}
+	return nil
+}
+
 func IsValidRelease(apiVersion, kind string) bool {
 	return apiVersion == "joy.nesto.ca/v1alpha1" && kind == ReleaseKind
 }
@@ -116,7 +142,7 @@ func LoadRelease(file *yml.File) (*Release, error) {
 }
 
 func stripCustomTags(node *yaml.Node) *yaml.Node {
-	if slices.Contains(yml.CustomTags, node.Tag) {
+	if yml.IsCustomTag(node.Tag) {
 		node.Tag = ""
 	}
 	for i, content := range node.Content {
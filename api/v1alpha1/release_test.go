@@ -18,6 +18,7 @@ func TestReleaseUnmarshalling(t *testing.T) {
 			yes: !lock true,
 			nil: !lock null,
 			age: !lock 42,
+			env: !merge-by:name [{name: A, value: !replace a}],
 		},
 	  },
 	}`
@@ -28,6 +29,7 @@ func TestReleaseUnmarshalling(t *testing.T) {
 	require.Equal(t, true, release.Spec.Values["yes"])
 	require.Equal(t, nil, release.Spec.Values["nil"])
 	require.Equal(t, 42, release.Spec.Values["age"])
+	require.Equal(t, []any{map[string]any{"name": "A", "value": "a"}}, release.Spec.Values["env"])
 }
 
 func TestReleaseChartValidation(t *testing.T) {
@@ -8,6 +8,12 @@ package v1alpha1
 		version: string
 		project: string
 
+		// Digest of the image of the release version, pinning it for supply-chain guarantees.
+		digest?: =~"^sha256:[a-f0-9]{64}$"
+
+		// Names of releases that must reach an environment before this release can be promoted to it.
+		dependsOn?: [...string]
+
 		// Chart to be used by release. If omitted will use the default chart reference defined by the catalog.
 		// Two forms are allowed, you can select a chart reference from the catalog and optionally override its version. Otherwise a fully qualified chart must be defined.
 		chart?: ({
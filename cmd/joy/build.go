@@ -1,9 +1,13 @@
 package main
 
 import (
+	"strings"
+
 	"github.com/spf13/cobra"
 
 	"github.com/nestoca/joy/internal/build"
+	"github.com/nestoca/joy/internal/config"
+	"github.com/nestoca/joy/internal/release/filtering"
 	"github.com/nestoca/joy/internal/yml"
 	"github.com/nestoca/joy/pkg/catalog"
 )
@@ -21,36 +25,77 @@ func NewBuildCmd() *cobra.Command {
 }
 
 func NewBuildPromoteCmd() *cobra.Command {
-	var chartVersion string
+	var (
+		chartVersion string
+		digest       string
+		releases     []string
+		selector     string
+		dryRun       bool
+	)
 
 	cmd := &cobra.Command{
 		Use:   "promote",
 		Short: "Promote a project to given version",
-		Long: `Promote a project to given version in given environment.
+		Long: `Promote a project to given version in given environments.
 Typically called at the end of a CI pipeline to promote a new build to default target environment.
 
-Usage: joy build promote [--chart-version <chart-version>] <env> <project> <version>`,
-		Args: cobra.ExactArgs(3),
+Multiple environments can be specified as a comma-separated list. Releases of the project can be further
+restricted by name with --release or by metadata labels with --selector. With --dry-run, no files are written
+and a JSON report of the release files that would be changed is printed instead.
+
+Usage: joy build promote [flags] <env>[,<env>...] <project> <version>`,
+		Example: `  # Promote my-service to version 1.2.3 in staging
+  joy build promote staging my-service 1.2.3
+
+  # Promote only the my-service-worker release in staging and demo, pinning its image digest
+  joy build promote staging,demo my-service 1.2.3 --release my-service-worker --digest sha256:...
+
+  # Preview which releases labelled tier=backend would be promoted
+  joy build promote staging my-service 1.2.3 --selector tier=backend --dry-run`,
+		Args:              cobra.ExactArgs(3),
+		ValidArgsFunction: completeBuildPromoteArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			env := args[0]
+			envs := strings.Split(args[0], ",")
 			project := args[1]
 			version := args[2]
 
+			cfg := config.FromContext(cmd.Context())
 			cat := catalog.FromContext(cmd.Context())
-			cat.WithEnvironments([]string{env})
+			cat.WithEnvironments(envs)
+
+			if len(releases) > 0 {
+				cat.WithReleaseFilter(filtering.NewSpecificReleasesFilter(releases))
+			}
+			if selector != "" {
+				filter, err := filtering.NewLabelFilter(selector)
+				if err != nil {
+					return err
+				}
+				cat.WithReleaseFilter(filter)
+			}
 
 			return build.Promote(build.Opts{
 				Catalog:      cat,
-				Environment:  env,
+				CatalogDir:   cfg.CatalogDir,
+				Environments: envs,
 				Project:      project,
 				Version:      version,
 				Writer:       yml.DiskWriter,
+				Out:          cmd.OutOrStdout(),
 				ChartVersion: chartVersion,
+				Digest:       digest,
+				DryRun:       dryRun,
 			})
 		},
 	}
 
 	cmd.Flags().StringVar(&chartVersion, "chart-version", "", "(optional) Chart version to promote")
+	cmd.Flags().StringVar(&digest, "digest", "", "(optional) Image digest (sha256:...) to pin alongside version, any existing digest being removed otherwise")
+	cmd.Flags().StringSliceVarP(&releases, "release", "r", nil, "Only promote releases with given names")
+	cmd.Flags().StringVarP(&selector, "selector", "l", "", "Only promote releases matching given label selector (ie: key1=value1,key2=value2)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print JSON report of files that would be changed without writing them")
+
+	registerReleaseFlagCompletion(cmd, true, "release")
 
 	return cmd
 }
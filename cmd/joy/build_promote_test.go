@@ -17,9 +17,11 @@ import (
 func TestBuildPromote(t *testing.T) {
 	testCases := []struct {
 		name          string
+		envs          string
 		version       string
 		catalog       *catalog.Catalog
 		chartVersion  string
+		flags         map[string]string
 		expectedError string
 	}{
 		{
@@ -31,6 +33,28 @@ func TestBuildPromote(t *testing.T) {
 			version:      "2.3.4-rc1",
 			chartVersion: "5.6.7",
 		},
+		{
+			name:    "multiple_environments_with_selector_and_digest",
+			envs:    "staging,demo",
+			version: "2.3.4",
+			flags: map[string]string{
+				"selector": "tier=backend",
+				"digest":   "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+			},
+		},
+		{
+			name:    "existing_digest_without_digest_flag",
+			version: "2.3.4",
+		},
+		{
+			name:    "invalid_selector",
+			version: "2.3.4",
+			catalog: newBuildPromoteTestCatalog(t, newTestCatalogParams{}),
+			flags: map[string]string{
+				"selector": "tier",
+			},
+			expectedError: `invalid label selector "tier": expected key=value pairs`,
+		},
 		{
 			name:          "disallowed_pre_release",
 			version:       "2.3.4-rc1",
@@ -81,8 +105,12 @@ func TestBuildPromote(t *testing.T) {
 			cmd := NewBuildPromoteCmd()
 			cmd.SetOut(&buffer)
 			cmd.SetErr(&buffer)
+			envs := tc.envs
+			if envs == "" {
+				envs = "staging"
+			}
 			cmd.SetArgs([]string{
-				"staging",
+				envs,
 				"my-project",
 				tc.version,
 			})
@@ -90,6 +118,9 @@ func TestBuildPromote(t *testing.T) {
 				err := cmd.Flags().Set("chart-version", tc.chartVersion)
 				require.NoError(t, err)
 			}
+			for name, value := range tc.flags {
+				require.NoError(t, cmd.Flags().Set(name, value))
+			}
 
 			err := cmd.ExecuteContext(ctx)
 
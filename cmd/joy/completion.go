@@ -0,0 +1,183 @@
+package main
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/nestoca/joy/internal/completion"
+	"github.com/nestoca/joy/internal/config"
+)
+
+type completionFunc = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective)
+
+// loadCompletionIndex returns the cached completion index of catalog. Root pre-run hooks are not executed when
+// completing, so config is the one loaded upfront and catalog is not loaded at all. On failure, completion falls back
+// to no suggestions, as errors cannot be surfaced in the user's shell.
+func loadCompletionIndex(cmd *cobra.Command) (*completion.Index, *config.Config) {
+	cfg := config.FromContext(cmd.Context())
+	if cfg == nil {
+		return nil, nil
+	}
+	index, err := completion.LoadIndex(cmd.Context(), cfg.CatalogDir, cfg.JoyCache)
+	if err != nil {
+		cobra.CompDebugln(err.Error(), true)
+		return nil, nil
+	}
+	return index, cfg
+}
+
+// isCompletionCmd returns true for the hidden command serving completion requests from shells and for the commands
+// generating completion scripts, neither of which must prompt, pull or load the catalog.
+func isCompletionCmd(cmd *cobra.Command) bool {
+	if cmd.Name() == cobra.ShellCompRequestCmd || cmd.Name() == cobra.ShellCompNoDescRequestCmd {
+		return true
+	}
+	return cmd.HasParent() && cmd.Parent().Name() == "completion"
+}
+
+// completeArgs completes up to maxArgs positional arguments, or any number of them if maxArgs is negative, with
+// values returned by given function, excluding those already provided.
+func completeArgs(maxArgs int, values func(cmd *cobra.Command, index *completion.Index) []string) completionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if maxArgs >= 0 && len(args) >= maxArgs {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		index, _ := loadCompletionIndex(cmd)
+		if index == nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return completion.Filter(values(cmd, index), toComplete, args...), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeFlag completes flag values with values returned by given function, possibly comma-separated.
+func completeFlag(commaSeparated bool, values func(cmd *cobra.Command, index *completion.Index) []string) completionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		index, _ := loadCompletionIndex(cmd)
+		if index == nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		if commaSeparated {
+			return completion.FilterCommaSeparated(values(cmd, index), toComplete), cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+		}
+		return completion.Filter(values(cmd, index), toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+func environmentNames(_ *cobra.Command, index *completion.Index) []string {
+	return index.EnvironmentNames()
+}
+
+func projectNames(_ *cobra.Command, index *completion.Index) []string {
+	return index.ProjectNames()
+}
+
+// releaseNames returns the names of releases in environments already specified via the --env, --source or --from
+// flags, if any, or in all environments otherwise.
+func releaseNames(cmd *cobra.Command, index *completion.Index) []string {
+	return index.ReleaseNames(getFlagEnvironments(cmd)...)
+}
+
+func completeEnvironments(maxArgs int) completionFunc {
+	return completeArgs(maxArgs, environmentNames)
+}
+
+func completeProjects(maxArgs int) completionFunc {
+	return completeArgs(maxArgs, projectNames)
+}
+
+func completeReleases(maxArgs int) completionFunc {
+	return completeArgs(maxArgs, releaseNames)
+}
+
+// completeEnvironmentLink completes "[environment] [link]" arguments.
+func completeEnvironmentLink(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return completeEnvironments(1)(cmd, args, toComplete)
+	}
+	return completeLink(cmd, args, toComplete, func(index *completion.Index, cfg *config.Config) []string {
+		return index.EnvironmentLinkNames(cfg.Templates)
+	})
+}
+
+// completeProjectLink completes "[project] [link]" arguments.
+func completeProjectLink(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return completeProjects(1)(cmd, args, toComplete)
+	}
+	return completeLink(cmd, args, toComplete, func(index *completion.Index, cfg *config.Config) []string {
+		return index.ProjectLinkNames(cfg.Templates, args[0])
+	})
+}
+
+// completeReleaseLink completes "[release] [link]" arguments.
+func completeReleaseLink(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return completeReleases(1)(cmd, args, toComplete)
+	}
+	return completeLink(cmd, args, toComplete, func(index *completion.Index, cfg *config.Config) []string {
+		return index.ReleaseLinkNames(cfg.Templates, args[0], getFlagEnvironments(cmd)...)
+	})
+}
+
+func completeLink(cmd *cobra.Command, args []string, toComplete string, names func(*completion.Index, *config.Config) []string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 1 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	index, cfg := loadCompletionIndex(cmd)
+	if index == nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return completion.Filter(names(index, cfg), toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeBuildPromoteArgs completes the "<env>[,<env>...] <project> <version>" arguments of build promote.
+func completeBuildPromoteArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	switch len(args) {
+	case 0:
+		return completeFlag(true, environmentNames)(cmd, args, toComplete)
+	case 1:
+		return completeArgs(-1, projectNames)(cmd, args, toComplete)
+	default:
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// getFlagEnvironments returns the environments already specified via the --env, --source or --from flags of command,
+// either as a slice flag or as comma-separated values.
+func getFlagEnvironments(cmd *cobra.Command) []string {
+	for _, name := range []string{"env", "source", "from"} {
+		flag := cmd.Flags().Lookup(name)
+		if flag == nil || !flag.Changed {
+			continue
+		}
+		if value, ok := flag.Value.(pflag.SliceValue); ok {
+			return value.GetSlice()
+		}
+		return slices.DeleteFunc(strings.Split(flag.Value.String(), ","), func(env string) bool { return env == "" })
+	}
+	return nil
+}
+
+// registerEnvironmentFlagCompletion registers completion of environment names for given flags of command.
+func registerEnvironmentFlagCompletion(cmd *cobra.Command, commaSeparated bool, flags ...string) {
+	for _, flag := range flags {
+		mustRegisterFlagCompletion(cmd, flag, completeFlag(commaSeparated, environmentNames))
+	}
+}
+
+// registerReleaseFlagCompletion registers completion of release names for given flags of command.
+func registerReleaseFlagCompletion(cmd *cobra.Command, commaSeparated bool, flags ...string) {
+	for _, flag := range flags {
+		mustRegisterFlagCompletion(cmd, flag, completeFlag(commaSeparated, releaseNames))
+	}
+}
+
+func mustRegisterFlagCompletion(cmd *cobra.Command, flag string, fn completionFunc) {
+	if err := cmd.RegisterFlagCompletionFunc(flag, fn); err != nil {
+		panic(err)
+	}
+}
@@ -1,16 +1,21 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/pkg/browser"
 	"github.com/spf13/cobra"
 
 	"github.com/nestoca/joy/api/v1alpha1"
+	"github.com/nestoca/joy/internal"
 	"github.com/nestoca/joy/internal/config"
 	"github.com/nestoca/joy/internal/environment"
+	"github.com/nestoca/joy/internal/environment/preview"
 	"github.com/nestoca/joy/internal/info"
 	"github.com/nestoca/joy/internal/links"
+	"github.com/nestoca/joy/internal/yml"
 	"github.com/nestoca/joy/pkg/catalog"
 )
 
@@ -22,10 +27,137 @@ func NewEnvironmentCmd(preRunConfigs PreRunConfigs) *cobra.Command {
 		Long:    `Manage environments, such as listing and selecting them.`,
 		GroupID: "core",
 	}
+	cmd.AddCommand(NewEnvironmentListCmd(preRunConfigs))
+	cmd.AddCommand(NewEnvironmentShowCmd(preRunConfigs))
+	cmd.AddCommand(NewEnvironmentDiffCmd(preRunConfigs))
 	cmd.AddCommand(NewEnvironmentSelectCmd(preRunConfigs))
 	cmd.AddCommand(NewEnvironmentLinksCmd())
 	cmd.AddCommand(NewEnvironmentOpenCmd())
 	cmd.AddCommand(NewEnvironmentSchemaCmd())
+	cmd.AddCommand(NewEnvironmentPreviewCmd())
+	return cmd
+}
+
+func NewEnvironmentListCmd(preRunConfigs PreRunConfigs) *cobra.Command {
+	var jsonOutput bool
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List environments",
+		Long:    `List environments, with their cluster, namespace, order, promotion sources, owners and number of releases.`,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cat := catalog.FromContext(cmd.Context())
+			summaries := environment.GetSummaries(cat)
+
+			if jsonOutput {
+				output, err := json.MarshalIndent(summaries, "", "  ")
+				if err != nil {
+					return fmt.Errorf("marshalling environments as JSON: %w", err)
+				}
+				_, err = fmt.Fprintln(cmd.OutOrStdout(), string(output))
+				return err
+			}
+
+			environment.FormatSummaries(cmd.OutOrStdout(), summaries)
+			return nil
+		},
+	}
+	cmd.Flags().BoolVarP(&jsonOutput, "json", "j", false, "Output as JSON")
+
+	preRunConfigs.PullCatalog(cmd)
+
+	return cmd
+}
+
+func NewEnvironmentShowCmd(preRunConfigs PreRunConfigs) *cobra.Command {
+	var jsonOutput bool
+	cmd := &cobra.Command{
+		Use:   "show <environment>",
+		Short: "Show environment details",
+		Long: `Show environment details, such as its cluster, namespace, order, promotion sources, owners, chart version
+overrides and number of releases.`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeEnvironments(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cat := catalog.FromContext(cmd.Context())
+			summary, err := environment.GetSummary(cat, args[0])
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				output, err := json.MarshalIndent(summary, "", "  ")
+				if err != nil {
+					return fmt.Errorf("marshalling environment as JSON: %w", err)
+				}
+				_, err = fmt.Fprintln(cmd.OutOrStdout(), string(output))
+				return err
+			}
+
+			environment.FormatSummary(cmd.OutOrStdout(), summary)
+			return nil
+		},
+	}
+	cmd.Flags().BoolVarP(&jsonOutput, "json", "j", false, "Output as JSON")
+
+	preRunConfigs.PullCatalog(cmd)
+
+	return cmd
+}
+
+func NewEnvironmentDiffCmd(preRunConfigs PreRunConfigs) *cobra.Command {
+	var jsonOutput, exitCode bool
+	cmd := &cobra.Command{
+		Use:   "diff <source> <target>",
+		Short: "Compare two environments side by side",
+		Long: `Compare two environments side by side.
+
+Reports releases missing on either side, as well as differences in release versions, release charts (including
+environment chart version overrides) and environment-level values.`,
+		Example: `  # Compare staging and production environments
+  joy env diff staging prod
+
+  # Fail when new environment does not match old one, such as in CI
+  joy env diff prod prod-east --exit-code`,
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: completeEnvironments(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := config.FromContext(cmd.Context())
+			cat := catalog.FromContext(cmd.Context())
+
+			diff, err := environment.Compare(cat, environment.DiffParams{
+				Source: args[0],
+				Target: args[1],
+				Charts: newChartCache(cfg, internal.IO{Out: cmd.ErrOrStderr(), Err: cmd.ErrOrStderr()}),
+			})
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				output, err := json.MarshalIndent(diff, "", "  ")
+				if err != nil {
+					return fmt.Errorf("marshalling environment diff as JSON: %w", err)
+				}
+				if _, err := fmt.Fprintln(cmd.OutOrStdout(), string(output)); err != nil {
+					return err
+				}
+			} else {
+				environment.FormatDiff(cmd.OutOrStdout(), diff)
+			}
+
+			if exitCode && !diff.IsEmpty() {
+				return fmt.Errorf("environments %s and %s differ", diff.Source, diff.Target)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVarP(&jsonOutput, "json", "j", false, "Output as JSON")
+	cmd.Flags().BoolVar(&exitCode, "exit-code", false, "Exit with an error when environments differ")
+
+	preRunConfigs.PullCatalog(cmd)
+
 	return cmd
 }
 
@@ -53,10 +185,11 @@ Only selected environments will be included in releases table columns.`,
 
 func NewEnvironmentOpenCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:     "open [flags] [environment] [link]",
-		Aliases: []string{"open", "o"},
-		Short:   "Open environment link",
-		Args:    cobra.RangeArgs(0, 2),
+		Use:               "open [flags] [environment] [link]",
+		Aliases:           []string{"open", "o"},
+		Short:             "Open environment link",
+		Args:              cobra.RangeArgs(0, 2),
+		ValidArgsFunction: completeEnvironmentLink,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cfg := config.FromContext(cmd.Context())
 			cat := catalog.FromContext(cmd.Context())
@@ -71,7 +204,7 @@ func NewEnvironmentOpenCmd() *cobra.Command {
 			}
 
 			infoProvider := info.NewProvider(cfg.GitHubOrganization, cfg.Templates.Project.GitTag, cfg.RepositoriesDir, cfg.JoyCache)
-			linksProvider := links.NewProvider(infoProvider, cfg.Templates)
+			linksProvider := links.NewProvider(infoProvider, cfg.Templates, newChartCache(cfg, internal.IO{Out: cmd.ErrOrStderr(), Err: cmd.ErrOrStderr()}))
 
 			envLinks, err := links.GetEnvironmentLinks(linksProvider, cat, envName)
 			if err != nil {
@@ -92,10 +225,11 @@ func NewEnvironmentOpenCmd() *cobra.Command {
 
 func NewEnvironmentLinksCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:     "links [flags] [environment] [link]",
-		Aliases: []string{"links", "link", "lnk"},
-		Short:   "List environment links",
-		Args:    cobra.RangeArgs(0, 2),
+		Use:               "links [flags] [environment] [link]",
+		Aliases:           []string{"links", "link", "lnk"},
+		Short:             "List environment links",
+		Args:              cobra.RangeArgs(0, 2),
+		ValidArgsFunction: completeEnvironmentLink,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cfg := config.FromContext(cmd.Context())
 			cat := catalog.FromContext(cmd.Context())
@@ -111,7 +245,7 @@ func NewEnvironmentLinksCmd() *cobra.Command {
 			}
 
 			infoProvider := info.NewProvider(cfg.GitHubOrganization, cfg.Templates.Project.GitTag, cfg.RepositoriesDir, cfg.JoyCache)
-			linksProvider := links.NewProvider(infoProvider, cfg.Templates)
+			linksProvider := links.NewProvider(infoProvider, cfg.Templates, newChartCache(cfg, internal.IO{Out: cmd.ErrOrStderr(), Err: cmd.ErrOrStderr()}))
 
 			envLinks, err := links.GetEnvironmentLinks(linksProvider, cat, envName)
 			if err != nil {
@@ -140,3 +274,115 @@ func NewEnvironmentSchemaCmd() *cobra.Command {
 		},
 	}
 }
+
+func NewEnvironmentPreviewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "preview",
+		Aliases: []string{"previews", "pv"},
+		Short:   "Manage ephemeral pull request preview environments",
+		Long: `Manage ephemeral pull request preview environments.
+
+A preview environment is a short-lived copy of a source environment, with its own namespace, containing only
+the chosen releases set to the pull request build version. It is named <source>-pr-<number> and lives next to
+the source environment directory.`,
+	}
+	cmd.AddCommand(NewEnvironmentPreviewCreateCmd())
+	cmd.AddCommand(NewEnvironmentPreviewDestroyCmd())
+	cmd.AddCommand(NewEnvironmentPreviewGCCmd())
+	return cmd
+}
+
+func NewEnvironmentPreviewCreateCmd() *cobra.Command {
+	var (
+		pullRequest int
+		source      string
+		releases    []string
+		version     string
+		owners      []string
+		ttl         time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create preview environment for pull request",
+		Example: `  # Preview pull request 123 build of my-service, copied from dev, for 3 days
+  joy env preview create --pr 123 --from dev --release my-service --version 1.2.3-pr123 --ttl 72h`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			_, err := preview.Create(preview.CreateOpts{
+				Catalog:     catalog.FromContext(cmd.Context()),
+				Writer:      yml.DiskWriter,
+				Out:         cmd.OutOrStdout(),
+				PullRequest: pullRequest,
+				Source:      source,
+				Releases:    releases,
+				Version:     version,
+				Owners:      owners,
+				TTL:         ttl,
+			})
+			return err
+		},
+	}
+
+	cmd.Flags().IntVar(&pullRequest, "pr", 0, "Number of pull request to preview")
+	cmd.Flags().StringVar(&source, "from", "", "Environment to copy releases from")
+	cmd.Flags().StringSliceVarP(&releases, "release", "r", nil, "Releases to copy into preview environment")
+	cmd.Flags().StringVar(&version, "version", "", "Pull request build version to set on copied releases")
+	cmd.Flags().StringSliceVar(&owners, "owner", nil, "(optional) Owners of preview environment, such as pull request author, instead of none")
+	cmd.Flags().DurationVar(&ttl, "ttl", 0, "(optional) Duration after which preview environment expires and gets removed by gc")
+
+	registerEnvironmentFlagCompletion(cmd, false, "from")
+	registerReleaseFlagCompletion(cmd, true, "release")
+
+	_ = cmd.MarkFlagRequired("pr")
+	_ = cmd.MarkFlagRequired("from")
+	_ = cmd.MarkFlagRequired("release")
+	_ = cmd.MarkFlagRequired("version")
+
+	return cmd
+}
+
+func NewEnvironmentPreviewDestroyCmd() *cobra.Command {
+	var (
+		pullRequest int
+		source      string
+	)
+
+	cmd := &cobra.Command{
+		Use:     "destroy",
+		Aliases: []string{"delete", "rm"},
+		Short:   "Destroy preview environments of pull request",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return preview.Destroy(preview.DestroyOpts{
+				Catalog:     catalog.FromContext(cmd.Context()),
+				Out:         cmd.OutOrStdout(),
+				PullRequest: pullRequest,
+				Source:      source,
+			})
+		},
+	}
+
+	cmd.Flags().IntVar(&pullRequest, "pr", 0, "Number of pull request whose preview environments to destroy")
+	cmd.Flags().StringVar(&source, "from", "", "(optional) Only destroy preview environment created from given environment")
+	registerEnvironmentFlagCompletion(cmd, false, "from")
+
+	_ = cmd.MarkFlagRequired("pr")
+
+	return cmd
+}
+
+func NewEnvironmentPreviewGCCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "gc",
+		Short: "Destroy expired preview environments",
+		Long:  `Destroy all preview environments whose TTL has expired.`,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return preview.GC(preview.GCOpts{
+				Catalog: catalog.FromContext(cmd.Context()),
+				Out:     cmd.OutOrStdout(),
+			})
+		},
+	}
+}
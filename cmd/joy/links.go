@@ -0,0 +1,87 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/nestoca/joy/internal"
+	"github.com/nestoca/joy/internal/config"
+	"github.com/nestoca/joy/internal/info"
+	"github.com/nestoca/joy/internal/links"
+	"github.com/nestoca/joy/pkg/catalog"
+)
+
+func NewLinksCmd(preRunConfigs PreRunConfigs) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "links",
+		Short:   "Manage links of environments, projects and releases",
+		GroupID: "core",
+	}
+	cmd.AddCommand(NewLinksCheckCmd(preRunConfigs))
+	return cmd
+}
+
+func NewLinksCheckCmd(preRunConfigs PreRunConfigs) *cobra.Command {
+	var (
+		checkHTTP  bool
+		jsonOutput bool
+		opts       links.CheckOpts
+	)
+	cmd := &cobra.Command{
+		Use:   "check",
+		Short: "Check links of all environments, projects and releases",
+		Long: `Check links of all environments, projects and releases, reporting link templates that fail to render.
+
+Use --http to also make a HEAD request to every distinct http(s) link, reporting those that cannot be reached or that
+respond with an error status. Requests are made concurrently, each bounded by a timeout.`,
+		Example: `  # Check that all link templates render
+  joy links check
+
+  # Also check that all links can be reached
+  joy links check --http --concurrency 16 --timeout 5s`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := config.FromContext(cmd.Context())
+			cat := catalog.FromContext(cmd.Context())
+
+			infoProvider := info.NewProvider(cfg.GitHubOrganization, cfg.Templates.Project.GitTag, cfg.RepositoriesDir, cfg.JoyCache)
+
+			opts.Catalog = cat
+			opts.Provider = links.NewProvider(infoProvider, cfg.Templates, newChartCache(cfg, internal.IO{Out: cmd.ErrOrStderr(), Err: cmd.ErrOrStderr()}))
+			if checkHTTP {
+				opts.Client = http.DefaultClient
+			}
+
+			report := links.Check(cmd.Context(), opts)
+
+			if jsonOutput {
+				output, err := json.MarshalIndent(report, "", "  ")
+				if err != nil {
+					return fmt.Errorf("marshalling report as JSON: %w", err)
+				}
+				if _, err := fmt.Fprintln(cmd.OutOrStdout(), string(output)); err != nil {
+					return err
+				}
+			} else {
+				links.FormatCheckReport(cmd.OutOrStdout(), report)
+			}
+
+			if len(report.Failures) > 0 {
+				return fmt.Errorf("found %d link failures", len(report.Failures))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&checkHTTP, "http", false, "Make a HEAD request to every http(s) link")
+	cmd.Flags().IntVar(&opts.Concurrency, "concurrency", 8, "Maximum number of concurrent requests")
+	cmd.Flags().DurationVar(&opts.Timeout, "timeout", 10*time.Second, "Timeout of each request")
+	cmd.Flags().BoolVarP(&jsonOutput, "json", "j", false, "Output as JSON")
+
+	preRunConfigs.PullCatalog(cmd)
+
+	return cmd
+}
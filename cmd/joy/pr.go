@@ -1,9 +1,13 @@
 package main
 
 import (
+	"time"
+
 	"github.com/spf13/cobra"
 
+	"github.com/nestoca/joy/internal/config"
 	"github.com/nestoca/joy/internal/pr/promote"
+	"github.com/nestoca/joy/internal/pr/status"
 	"github.com/nestoca/joy/pkg/catalog"
 )
 
@@ -15,17 +19,24 @@ func NewPRCmd() *cobra.Command {
 		GroupID: "core",
 	}
 	cmd.AddCommand(NewPRPromoteCmd())
+	cmd.AddCommand(NewPRListCmd())
+	cmd.AddCommand(NewPRStatusCmd())
 	return cmd
 }
 
 func NewPRPromoteCmd() *cobra.Command {
-	var noPrompt, disable bool
+	var noPrompt, disable, disableAll bool
 	var targetEnv string
 	cmd := cobra.Command{
 		Use:     "promote",
 		Aliases: []string{"prom"},
 		Short:   "Auto-promote builds of pull request to given environment",
-		Args:    cobra.NoArgs,
+		Example: `  # Auto-promote builds of current branch's pull request to staging
+  joy pr promote --target staging
+
+  # Disable auto-promotion of all pull requests to staging, to free it up
+  joy pr promote --disable-all --target staging`,
+		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cat := catalog.FromContext(cmd.Context())
 
@@ -35,6 +46,7 @@ func NewPRPromoteCmd() *cobra.Command {
 					Environments: cat.Environments,
 					TargetEnv:    targetEnv,
 					Disable:      disable,
+					DisableAll:   disableAll,
 					NoPrompt:     noPrompt,
 				})
 		},
@@ -42,8 +54,78 @@ func NewPRPromoteCmd() *cobra.Command {
 
 	cmd.Flags().BoolVar(&noPrompt, "no-prompt", false, "Do not prompt user for anything")
 	cmd.Flags().StringVarP(&targetEnv, "target", "t", "", "Environment to auto-promote builds of pull request to")
+	registerEnvironmentFlagCompletion(&cmd, false, "target")
 	cmd.Flags().BoolVar(&disable, "disable", false, "Disable auto-promotion")
+	cmd.Flags().BoolVar(&disableAll, "disable-all", false, "Disable auto-promotion of all pull requests to target environment")
 	cmd.MarkFlagsMutuallyExclusive("target", "disable")
+	cmd.MarkFlagsMutuallyExclusive("disable", "disable-all")
 
 	return &cmd
 }
+
+func NewPRListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List pull requests auto-promoting to each environment",
+		Long:    `List branches whose pull requests are configured to auto-promote their builds, grouped by target environment.`,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cat := catalog.FromContext(cmd.Context())
+			return promote.
+				NewDefaultPromotion(".", cmd.OutOrStdout()).
+				List(cat.Environments)
+		},
+	}
+}
+
+func NewPRStatusCmd() *cobra.Command {
+	var params status.Params
+	cmd := &cobra.Command{
+		Use:     "status [number-or-url...]",
+		Aliases: []string{"st"},
+		Short:   "Show status of release promotion pull requests",
+		Long: `Show status of release promotion pull requests, including their checks, review state and auto-merge state.
+
+By default, all open pull requests created by "joy release promote" are listed, as identified by their environment:
+and release: labels. Specific pull requests can also be given by number or URL, as printed by "joy release promote".
+
+With --wait, blocks until all pull requests are either merged, closed or failing their checks, and exits with an
+error if any of them did not merge, which is useful for CI pipelines that need to know when a promotion has landed.`,
+		Example: `  # List all open promotion pull requests
+  joy pr status
+
+  # List open promotion pull requests to prod for release my-service
+  joy pr status --env prod --release my-service
+
+  # Wait for a promotion pull request to merge
+  joy pr status https://github.com/acme/catalog/pull/123 --wait --timeout 20m`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := config.FromContext(cmd.Context())
+
+			for _, arg := range args {
+				number, err := status.ParseNumber(arg)
+				if err != nil {
+					return err
+				}
+				params.Numbers = append(params.Numbers, number)
+			}
+
+			return status.
+				NewTracker(newCatalogPullRequestProvider(cfg), cmd.OutOrStdout()).
+				Run(params)
+		},
+	}
+
+	cmd.Flags().StringVarP(&params.Environment, "env", "e", "", "Only show pull requests promoting to given environment")
+	cmd.Flags().StringSliceVarP(&params.Releases, "release", "r", nil, "Only show pull requests promoting given releases")
+	cmd.Flags().BoolVarP(&params.Wait, "wait", "w", false, "Wait until pull requests are merged or fail")
+	cmd.Flags().DurationVar(&params.Interval, "interval", 15*time.Second, "Interval between status checks when waiting")
+	cmd.Flags().DurationVar(&params.Timeout, "timeout", 30*time.Minute, "Maximum duration to wait for, or 0 to wait indefinitely")
+	cmd.Flags().BoolVar(&params.JSON, "json", false, "Output statuses as JSON")
+
+	registerEnvironmentFlagCompletion(cmd, false, "env")
+	registerReleaseFlagCompletion(cmd, true, "release")
+
+	return cmd
+}
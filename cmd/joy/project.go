@@ -1,12 +1,14 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/pkg/browser"
 	"github.com/spf13/cobra"
 
 	"github.com/nestoca/joy/api/v1alpha1"
+	"github.com/nestoca/joy/internal"
 	"github.com/nestoca/joy/internal/config"
 	"github.com/nestoca/joy/internal/info"
 	"github.com/nestoca/joy/internal/links"
@@ -23,6 +25,7 @@ func NewProjectCmd(preRunConfigs PreRunConfigs) *cobra.Command {
 		GroupID: "core",
 	}
 	cmd.AddCommand(NewProjectListCmd(preRunConfigs))
+	cmd.AddCommand(NewProjectShowCmd(preRunConfigs))
 	cmd.AddCommand(NewProjectOpenCmd())
 	cmd.AddCommand(NewProjectLinksCmd())
 	cmd.AddCommand(NewProjectSchemaCmd())
@@ -46,12 +49,51 @@ func NewProjectListCmd(preRunConfigs PreRunConfigs) *cobra.Command {
 	return cmd
 }
 
+func NewProjectShowCmd(preRunConfigs PreRunConfigs) *cobra.Command {
+	var jsonOutput bool
+	cmd := &cobra.Command{
+		Use:   "show <project>",
+		Short: "Show project details and its releases across environments",
+		Long: `Show project details, such as its owners, reviewers and repository, along with the versions of its releases
+across environments.`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeProjects(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := config.FromContext(cmd.Context())
+			cat := catalog.FromContext(cmd.Context())
+			infoProvider := info.NewProvider(cfg.GitHubOrganization, cfg.Templates.Project.GitTag, cfg.RepositoriesDir, cfg.JoyCache)
+			details, err := project.GetDetails(cat, infoProvider, args[0])
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				output, err := json.MarshalIndent(details, "", "  ")
+				if err != nil {
+					return fmt.Errorf("marshalling project as JSON: %w", err)
+				}
+				_, err = fmt.Fprintln(cmd.OutOrStdout(), string(output))
+				return err
+			}
+
+			project.FormatDetails(cmd.OutOrStdout(), details)
+			return nil
+		},
+	}
+	cmd.Flags().BoolVarP(&jsonOutput, "json", "j", false, "Output as JSON")
+
+	preRunConfigs.PullCatalog(cmd)
+
+	return cmd
+}
+
 func NewProjectOpenCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:     "open [flags] [project] [link]",
-		Aliases: []string{"open", "o"},
-		Short:   "Open project link",
-		Args:    cobra.RangeArgs(0, 2),
+		Use:               "open [flags] [project] [link]",
+		Aliases:           []string{"open", "o"},
+		Short:             "Open project link",
+		Args:              cobra.RangeArgs(0, 2),
+		ValidArgsFunction: completeProjectLink,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cfg := config.FromContext(cmd.Context())
 			cat := catalog.FromContext(cmd.Context())
@@ -67,7 +109,7 @@ func NewProjectOpenCmd() *cobra.Command {
 			}
 
 			infoProvider := info.NewProvider(cfg.GitHubOrganization, cfg.Templates.Project.GitTag, cfg.RepositoriesDir, cfg.JoyCache)
-			linksProvider := links.NewProvider(infoProvider, cfg.Templates)
+			linksProvider := links.NewProvider(infoProvider, cfg.Templates, newChartCache(cfg, internal.IO{Out: cmd.ErrOrStderr(), Err: cmd.ErrOrStderr()}))
 
 			projectLinks, err := links.GetProjectLinks(linksProvider, cat, projectName)
 			if err != nil {
@@ -88,10 +130,11 @@ func NewProjectOpenCmd() *cobra.Command {
 
 func NewProjectLinksCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:     "links [flags] [project] [link]",
-		Aliases: []string{"links", "link", "lnk"},
-		Short:   "List project links",
-		Args:    cobra.RangeArgs(0, 2),
+		Use:               "links [flags] [project] [link]",
+		Aliases:           []string{"links", "link", "lnk"},
+		Short:             "List project links",
+		Args:              cobra.RangeArgs(0, 2),
+		ValidArgsFunction: completeProjectLink,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cfg := config.FromContext(cmd.Context())
 			cat := catalog.FromContext(cmd.Context())
@@ -107,7 +150,7 @@ func NewProjectLinksCmd() *cobra.Command {
 			}
 
 			infoProvider := info.NewProvider(cfg.GitHubOrganization, cfg.Templates.Project.GitTag, cfg.RepositoriesDir, cfg.JoyCache)
-			linksProvider := links.NewProvider(infoProvider, cfg.Templates)
+			linksProvider := links.NewProvider(infoProvider, cfg.Templates, newChartCache(cfg, internal.IO{Out: cmd.ErrOrStderr(), Err: cmd.ErrOrStderr()}))
 
 			projectLinks, err := links.GetProjectLinks(linksProvider, cat, projectName)
 			if err != nil {
@@ -2,6 +2,7 @@ package main
 
 import (
 	"cmp"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -20,10 +21,13 @@ import (
 
 	"github.com/nestoca/joy/api/v1alpha1"
 	"github.com/nestoca/joy/internal"
+	"github.com/nestoca/joy/internal/changelog"
 	"github.com/nestoca/joy/internal/config"
 	"github.com/nestoca/joy/internal/git"
 	"github.com/nestoca/joy/internal/git/pr"
+	"github.com/nestoca/joy/internal/gitea"
 	"github.com/nestoca/joy/internal/github"
+	"github.com/nestoca/joy/internal/gitlab"
 	"github.com/nestoca/joy/internal/info"
 	"github.com/nestoca/joy/internal/links"
 	"github.com/nestoca/joy/internal/release"
@@ -54,20 +58,37 @@ func NewReleaseCmd(preRunConfigs PreRunConfigs) *cobra.Command {
 	cmd.AddCommand(NewReleaseLinksCmd())
 	cmd.AddCommand(NewReleaseSchemaCmd())
 	cmd.AddCommand(NewGitCommands())
+	cmd.AddCommand(NewReleaseChangelogCmd())
 	cmd.AddCommand(NewValidateCommand())
 
 	return cmd
 }
 
 func NewReleaseListCmd(preRunConfigs PreRunConfigs) *cobra.Command {
-	var releases, envs, owners string
-	var narrow, wide bool
+	var releases, envs, owners, format string
+	var narrow, wide, tree, showDrift, commits bool
 	var jsonOutput bool
+	var columns []string
 	cmd := &cobra.Command{
-		Use:     "list",
-		Aliases: []string{"ls", "l"},
-		Args:    cobra.RangeArgs(0, 1),
-		Short:   "List releases across environments",
+		Use:               "list",
+		Aliases:           []string{"ls", "l"},
+		Args:              cobra.RangeArgs(0, 1),
+		ValidArgsFunction: completeReleases(1),
+		Short:             "List releases across environments",
+		Example: `  # List releases as a markdown table with chart version and namespace of each environment
+  joy release list --format markdown --columns version,chart-version,namespace
+
+  # List releases, marking those whose values drift from the reference environment
+  joy release list --show-drift
+
+  # List releases with how far each version is behind the reference environment, including commit counts
+  joy release list --wide --commits
+
+  # List releases as JSON, including version distance and when each version last changed in catalog
+  joy release list --json
+
+  # List releases with a custom Go template
+  joy release list --format '{{.Name}} {{range .Releases}}{{.DisplayVersion}} {{end}}'`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cfg := config.FromContext(cmd.Context())
 			cat := catalog.FromContext(cmd.Context())
@@ -76,6 +97,21 @@ func NewReleaseListCmd(preRunConfigs PreRunConfigs) *cobra.Command {
 				return fmt.Errorf("--releases flag no longer supported, please specify comma-delimited list of releases as first positional argument")
 			}
 
+			if jsonOutput {
+				format = list.JSONFormat
+			}
+			if tree && (format == list.JSONFormat || format == list.YAMLFormat) {
+				return fmt.Errorf("--tree cannot be combined with %s format", format)
+			}
+
+			if wide && len(columns) == 0 {
+				columns = []string{string(list.VersionColumn), string(list.DistanceColumn)}
+			}
+			selectedColumns, err := list.ParseColumns(columns)
+			if err != nil {
+				return err
+			}
+
 			if len(args) > 0 {
 				releasePattern := args[0]
 				cat.WithReleaseFilter(filtering.NewNamePatternFilter(releasePattern))
@@ -93,25 +129,41 @@ func NewReleaseListCmd(preRunConfigs PreRunConfigs) *cobra.Command {
 				cat.WithReleaseFilter(filtering.NewOwnerFilter(owners))
 			}
 
-			releaseList, err := list.GetReleaseList(cat, list.Params{
+			params := list.Params{
 				SelectedEnvs:         selectedEnvs,
 				ReferenceEnvironment: cfg.ReferenceEnvironment,
-			})
+				Charts:               newChartCache(cfg, internal.IO{Out: cmd.ErrOrStderr(), Err: cmd.ErrOrStderr()}),
+				Drift:                showDrift,
+			}
+			// Determining staleness requires going through the git history of each release, so only do it when shown,
+			// which JSON and YAML formats always do regardless of columns
+			if slices.Contains(selectedColumns, list.DistanceColumn) || format == list.JSONFormat || format == list.YAMLFormat {
+				params.CatalogDir = cfg.CatalogDir
+			}
+			if commits {
+				params.Info = info.NewProvider(cfg.GitHubOrganization, cfg.Templates.Project.GitTag, cfg.RepositoriesDir, cfg.JoyCache)
+			}
+
+			releaseList, err := list.GetReleaseList(cat, params)
 			if err != nil {
 				return fmt.Errorf("getting release list: %w", err)
 			}
 
-			if jsonOutput {
-				output, err := list.FormatReleaseListAsJson(releaseList)
-				if err != nil {
-					return fmt.Errorf("formatting release list as JSON: %w", err)
-				}
-				fmt.Println(output)
-				return nil
+			if tree {
+				releaseList = list.AsDependencyTree(releaseList)
 			}
 
-			output := list.FormatReleaseListAsTable(releaseList, cfg.ReferenceEnvironment, cfg.ColumnWidths.Get(narrow, wide))
-			fmt.Println(output)
+			output, err := list.FormatReleaseList(releaseList, list.FormatOpts{
+				Format:               format,
+				Columns:              selectedColumns,
+				ShowDrift:            showDrift,
+				ReferenceEnvironment: cfg.ReferenceEnvironment,
+				MaxColumnWidth:       cfg.ColumnWidths.Get(narrow, wide),
+			})
+			if err != nil {
+				return fmt.Errorf("formatting release list: %w", err)
+			}
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), output)
 			return nil
 		},
 	}
@@ -120,8 +172,18 @@ func NewReleaseListCmd(preRunConfigs PreRunConfigs) *cobra.Command {
 	cmd.Flags().StringVarP(&owners, "owners", "o", "", "List releases by owners (comma-separated, defaults to all)")
 	cmd.Flags().BoolVarP(&narrow, "narrow", "n", false, "Use narrow columns mode")
 	cmd.Flags().BoolVarP(&wide, "wide", "w", false, "Use wide columns mode")
-	cmd.Flags().BoolVarP(&jsonOutput, "json", "j", false, "Output as JSON")
+	cmd.Flags().BoolVarP(&jsonOutput, "json", "j", false, "Output as JSON (same as --format json)")
+	cmd.Flags().StringVarP(&format, "format", "f", list.TableFormat, "Output format: table, json, yaml, csv, markdown, or a Go template rendered for each release")
+	cmd.Flags().StringSliceVarP(&columns, "columns", "c", nil, "Columns to show for each environment: version, chart-version, namespace, digest, project, distance (comma-separated, defaults to version, or version and distance in wide mode)")
+	cmd.Flags().BoolVarP(&tree, "tree", "t", false, "Show releases as a tree of their dependencies")
+	cmd.Flags().BoolVar(&commits, "commits", false, "Count commits between git tags of each release and of the reference environment, which requires cloning project repositories")
+	cmd.Flags().BoolVar(&showDrift, "show-drift", false, "Mark versions of releases whose values drift from the reference environment, disregarding !lock and !local values")
+
+	registerEnvironmentFlagCompletion(cmd, true, "env")
+	registerReleaseFlagCompletion(cmd, true, "releases")
 	cmd.MarkFlagsMutuallyExclusive("narrow", "wide")
+	cmd.MarkFlagsMutuallyExclusive("json", "format")
+	cmd.MarkFlagsMutuallyExclusive("json", "tree")
 
 	preRunConfigs.PullCatalog(cmd)
 
@@ -142,13 +204,15 @@ func NewReleasePromoteCmd(params PromoteParams) *cobra.Command {
 	var sourceEnv, targetEnv string
 	var autoMerge, draft, dryRun, localOnly, noPrompt, narrow, wide bool
 	var all, keepPrerelease bool
-	var omit []string
+	var versionOnly, valuesOnly, supersede, split, ignoreDependencies bool
+	var omit, paths []string
 	var templateVars []string
 
 	cmd := &cobra.Command{
-		Use:     "promote [flags] [releases]",
-		Aliases: []string{"prom", "p"},
-		Short:   "Promote releases across environments",
+		Use:               "promote [flags] [releases]",
+		Aliases:           []string{"prom", "p"},
+		Short:             "Promote releases across environments",
+		ValidArgsFunction: completeReleases(-1),
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			if autoMerge && draft {
 				return fmt.Errorf("flags --auto-merge and --draft cannot be used together")
@@ -171,11 +235,12 @@ func NewReleasePromoteCmd(params PromoteParams) *cobra.Command {
 			cfg := config.FromContext(cmd.Context())
 			cat := catalog.FromContext(cmd.Context())
 
-			var filter filtering.Filter
+			var selection []string
 			if len(releases) == 0 && !all && len(cfg.Releases.Selected) > 0 {
 				// if there is no pre-selection, ie: user did not explicity pass releases nor use the --all flag
-				// we want to limit the catalog releases to the user config defined release selection.
-				cat.WithReleaseFilter(filtering.NewSpecificReleasesFilter(cfg.Releases.Selected))
+				// we want to limit the releases to the user config defined release selection. The catalog itself
+				// is left unfiltered, such that dependencies outside of selection are still checked.
+				selection = cfg.Releases.Selected
 			}
 
 			sourceEnv, err := v1alpha1.GetEnvironmentByName(cat.Environments, sourceEnv)
@@ -195,18 +260,24 @@ func NewReleasePromoteCmd(params PromoteParams) *cobra.Command {
 
 			selectedEnvironments := v1alpha1.GetEnvironmentsByNames(cat.Environments, cfg.Environments.Selected)
 
+			issueKeyPattern, err := changelog.CompileIssueKeyPattern(cfg.Changelog.IssueKeyPattern)
+			if err != nil {
+				return err
+			}
+
 			infoProvider := info.NewProvider(cfg.GitHubOrganization, cfg.Templates.Project.GitTag, cfg.RepositoriesDir, cfg.JoyCache)
 
 			promoter := promote.Promotion{
 				CommitTemplate:      cfg.Templates.Release.Promote.Commit,
 				PullRequestTemplate: cfg.Templates.Release.Promote.PullRequest,
 				TemplateVariables:   templateVariables,
+				IssueKeyPattern:     issueKeyPattern,
 				PromptProvider:      cmp.Or[promote.PromptProvider](params.Prompt, promote.NewInteractivePromptProvider(cmd.OutOrStdout())),
 				GitProvider:         cmp.Or[promote.GitProvider](params.Git, promote.NewShellGitProvider(cfg.CatalogDir)),
-				PullRequestProvider: cmp.Or[pr.PullRequestProvider](params.PullRequest, github.NewPullRequestProvider(cfg.CatalogDir)),
+				PullRequestProvider: cmp.Or(params.PullRequest, newCatalogPullRequestProvider(cfg)),
 				YamlWriter:          cmp.Or[yml.Writer](params.Writer, yml.DiskWriter),
 				InfoProvider:        cmp.Or(params.Info, infoProvider),
-				LinksProvider:       cmp.Or(params.Links, links.NewProvider(infoProvider, cfg.Templates)),
+				LinksProvider:       cmp.Or(params.Links, links.NewProvider(infoProvider, cfg.Templates, newChartCache(cfg, internal.IO{Out: cmd.ErrOrStderr(), Err: cmd.ErrOrStderr()}))),
 				Out:                 cmd.OutOrStdout(),
 			}
 
@@ -215,7 +286,8 @@ func NewReleasePromoteCmd(params PromoteParams) *cobra.Command {
 				SourceEnv:            sourceEnv,
 				TargetEnv:            targetEnv,
 				Releases:             releases,
-				ReleasesFiltered:     len(releases) > 0 || filter != nil,
+				ReleaseSelection:     selection,
+				ReleasesFiltered:     len(releases) > 0 || len(selection) > 0,
 				NoPrompt:             noPrompt,
 				AutoMerge:            autoMerge,
 				All:                  all,
@@ -225,6 +297,11 @@ func NewReleasePromoteCmd(params PromoteParams) *cobra.Command {
 				SelectedEnvironments: selectedEnvironments,
 				DryRun:               dryRun,
 				LocalOnly:            localOnly,
+				Mode:                 getPromotionMode(versionOnly, valuesOnly),
+				Paths:                paths,
+				Supersede:            supersede,
+				Split:                split,
+				IgnoreDependencies:   ignoreDependencies,
 				MaxColumnWidth:       cfg.ColumnWidths.Get(narrow, wide),
 			}
 
@@ -246,13 +323,59 @@ func NewReleasePromoteCmd(params PromoteParams) *cobra.Command {
 	cmd.Flags().BoolVar(&all, "all", false, "Select all releases")
 	cmd.Flags().BoolVar(&keepPrerelease, "keep-prerelease", false, "Do not promote releases that are prereleases in target env")
 	cmd.Flags().StringSliceVar(&omit, "omit", nil, "Releases to omit from promotion")
+	cmd.Flags().BoolVar(&versionOnly, "version-only", false, "Only promote release versions, leaving values untouched in target")
+	cmd.Flags().BoolVar(&valuesOnly, "values-only", false, "Only promote release values, leaving versions untouched in target")
+	cmd.Flags().BoolVar(&supersede, "supersede", false, "Close open PRs only promoting releases also promoted to target environment, without prompting")
+	cmd.Flags().BoolVar(&split, "split", false, "Create one independent branch and PR per release, instead of a single PR for all releases")
+	cmd.Flags().StringSliceVar(&paths, "path", nil, "Only promote values at given dot-separated path, such as spec.values.featureFlags, escaping dots within keys with a backslash (flag can be specified multiple times)")
+	cmd.Flags().BoolVar(&ignoreDependencies, "ignore-dependencies", false, "Only warn about, rather than fail on, releases promoted ahead of dependencies still behind in target")
 	cmd.MarkFlagsMutuallyExclusive("narrow", "wide")
+	cmd.MarkFlagsMutuallyExclusive("version-only", "values-only", "path")
+
+	registerEnvironmentFlagCompletion(cmd, false, "source", "target")
+	registerReleaseFlagCompletion(cmd, true, "omit")
 
 	params.PreRunConfigs.PullCatalog(cmd)
 
 	return cmd
 }
 
+// newChartCache returns the cache of charts configured in the catalog, pulling missing charts with given IO.
+func newChartCache(cfg *config.Config, io internal.IO) helm.ChartCache {
+	return helm.ChartCache{
+		Refs:            cfg.Charts,
+		DefaultChartRef: cfg.DefaultChartRef,
+		Root:            cfg.JoyCache,
+		Puller:          helm.CLI{IO: io},
+	}
+}
+
+// newCatalogPullRequestProvider returns the provider of pull requests for the catalog repository, as configured in the catalog.
+func newCatalogPullRequestProvider(cfg *config.Config) pr.PullRequestProvider {
+	prs := cfg.PullRequests
+	switch prs.Provider {
+	case config.PullRequestProviderGitLab:
+		token := os.Getenv(cmp.Or(prs.TokenEnv, gitlab.TokenEnvVar))
+		return gitlab.NewPullRequestProvider(cmp.Or(prs.URL, "https://gitlab.com"), prs.Repository, token)
+	case config.PullRequestProviderGitea:
+		token := os.Getenv(cmp.Or(prs.TokenEnv, gitea.TokenEnvVar))
+		return gitea.NewPullRequestProvider(prs.URL, prs.Repository, token)
+	default:
+		return github.NewPullRequestProvider(cfg.CatalogDir)
+	}
+}
+
+func getPromotionMode(versionOnly, valuesOnly bool) promote.Mode {
+	switch {
+	case versionOnly:
+		return promote.ModeVersionOnly
+	case valuesOnly:
+		return promote.ModeValuesOnly
+	default:
+		return promote.ModeFull
+	}
+}
+
 func parseTemplateVars(templateVars []string) (map[string]string, error) {
 	vars := make(map[string]string)
 	for _, templateVar := range templateVars {
@@ -298,8 +421,9 @@ func NewReleaseRenderCmd() *cobra.Command {
 	)
 
 	cmd := &cobra.Command{
-		Use:   "render [release]",
-		Short: "render kubernetes manifests from joy release",
+		Use:               "render [release]",
+		Short:             "render kubernetes manifests from joy release",
+		ValidArgsFunction: completeReleases(-1),
 		RunE: func(cmd *cobra.Command, releases []string) (err error) {
 			cfg := config.FromContext(cmd.Context())
 
@@ -615,6 +739,8 @@ func NewReleaseRenderCmd() *cobra.Command {
 	cmd.Flags().BoolVar(&verbose, "verbose", false, "print empty diffs with headers")
 	cmd.Flags().BoolVar(&valuesOnly, "values", false, "print rendered chart values only")
 
+	registerEnvironmentFlagCompletion(cmd, true, "env")
+
 	return cmd
 }
 
@@ -622,9 +748,10 @@ func NewValidateCommand() *cobra.Command {
 	var env string
 
 	cmd := &cobra.Command{
-		Use:   "validate [releases...]",
-		Short: "validate releases",
-		Args:  cobra.RangeArgs(0, 1),
+		Use:               "validate [releases...]",
+		Short:             "validate releases",
+		Args:              cobra.RangeArgs(0, 1),
+		ValidArgsFunction: completeReleases(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cfg := config.FromContext(cmd.Context())
 
@@ -670,6 +797,7 @@ func NewValidateCommand() *cobra.Command {
 	}
 
 	cmd.Flags().StringVarP(&env, "env", "e", "", "environment to select release from.")
+	registerEnvironmentFlagCompletion(cmd, true, "env")
 	return cmd
 }
 
@@ -680,48 +808,17 @@ func NewGitCommands() *cobra.Command {
 			target string
 		)
 		cmd := &cobra.Command{
-			Use:     command + " <release>",
-			Aliases: []string{command[0:1]},
-			Args:    cobra.MinimumNArgs(1),
+			Use:               command + " <release>",
+			Aliases:           []string{command[0:1]},
+			Args:              cobra.MinimumNArgs(1),
+			ValidArgsFunction: completeReleases(1),
 			RunE: func(cmd *cobra.Command, args []string) error {
 				cfg := config.FromContext(cmd.Context())
 				cat := catalog.FromContext(cmd.Context())
 
-				if target == "" {
-					target = cfg.ReferenceEnvironment
-				}
-
-				if target == "" {
-					return fmt.Errorf("unable to determine target environment: specify target or set your reference environment in your config")
-				}
-
-				releaseName := args[0]
-
-				var sourceRelease, targetRelease *v1alpha1.Release
-
-				for _, cross := range cat.Releases.Items {
-					if cross.Name != releaseName {
-						continue
-					}
-					for _, rel := range cross.Releases {
-						if rel == nil {
-							continue
-						}
-						switch rel.Environment.Name {
-						case source:
-							sourceRelease = rel
-						case target:
-							targetRelease = rel
-						}
-					}
-					break
-				}
-
-				if sourceRelease == nil {
-					return fmt.Errorf("no source release found")
-				}
-				if targetRelease == nil {
-					return fmt.Errorf("no target release found")
+				sourceRelease, targetRelease, err := lookupSourceAndTargetReleases(cfg, cat, args[0], source, target)
+				if err != nil {
+					return err
 				}
 
 				infoProvider := info.NewProvider(cfg.GitHubOrganization, cfg.Templates.Project.GitTag, cfg.RepositoriesDir, cfg.JoyCache)
@@ -766,6 +863,7 @@ func NewGitCommands() *cobra.Command {
 
 		cmd.Flags().StringVar(&source, "source", "", "source environment to compare release from")
 		cmd.Flags().StringVar(&target, "target", "", "target environment to compare release to")
+		registerEnvironmentFlagCompletion(cmd, false, "source", "target")
 
 		if err := cmd.MarkFlagRequired("source"); err != nil {
 			panic(err)
@@ -786,14 +884,116 @@ func NewGitCommands() *cobra.Command {
 	return root
 }
 
+func NewReleaseChangelogCmd() *cobra.Command {
+	var source, target string
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:     "changelog <release>",
+		Aliases: []string{"changes", "c"},
+		Short:   "Show changelog of a release between environments",
+		Long: `Show changelog of a release between environments, with commits parsed as Conventional Commits and grouped by type.
+
+Breaking changes are highlighted, and issue keys are extracted from commit messages using the
+changelog.issueKeyPattern regular expression of the catalog configuration (defaults to Jira-style keys).`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeReleases(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := config.FromContext(cmd.Context())
+			cat := catalog.FromContext(cmd.Context())
+
+			sourceRelease, targetRelease, err := lookupSourceAndTargetReleases(cfg, cat, args[0], source, target)
+			if err != nil {
+				return err
+			}
+
+			issueKeyPattern, err := changelog.CompileIssueKeyPattern(cfg.Changelog.IssueKeyPattern)
+			if err != nil {
+				return err
+			}
+
+			infoProvider := info.NewProvider(cfg.GitHubOrganization, cfg.Templates.Project.GitTag, cfg.RepositoriesDir, cfg.JoyCache)
+			releaseChangelog, err := changelog.Generate(infoProvider, sourceRelease, targetRelease, issueKeyPattern)
+			if err != nil {
+				return fmt.Errorf("generating changelog: %w", err)
+			}
+
+			if jsonOutput {
+				output, err := json.MarshalIndent(releaseChangelog, "", "  ")
+				if err != nil {
+					return fmt.Errorf("marshalling changelog as JSON: %w", err)
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), string(output))
+				return nil
+			}
+
+			if releaseChangelog.IsEmpty() {
+				fmt.Fprintf(cmd.OutOrStdout(), "🤷 No changes for release %s between %s and %s\n", sourceRelease.Name, sourceRelease.Environment.Name, targetRelease.Environment.Name)
+				return nil
+			}
+
+			fmt.Fprintln(cmd.OutOrStdout(), releaseChangelog.Markdown())
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&source, "source", "", "Source environment to promote release from")
+	cmd.Flags().StringVar(&target, "target", "", "Target environment to promote release to (defaults to reference environment)")
+	cmd.Flags().BoolVarP(&jsonOutput, "json", "j", false, "Output as JSON")
+	registerEnvironmentFlagCompletion(cmd, false, "source", "target")
+
+	if err := cmd.MarkFlagRequired("source"); err != nil {
+		panic(err)
+	}
+
+	return cmd
+}
+
+// lookupSourceAndTargetReleases returns the given release in both source and target environments, where target
+// defaults to the reference environment.
+func lookupSourceAndTargetReleases(cfg *config.Config, cat *catalog.Catalog, releaseName, source, target string) (*v1alpha1.Release, *v1alpha1.Release, error) {
+	target = cmp.Or(target, cfg.ReferenceEnvironment)
+	if target == "" {
+		return nil, nil, fmt.Errorf("unable to determine target environment: specify target or set your reference environment in your config")
+	}
+
+	var sourceRelease, targetRelease *v1alpha1.Release
+	for _, cross := range cat.Releases.Items {
+		if cross.Name != releaseName {
+			continue
+		}
+		for _, rel := range cross.Releases {
+			if rel == nil {
+				continue
+			}
+			switch rel.Environment.Name {
+			case source:
+				sourceRelease = rel
+			case target:
+				targetRelease = rel
+			}
+		}
+		break
+	}
+
+	if sourceRelease == nil {
+		return nil, nil, fmt.Errorf("release %s not found in source environment %s", releaseName, source)
+	}
+	if targetRelease == nil {
+		return nil, nil, fmt.Errorf("release %s not found in target environment %s", releaseName, target)
+	}
+	return sourceRelease, targetRelease, nil
+}
+
 func NewReleaseOpenCmd() *cobra.Command {
 	var env string
 
 	cmd := &cobra.Command{
-		Use:     "open [flags] [release] [link]",
-		Aliases: []string{"open", "o"},
-		Short:   "Open release link",
-		Args:    cobra.RangeArgs(0, 2),
+		Use:               "open [flags] [release] [link]",
+		Aliases:           []string{"open", "o"},
+		Short:             "Open release link",
+		Args:              cobra.RangeArgs(0, 2),
+		ValidArgsFunction: completeReleaseLink,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cfg := config.FromContext(cmd.Context())
 			cat := catalog.FromContext(cmd.Context())
@@ -816,7 +1016,7 @@ func NewReleaseOpenCmd() *cobra.Command {
 			cat.WithReleaseFilter(filter)
 
 			infoProvider := info.NewProvider(cfg.GitHubOrganization, cfg.Templates.Project.GitTag, cfg.RepositoriesDir, cfg.JoyCache)
-			linksProvider := links.NewProvider(infoProvider, cfg.Templates)
+			linksProvider := links.NewProvider(infoProvider, cfg.Templates, newChartCache(cfg, internal.IO{Out: cmd.ErrOrStderr(), Err: cmd.ErrOrStderr()}))
 
 			releaseLinks, err := links.GetReleaseLinks(linksProvider, cat, env, releaseName)
 			if err != nil {
@@ -833,6 +1033,7 @@ func NewReleaseOpenCmd() *cobra.Command {
 	}
 
 	cmd.Flags().StringVarP(&env, "env", "e", "", "Environment (interactive if not specified)")
+	registerEnvironmentFlagCompletion(cmd, false, "env")
 
 	return cmd
 }
@@ -841,10 +1042,11 @@ func NewReleaseLinksCmd() *cobra.Command {
 	var env string
 
 	cmd := &cobra.Command{
-		Use:     "links [flags] [release] [link]",
-		Aliases: []string{"links", "link", "lnk"},
-		Short:   "List release links",
-		Args:    cobra.RangeArgs(0, 2),
+		Use:               "links [flags] [release] [link]",
+		Aliases:           []string{"links", "link", "lnk"},
+		Short:             "List release links",
+		Args:              cobra.RangeArgs(0, 2),
+		ValidArgsFunction: completeReleaseLink,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cfg := config.FromContext(cmd.Context())
 			cat := catalog.FromContext(cmd.Context())
@@ -867,7 +1069,7 @@ func NewReleaseLinksCmd() *cobra.Command {
 			cat.WithReleaseFilter(filter)
 
 			infoProvider := info.NewProvider(cfg.GitHubOrganization, cfg.Templates.Project.GitTag, cfg.RepositoriesDir, cfg.JoyCache)
-			linksProvider := links.NewProvider(infoProvider, cfg.Templates)
+			linksProvider := links.NewProvider(infoProvider, cfg.Templates, newChartCache(cfg, internal.IO{Out: cmd.ErrOrStderr(), Err: cmd.ErrOrStderr()}))
 
 			releaseLinks, err := links.GetReleaseLinks(linksProvider, cat, env, releaseName)
 			if err != nil {
@@ -885,6 +1087,7 @@ func NewReleaseLinksCmd() *cobra.Command {
 	}
 
 	cmd.Flags().StringVarP(&env, "env", "e", "", "Environment (interactive if not specified)")
+	registerEnvironmentFlagCompletion(cmd, false, "env")
 
 	return cmd
 }
@@ -892,8 +1095,9 @@ func NewReleaseLinksCmd() *cobra.Command {
 func NewReleaseSchemaCmd() *cobra.Command {
 	var env string
 	cmd := &cobra.Command{
-		Use:  "schema",
-		Args: cobra.RangeArgs(0, 1),
+		Use:               "schema",
+		Args:              cobra.RangeArgs(0, 1),
+		ValidArgsFunction: completeReleases(1),
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) != 0 && env == "" {
 				return fmt.Errorf("environment (--env flag) is required when querying a release")
@@ -944,5 +1148,6 @@ func NewReleaseSchemaCmd() *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&env, "env", "", "environment to find release from")
+	registerEnvironmentFlagCompletion(cmd, false, "env")
 	return cmd
 }
@@ -47,7 +47,7 @@ func NewRootCmd(version string, preRunConfigs PreRunConfigs) *cobra.Command {
 		SilenceUsage:  true,
 		SilenceErrors: true,
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-			if cmd.CalledAs() == "help" {
+			if cmd.CalledAs() == "help" || isCompletionCmd(cmd) {
 				return nil
 			}
 
@@ -140,6 +140,9 @@ func NewRootCmd(version string, preRunConfigs PreRunConfigs) *cobra.Command {
 	cmd.AddCommand(NewProjectCmd(preRunConfigs))
 	cmd.AddCommand(NewPRCmd())
 	cmd.AddCommand(NewBuildCmd())
+	cmd.AddCommand(NewUICmd(preRunConfigs))
+	cmd.AddCommand(NewServeCmd(preRunConfigs))
+	cmd.AddCommand(NewLinksCmd(preRunConfigs))
 
 	// Catalog git commands
 	cmd.AddGroup(&cobra.Group{ID: "git", Title: "Catalog git commands"})
@@ -76,6 +76,7 @@ The sealed secrets public certificate must also have been imported into the envi
 	}
 
 	cmd.Flags().StringVarP(&env, "env", "e", "", "Environment to seal secret in")
+	registerEnvironmentFlagCompletion(cmd, false, "env")
 
 	return cmd
 }
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/nestoca/joy/internal"
+	"github.com/nestoca/joy/internal/config"
+	"github.com/nestoca/joy/internal/info"
+	"github.com/nestoca/joy/internal/links"
+	"github.com/nestoca/joy/internal/server"
+	"github.com/nestoca/joy/internal/style"
+	"github.com/nestoca/joy/pkg/catalog"
+)
+
+func NewServeCmd(preRunConfigs PreRunConfigs) *cobra.Command {
+	var (
+		addr      string
+		watchOpts server.WatchOpts
+	)
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Args:  cobra.NoArgs,
+		Short: "Serve a read-only web view and JSON API of the catalog",
+		Long: `Serve a read-only web view and JSON API of the catalog, so that anyone can see what is deployed where without installing joy.
+
+The web view shows a matrix of releases across environments, like "joy release list", with links and hydrated values of each release.
+
+The JSON API exposes the following endpoints:
+  GET /api/status
+  GET /api/environments
+  GET /api/environments/{env}
+  GET /api/environments/{env}/releases/{release}
+  GET /api/environments/{env}/releases/{release}/links
+  GET /api/environments/{env}/releases/{release}/values
+  GET /api/projects
+  GET /api/projects/{project}
+  GET /api/releases[?env=dev,staging][&ref=staging]
+
+The catalog is reloaded whenever its files change. Use --pull to also periodically pull the catalog from its remote.
+
+As the API is unauthenticated and exposes hydrated release values, it is only served on localhost by default.
+Listening on all interfaces, for example with --addr :8080, must be explicitly requested.`,
+		Example: `  # Serve catalog locally on port 8080
+  joy serve
+
+  # Serve catalog on port 8080 of all interfaces, pulling changes from remote every minute
+  joy serve --addr :8080 --pull --reload-interval 1m`,
+		GroupID: "core",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := config.FromContext(cmd.Context())
+			cat := catalog.FromContext(cmd.Context())
+
+			infoProvider := info.NewProvider(cfg.GitHubOrganization, cfg.Templates.Project.GitTag, cfg.RepositoriesDir, cfg.JoyCache)
+			charts := newChartCache(cfg, internal.IO{Out: io.Discard, Err: io.Discard})
+
+			srv, err := server.New(server.Opts{
+				CatalogDir:           cfg.CatalogDir,
+				KnownChartRefs:       cfg.KnownChartRefs(),
+				ReferenceEnvironment: cfg.ReferenceEnvironment,
+				Charts:               charts,
+				Links:                links.NewProvider(infoProvider, cfg.Templates, charts),
+				Out:                  cmd.ErrOrStderr(),
+			}, cat)
+			if err != nil {
+				return err
+			}
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+			defer stop()
+
+			go srv.Watch(ctx, watchOpts)
+
+			httpServer := &http.Server{
+				Addr:              addr,
+				Handler:           srv.Handler(),
+				ReadHeaderTimeout: 10 * time.Second,
+			}
+			go func() {
+				<-ctx.Done()
+				shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+				_ = httpServer.Shutdown(shutdownCtx)
+			}()
+
+			url := "http://" + addr
+			if host, port, err := net.SplitHostPort(addr); err == nil && (host == "" || host == "0.0.0.0" || host == "::") {
+				url = "http://localhost:" + port
+				_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "⚠️ %s\n", style.Warning("Serving unauthenticated catalog, including hydrated values, on all network interfaces"))
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "🌐 Serving catalog on %s\n", style.Link(url))
+			if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				return fmt.Errorf("serving: %w", err)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&addr, "addr", "localhost:8080", "Address to listen on, such as :8080 to listen on all interfaces")
+	cmd.Flags().DurationVar(&watchOpts.Interval, "reload-interval", 30*time.Second, "Interval between checks for catalog changes")
+	cmd.Flags().BoolVar(&watchOpts.Pull, "pull", false, "Pull catalog from its remote before each check for changes")
+
+	preRunConfigs.PullCatalog(cmd)
+
+	return cmd
+}
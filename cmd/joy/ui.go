@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/nestoca/joy/internal"
+	"github.com/nestoca/joy/internal/changelog"
+	"github.com/nestoca/joy/internal/config"
+	"github.com/nestoca/joy/internal/info"
+	"github.com/nestoca/joy/internal/links"
+	"github.com/nestoca/joy/internal/release/promote"
+	"github.com/nestoca/joy/internal/ui"
+	"github.com/nestoca/joy/internal/yml"
+	"github.com/nestoca/joy/pkg/catalog"
+)
+
+func NewUICmd(preRunConfigs PreRunConfigs) *cobra.Command {
+	var narrow, wide bool
+	cmd := &cobra.Command{
+		Use:     "ui",
+		Aliases: []string{"dashboard", "tui"},
+		Args:    cobra.NoArgs,
+		Short:   "Browse and promote releases in an interactive terminal dashboard",
+		Long: `Browse and promote releases in an interactive terminal dashboard.
+
+The dashboard shows a matrix of releases across environments, like "joy release list". From there, you can:
+  - Filter releases by name (/)
+  - Drill down into the YAML, links and hydrated values of a release in an environment (enter)
+  - Mark releases (space) and promote them from the environment of the current column (p)`,
+		GroupID: "core",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := config.FromContext(cmd.Context())
+			cat := catalog.FromContext(cmd.Context())
+
+			terminal := ui.NewTerminal(cmd.InOrStdin(), cmd.OutOrStdout())
+			if !terminal.IsInteractive() {
+				return fmt.Errorf("joy ui requires an interactive terminal")
+			}
+
+			issueKeyPattern, err := changelog.CompileIssueKeyPattern(cfg.Changelog.IssueKeyPattern)
+			if err != nil {
+				return err
+			}
+
+			infoProvider := info.NewProvider(cfg.GitHubOrganization, cfg.Templates.Project.GitTag, cfg.RepositoriesDir, cfg.JoyCache)
+			charts := newChartCache(cfg, internal.IO{Out: io.Discard, Err: io.Discard})
+			linksProvider := links.NewProvider(infoProvider, cfg.Templates, charts)
+
+			return ui.Run(cmd.Context(), ui.Opts{
+				Catalog:              cat,
+				Terminal:             terminal,
+				ReferenceEnvironment: cfg.ReferenceEnvironment,
+				SelectedEnvironments: cfg.Environments.Selected,
+				Charts:               charts,
+				Links:                linksProvider,
+				Promotion: promote.Promotion{
+					CommitTemplate:      cfg.Templates.Release.Promote.Commit,
+					PullRequestTemplate: cfg.Templates.Release.Promote.PullRequest,
+					IssueKeyPattern:     issueKeyPattern,
+					GitProvider:         promote.NewShellGitProvider(cfg.CatalogDir),
+					PullRequestProvider: newCatalogPullRequestProvider(cfg),
+					YamlWriter:          yml.DiskWriter,
+					InfoProvider:        infoProvider,
+					LinksProvider:       linksProvider,
+				},
+				MaxColumnWidth: cfg.ColumnWidths.Get(narrow, wide),
+			})
+		},
+	}
+	cmd.Flags().BoolVarP(&narrow, "narrow", "n", false, "Use narrow columns mode")
+	cmd.Flags().BoolVarP(&wide, "wide", "w", false, "Use wide columns mode")
+	cmd.MarkFlagsMutuallyExclusive("narrow", "wide")
+
+	preRunConfigs.PullCatalog(cmd)
+
+	return cmd
+}
@@ -1,10 +1,19 @@
 package build
 
 import (
+	"cmp"
+	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 
 	"golang.org/x/mod/semver"
+	"gopkg.in/yaml.v3"
 
+	"github.com/nestoca/joy/api/v1alpha1"
 	"github.com/nestoca/joy/internal/style"
 	"github.com/nestoca/joy/internal/yml"
 	"github.com/nestoca/joy/pkg/catalog"
@@ -13,72 +22,216 @@ import (
 type Opts struct {
 	Catalog      *catalog.Catalog
 	Writer       yml.Writer
-	Environment  string
+	Out          io.Writer
+	CatalogDir   string
+	Environments []string
 	Project      string
 	Version      string
 	ChartVersion string
+	Digest       string
+	DryRun       bool
+}
+
+// Change describes the update of a single release file by a build promotion.
+type Change struct {
+	Environment          string `json:"environment"`
+	Release              string `json:"release"`
+	File                 string `json:"file"`
+	PreviousVersion      string `json:"previousVersion"`
+	Version              string `json:"version"`
+	PreviousChartVersion string `json:"previousChartVersion,omitempty"`
+	ChartVersion         string `json:"chartVersion,omitempty"`
+	PreviousDigest       string `json:"previousDigest,omitempty"`
+	Digest               string `json:"digest,omitempty"`
+}
+
+// Report describes all the release files changed, or that would be changed in dry-run mode, by a build promotion.
+type Report struct {
+	DryRun  bool     `json:"dryRun"`
+	Project string   `json:"project"`
+	Changes []Change `json:"changes"`
 }
 
 func Promote(opts Opts) error {
-	if !opts.Catalog.Environments[0].Spec.Promotion.FromPullRequests {
-		version := "v" + opts.Version
-		if semver.Prerelease(version)+semver.Build(version) != "" {
-			return fmt.Errorf("cannot promote prerelease version to %s environment", opts.Environment)
+	out := cmp.Or[io.Writer](opts.Out, os.Stdout)
+
+	if opts.Digest != "" {
+		if err := v1alpha1.ValidateDigest(opts.Digest); err != nil {
+			return err
 		}
 	}
 
-	promotionCount := 0
-	for _, crossRelease := range opts.Catalog.Releases.Items {
-		release := crossRelease.Releases[0]
-		if release == nil || release.Spec.Project != opts.Project {
-			continue
-		}
+	environments, err := getEnvironments(opts.Catalog, opts.Environments)
+	if err != nil {
+		return err
+	}
 
-		versionNode, err := yml.FindNode(release.File.Tree, "spec.version")
-		if err != nil {
-			return fmt.Errorf("release %s has no version property: %w", release.Name, err)
+	for _, env := range environments {
+		if !env.Spec.Promotion.FromPullRequests {
+			version := "v" + opts.Version
+			if semver.Prerelease(version)+semver.Build(version) != "" {
+				return fmt.Errorf("cannot promote prerelease version to %s environment", env.Name)
+			}
 		}
+	}
 
-		versionNode.Value = opts.Version
-		if err := release.File.UpdateYamlFromTree(); err != nil {
-			return fmt.Errorf("updating release yaml from node tree: %w", err)
-		}
+	// Compute all changes before writing anything, so that a failure does not leave a partial promotion behind
+	report := Report{DryRun: opts.DryRun, Project: opts.Project, Changes: []Change{}}
+	var files []*yml.File
+	for _, env := range environments {
+		envIndex := slices.Index(opts.Catalog.Environments, env)
+		for _, crossRelease := range opts.Catalog.Releases.Items {
+			release := crossRelease.Releases[envIndex]
+			if release == nil || release.Spec.Project != opts.Project {
+				continue
+			}
 
-		if opts.ChartVersion != "" {
-			chartVersionNode, err := yml.FindNode(release.File.Tree, "spec.chart.version")
+			change, err := promoteRelease(env, release, opts)
 			if err != nil {
-				return fmt.Errorf("release %s has no chart version property: %w", release.Name, err)
-			}
-			chartVersionNode.Value = opts.ChartVersion
-			if err := release.File.UpdateYamlFromTree(); err != nil {
-				return fmt.Errorf("updating release yaml from node tree: %w", err)
+				return err
 			}
+			report.Changes = append(report.Changes, change)
+			files = append(files, release.File)
+		}
+	}
+
+	if len(report.Changes) == 0 {
+		return fmt.Errorf("no releases found for project %s", opts.Project)
+	}
+
+	if opts.DryRun {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshalling dry-run report: %w", err)
 		}
+		_, _ = fmt.Fprintln(out, string(data))
+		return nil
+	}
 
-		if err := opts.Writer.WriteFile(release.File); err != nil {
+	for i, file := range files {
+		if err := opts.Writer.WriteFile(file); err != nil {
 			return fmt.Errorf("writing release file: %w", err)
 		}
+		change := report.Changes[i]
+		_, _ = fmt.Fprintf(out, "✅ Promoted release %s in environment %s to %s\n", style.Resource(change.Release), style.Resource(change.Environment), formatTarget(opts))
+	}
+
+	plural, envPlural := "", ""
+	if len(report.Changes) > 1 {
+		plural = "s"
+	}
+	if len(environments) > 1 {
+		envPlural = "s"
+	}
+	_, _ = fmt.Fprintf(out, "🍺 Promoted %d release%s of project %s in environment%s %s to %s\n", len(report.Changes), plural, style.Resource(opts.Project), envPlural, style.Resource(strings.Join(opts.Environments, ", ")), formatTarget(opts))
+	return nil
+}
+
+// getEnvironments returns the catalog environments with given names, in the order they were given.
+func getEnvironments(cat *catalog.Catalog, names []string) ([]*v1alpha1.Environment, error) {
+	if len(names) == 0 {
+		return nil, fmt.Errorf("at least one environment is required")
+	}
+
+	var environments []*v1alpha1.Environment
+	for _, name := range names {
+		if name == "" {
+			return nil, fmt.Errorf("environment name cannot be empty")
+		}
+		env, err := v1alpha1.GetEnvironmentByName(cat.Environments, name)
+		if err != nil {
+			return nil, err
+		}
+		environments = append(environments, env)
+	}
+	return environments, nil
+}
 
-		if opts.ChartVersion != "" {
-			fmt.Printf("✅ Promoted release %s to version %s and chart version %s\n", style.Resource(release.Name), style.Version(opts.Version), style.Version(opts.ChartVersion))
-		} else {
-			fmt.Printf("✅ Promoted release %s to version %s\n", style.Resource(release.Name), style.Version(opts.Version))
+// promoteRelease updates the version, chart version and digest of given release's file tree, without writing it. Any
+// existing digest is removed when no digest is given, as it would otherwise pin the previous image to new version.
+func promoteRelease(env *v1alpha1.Environment, release *v1alpha1.Release, opts Opts) (Change, error) {
+	path := release.File.Path
+	if catalogDir, err := filepath.Abs(opts.CatalogDir); opts.CatalogDir != "" && err == nil {
+		if relativePath, err := filepath.Rel(catalogDir, path); err == nil {
+			path = relativePath
 		}
-		promotionCount++
 	}
 
-	if promotionCount == 0 {
-		return fmt.Errorf("no releases found for project %s", opts.Project)
+	change := Change{
+		Environment:  env.Name,
+		Release:      release.Name,
+		File:         path,
+		Version:      opts.Version,
+		ChartVersion: opts.ChartVersion,
+		Digest:       opts.Digest,
 	}
-	plural := ""
-	if promotionCount > 1 {
-		plural = "s"
+
+	versionNode, err := yml.FindNode(release.File.Tree, v1alpha1.ReleaseVersionPath)
+	if err != nil {
+		return Change{}, fmt.Errorf("release %s has no version property: %w", release.Name, err)
 	}
+	change.PreviousVersion = versionNode.Value
+	versionNode.Value = opts.Version
 
 	if opts.ChartVersion != "" {
-		fmt.Printf("🍺 Promoted %d release%s of project %s in environment %s to version %s and chart version %s\n", promotionCount, plural, style.Resource(opts.Project), style.Resource(opts.Environment), style.Version(opts.Version), style.Version(opts.ChartVersion))
+		chartVersionNode, err := yml.FindNode(release.File.Tree, "spec.chart.version")
+		if err != nil {
+			return Change{}, fmt.Errorf("release %s has no chart version property: %w", release.Name, err)
+		}
+		change.PreviousChartVersion = chartVersionNode.Value
+		chartVersionNode.Value = opts.ChartVersion
+	}
+
+	// Digest pins the version, so any previous one must not be left behind with the new version
+	change.PreviousDigest = yml.FindNodeValueOrDefault(release.File.Tree, v1alpha1.ReleaseDigestPath, "")
+	if opts.Digest != "" {
+		if err := setDigest(release.File.Tree, opts.Digest); err != nil {
+			return Change{}, fmt.Errorf("setting digest of release %s: %w", release.Name, err)
+		}
 	} else {
-		fmt.Printf("🍺 Promoted %d release%s of project %s in environment %s to version %s\n", promotionCount, plural, style.Resource(opts.Project), style.Resource(opts.Environment), style.Version(opts.Version))
+		yml.RemoveNode(release.File.Tree, v1alpha1.ReleaseDigestPath)
+	}
+
+	if err := release.File.UpdateYamlFromTree(); err != nil {
+		return Change{}, fmt.Errorf("updating release yaml from node tree: %w", err)
+	}
+	return change, nil
+}
+
+// setDigest sets the spec.digest of given release tree, adding it right after spec.version if missing.
+func setDigest(tree *yaml.Node, digest string) error {
+	if node, err := yml.FindNode(tree, v1alpha1.ReleaseDigestPath); err == nil {
+		node.Value = digest
+		return nil
 	}
+
+	spec, err := yml.FindNode(tree, "spec")
+	if err != nil {
+		return err
+	}
+
+	index := len(spec.Content)
+	for i := 0; i+1 < len(spec.Content); i += 2 {
+		if spec.Content[i].Value == "version" {
+			index = i + 2
+			break
+		}
+	}
+
+	spec.Content = slices.Insert(spec.Content, index,
+		&yaml.Node{Kind: yaml.ScalarNode, Value: "digest"},
+		&yaml.Node{Kind: yaml.ScalarNode, Value: digest},
+	)
 	return nil
 }
+
+func formatTarget(opts Opts) string {
+	target := "version " + style.Version(opts.Version)
+	if opts.ChartVersion != "" {
+		target += " and chart version " + style.Version(opts.ChartVersion)
+	}
+	if opts.Digest != "" {
+		target += " with digest " + style.Version(opts.Digest)
+	}
+	return target
+}
@@ -1,6 +1,8 @@
 package build
 
 import (
+	"bytes"
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -11,6 +13,8 @@ import (
 	"github.com/nestoca/joy/pkg/catalog"
 )
 
+const testDigest = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
 func TestPromote(t *testing.T) {
 	var writer yml.WriterMock
 
@@ -33,10 +37,11 @@ func TestPromote(t *testing.T) {
 				},
 			},
 		},
-		Environment: "staging",
-		Writer:      &writer,
-		Project:     "promote-build",
-		Version:     "1.1.2",
+		Environments: []string{"staging"},
+		Writer:       &writer,
+		Out:          &bytes.Buffer{},
+		Project:      "promote-build",
+		Version:      "1.1.2",
 	}
 
 	require.NoError(t, Promote(opts))
@@ -46,14 +51,104 @@ func TestPromote(t *testing.T) {
 	require.Equal(t, "1.1.2", yml.FindNodeValueOrDefault(file.Tree, "spec.version", ""))
 }
 
+func TestPromoteMultipleEnvironmentsWithDigest(t *testing.T) {
+	var writer yml.WriterMock
+
+	opts := Opts{
+		Catalog:      newMultiEnvironmentCatalog(t),
+		Environments: []string{"staging", "demo"},
+		Writer:       &writer,
+		Out:          &bytes.Buffer{},
+		Project:      "promote-build",
+		Version:      "1.1.2",
+		Digest:       testDigest,
+	}
+
+	require.NoError(t, Promote(opts))
+	require.Len(t, writer.WriteFileCalls(), 2)
+
+	for _, call := range writer.WriteFileCalls() {
+		require.Equal(t, "1.1.2", yml.FindNodeValueOrDefault(call.File.Tree, "spec.version", ""))
+		require.Equal(t, testDigest, yml.FindNodeValueOrDefault(call.File.Tree, "spec.digest", ""))
+	}
+	require.Equal(t, "spec:\n  version: 1.1.2\n  digest: "+testDigest+"\n  project: promote-build\n", string(writer.WriteFileCalls()[0].File.Yaml))
+}
+
+func TestPromoteDryRun(t *testing.T) {
+	var writer yml.WriterMock
+	var out bytes.Buffer
+
+	opts := Opts{
+		Catalog:      newMultiEnvironmentCatalog(t),
+		CatalogDir:   ".",
+		Environments: []string{"demo", "staging"},
+		Writer:       &writer,
+		Out:          &out,
+		Project:      "promote-build",
+		Version:      "1.1.2",
+		Digest:       testDigest,
+		DryRun:       true,
+	}
+
+	require.NoError(t, Promote(opts))
+	require.Empty(t, writer.WriteFileCalls())
+
+	var report Report
+	require.NoError(t, json.Unmarshal(out.Bytes(), &report))
+	require.Equal(t, Report{
+		DryRun:  true,
+		Project: "promote-build",
+		Changes: []Change{
+			{
+				Environment:     "demo",
+				Release:         "release1",
+				File:            "demo/release1.yaml",
+				PreviousVersion: "0.0.2",
+				Version:         "1.1.2",
+				PreviousDigest:  "sha256:old",
+				Digest:          testDigest,
+			},
+			{
+				Environment:     "staging",
+				Release:         "release1",
+				File:            "staging/release1.yaml",
+				PreviousVersion: "0.0.1",
+				Version:         "1.1.2",
+				Digest:          testDigest,
+			},
+		},
+	}, report)
+}
+
+func TestPromoteWithInvalidDigest(t *testing.T) {
+	opts := Opts{
+		Catalog:      newMultiEnvironmentCatalog(t),
+		Environments: []string{"staging"},
+		Project:      "promote-build",
+		Version:      "1.1.2",
+		Digest:       "sha256:abc",
+	}
+	require.EqualError(t, Promote(opts), `invalid digest "sha256:abc": expected sha256:<64 hex characters>`)
+}
+
+func TestPromoteWithUnknownEnvironment(t *testing.T) {
+	opts := Opts{
+		Catalog:      newMultiEnvironmentCatalog(t),
+		Environments: []string{"staging", "prod"},
+		Project:      "promote-build",
+		Version:      "1.1.2",
+	}
+	require.EqualError(t, Promote(opts), `environment "prod" not found`)
+}
+
 func TestPromoteWithPrereleaseVersion(t *testing.T) {
 	opts := Opts{
 		Catalog: &catalog.Catalog{
-			Environments: []*v1alpha1.Environment{{}},
+			Environments: []*v1alpha1.Environment{{EnvironmentMetadata: v1alpha1.EnvironmentMetadata{Name: "testing"}}},
 		},
-		Environment: "testing",
-		Project:     "promote-build",
-		Version:     "1.1.2-updated",
+		Environments: []string{"testing"},
+		Project:      "promote-build",
+		Version:      "1.1.2-updated",
 	}
 	require.EqualError(t, Promote(opts), "cannot promote prerelease version to testing environment")
 }
@@ -61,20 +156,53 @@ func TestPromoteWithPrereleaseVersion(t *testing.T) {
 func TestPromoteWhenNoReleasesFoundForProject(t *testing.T) {
 	opts := Opts{
 		Catalog: &catalog.Catalog{
-			Environments: []*v1alpha1.Environment{{}},
+			Environments: []*v1alpha1.Environment{{EnvironmentMetadata: v1alpha1.EnvironmentMetadata{Name: "testing"}}},
 			Releases:     cross.ReleaseList{},
 		},
-		Environment: "testing",
-		Project:     "promote-build",
-		Version:     "1.1.2",
+		Environments: []string{"testing"},
+		Project:      "promote-build",
+		Version:      "1.1.2",
 	}
 
 	require.EqualError(t, Promote(opts), "no releases found for project promote-build")
 }
 
+func newMultiEnvironmentCatalog(t *testing.T) *catalog.Catalog {
+	environments := []*v1alpha1.Environment{
+		{EnvironmentMetadata: v1alpha1.EnvironmentMetadata{Name: "staging"}},
+		{EnvironmentMetadata: v1alpha1.EnvironmentMetadata{Name: "demo"}},
+	}
+	return &catalog.Catalog{
+		Environments: environments,
+		Releases: cross.ReleaseList{
+			Environments: environments,
+			Items: []*cross.Release{
+				{
+					Releases: []*v1alpha1.Release{
+						{
+							ReleaseMetadata: v1alpha1.ReleaseMetadata{Name: "release1"},
+							Spec:            v1alpha1.ReleaseSpec{Project: "promote-build"},
+							File:            makeFileWithPath(t, "staging/release1.yaml", "spec:\n  version: 0.0.1\n  project: promote-build\n"),
+						},
+						{
+							ReleaseMetadata: v1alpha1.ReleaseMetadata{Name: "release1"},
+							Spec:            v1alpha1.ReleaseSpec{Project: "promote-build"},
+							File:            makeFileWithPath(t, "demo/release1.yaml", "spec:\n  version: 0.0.2\n  digest: sha256:old\n  project: promote-build\n"),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
 func makeFile(t *testing.T, content string) *yml.File {
+	return makeFileWithPath(t, "", content)
+}
+
+func makeFileWithPath(t *testing.T, path, content string) *yml.File {
 	t.Helper()
-	f, err := yml.NewFile("", []byte(content))
+	f, err := yml.NewFile(path, []byte(content))
 	require.NoError(t, err)
 	return f
 }
@@ -0,0 +1,272 @@
+package changelog
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/nestoca/joy/internal/info"
+)
+
+// DefaultIssueKeyPattern matches Jira-style issue keys, such as "PROJ-123".
+const DefaultIssueKeyPattern = `\b[A-Z][A-Z0-9]+-[0-9]+\b`
+
+// OtherType is the type of commits that do not follow the Conventional Commits specification.
+const OtherType = "other"
+
+// sectionTitles maps known commit types to their section titles, in display order.
+var sectionTitles = []struct {
+	Type  string
+	Title string
+}{
+	{"feat", "Features"},
+	{"fix", "Bug Fixes"},
+	{"perf", "Performance Improvements"},
+	{"revert", "Reverts"},
+	{"refactor", "Code Refactoring"},
+	{"docs", "Documentation"},
+	{"style", "Styles"},
+	{"test", "Tests"},
+	{"build", "Build System"},
+	{"ci", "Continuous Integration"},
+	{"chore", "Chores"},
+	{OtherType, "Other Changes"},
+}
+
+var (
+	headerRegex         = regexp.MustCompile(`^(\w+)(?:\(([^)]*)\))?(!)?: *(.+)$`)
+	breakingFooterRegex = regexp.MustCompile(`(?m)^BREAKING[ -]CHANGE: *(.+)$`)
+)
+
+// Entry is a single commit parsed according to the Conventional Commits specification.
+type Entry struct {
+	Sha          string   `json:"sha"`
+	ShortSha     string   `json:"shortSha"`
+	Author       string   `json:"author"`
+	Message      string   `json:"message"`
+	Type         string   `json:"type"`
+	Scope        string   `json:"scope,omitempty"`
+	Description  string   `json:"description"`
+	Breaking     bool     `json:"breaking"`
+	BreakingNote string   `json:"breakingNote,omitempty"`
+	IssueKeys    []string `json:"issueKeys,omitempty"`
+}
+
+// Section groups the entries of a given commit type.
+type Section struct {
+	Type    string   `json:"type"`
+	Title   string   `json:"title"`
+	Entries []*Entry `json:"entries"`
+}
+
+// Changelog groups commits by type, highlighting breaking changes and referenced issues.
+type Changelog struct {
+	// Sections are the non-empty groups of entries by commit type, in conventional display order.
+	Sections []*Section `json:"sections"`
+
+	// Breaking are the entries introducing breaking changes, regardless of their type.
+	Breaking []*Entry `json:"breaking"`
+
+	// IssueKeys are the unique issue keys referenced by all entries, sorted alphabetically.
+	IssueKeys []string `json:"issueKeys"`
+}
+
+// CompileIssueKeyPattern compiles given issue key pattern, falling back to DefaultIssueKeyPattern when empty.
+func CompileIssueKeyPattern(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		pattern = DefaultIssueKeyPattern
+	}
+	regex, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("compiling issue key pattern %q: %w", pattern, err)
+	}
+	return regex, nil
+}
+
+// ParseEntry parses given commit message according to the Conventional Commits specification,
+// extracting issue keys matching given pattern if any. Messages that do not conform are given OtherType.
+func ParseEntry(message string, issueKeyPattern *regexp.Regexp) *Entry {
+	header, body, _ := strings.Cut(strings.TrimSpace(message), "\n")
+	entry := &Entry{
+		Message:     message,
+		Type:        OtherType,
+		Description: header,
+	}
+
+	if match := headerRegex.FindStringSubmatch(header); match != nil {
+		entry.Type = strings.ToLower(match[1])
+		entry.Scope = match[2]
+		entry.Breaking = match[3] != ""
+		entry.Description = match[4]
+	}
+
+	if match := breakingFooterRegex.FindStringSubmatch(body); match != nil {
+		entry.Breaking = true
+		entry.BreakingNote = strings.TrimSpace(match[1])
+	}
+
+	if issueKeyPattern != nil {
+		for _, key := range issueKeyPattern.FindAllString(message, -1) {
+			if !slices.Contains(entry.IssueKeys, key) {
+				entry.IssueKeys = append(entry.IssueKeys, key)
+			}
+		}
+	}
+
+	return entry
+}
+
+// New builds a changelog from given commits, extracting issue keys matching given pattern if any.
+func New(commits []*info.CommitMetadata, issueKeyPattern *regexp.Regexp) *Changelog {
+	sections := make(map[string]*Section)
+	changelog := &Changelog{}
+
+	for _, commit := range commits {
+		message := commit.Message
+		if commit.Body != "" {
+			message += "\n\n" + commit.Body
+		}
+
+		entry := ParseEntry(message, issueKeyPattern)
+		entry.Sha = commit.Sha
+		entry.ShortSha = commit.Sha[:min(7, len(commit.Sha))]
+		entry.Author = commit.Author
+
+		sectionType := entry.Type
+		if !isKnownType(sectionType) {
+			sectionType = OtherType
+		}
+
+		section := sections[sectionType]
+		if section == nil {
+			section = &Section{Type: sectionType, Title: getSectionTitle(sectionType)}
+			sections[sectionType] = section
+		}
+		section.Entries = append(section.Entries, entry)
+
+		if entry.Breaking {
+			changelog.Breaking = append(changelog.Breaking, entry)
+		}
+
+		for _, key := range entry.IssueKeys {
+			if !slices.Contains(changelog.IssueKeys, key) {
+				changelog.IssueKeys = append(changelog.IssueKeys, key)
+			}
+		}
+	}
+
+	for _, item := range sectionTitles {
+		if section := sections[item.Type]; section != nil {
+			changelog.Sections = append(changelog.Sections, section)
+		}
+	}
+	slices.Sort(changelog.IssueKeys)
+
+	return changelog
+}
+
+// HasBreakingChanges returns true if any entry of the changelog introduces a breaking change.
+func (c *Changelog) HasBreakingChanges() bool {
+	return c != nil && len(c.Breaking) > 0
+}
+
+// IsEmpty returns true if the changelog has no entries.
+func (c *Changelog) IsEmpty() bool {
+	return c == nil || len(c.Sections) == 0
+}
+
+// Section returns the section for given commit type, or nil if there are no entries of that type.
+func (c *Changelog) Section(commitType string) *Section {
+	if c == nil {
+		return nil
+	}
+	for _, section := range c.Sections {
+		if section.Type == commitType {
+			return section
+		}
+	}
+	return nil
+}
+
+// Features returns the entries of type "feat".
+func (c *Changelog) Features() []*Entry {
+	return c.Section("feat").GetEntries()
+}
+
+// Fixes returns the entries of type "fix".
+func (c *Changelog) Fixes() []*Entry {
+	return c.Section("fix").GetEntries()
+}
+
+// GetEntries returns the entries of the section, or nil if the section is nil.
+func (s *Section) GetEntries() []*Entry {
+	if s == nil {
+		return nil
+	}
+	return s.Entries
+}
+
+// Markdown renders the changelog as markdown, with breaking changes first, followed by each section.
+func (c *Changelog) Markdown() string {
+	if c.IsEmpty() {
+		return ""
+	}
+
+	var builder strings.Builder
+	if len(c.Breaking) > 0 {
+		builder.WriteString("### ⚠️ Breaking Changes\n\n")
+		for _, entry := range c.Breaking {
+			builder.WriteString("- " + entry.Line())
+			if entry.BreakingNote != "" {
+				builder.WriteString(": " + entry.BreakingNote)
+			}
+			builder.WriteString("\n")
+		}
+		builder.WriteString("\n")
+	}
+
+	for _, section := range c.Sections {
+		builder.WriteString("### " + section.Title + "\n\n")
+		for _, entry := range section.Entries {
+			builder.WriteString("- " + entry.Line() + "\n")
+		}
+		builder.WriteString("\n")
+	}
+
+	return strings.TrimSuffix(builder.String(), "\n")
+}
+
+// Line returns a single-line summary of the entry, with its optional scope in bold and its short sha.
+// Entries of unknown types keep their full header, as their type would otherwise be lost in the other section.
+func (e *Entry) Line() string {
+	line := e.Description
+	switch {
+	case !isKnownType(e.Type):
+		line, _, _ = strings.Cut(strings.TrimSpace(e.Message), "\n")
+	case e.Scope != "":
+		line = fmt.Sprintf("**%s:** %s", e.Scope, line)
+	}
+	if e.ShortSha != "" {
+		line += " (" + e.ShortSha + ")"
+	}
+	return line
+}
+
+func isKnownType(commitType string) bool {
+	for _, item := range sectionTitles {
+		if item.Type == commitType {
+			return true
+		}
+	}
+	return false
+}
+
+func getSectionTitle(commitType string) string {
+	for _, item := range sectionTitles {
+		if item.Type == commitType {
+			return item.Title
+		}
+	}
+	return commitType
+}
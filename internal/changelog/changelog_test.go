@@ -0,0 +1,152 @@
+package changelog
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/nestoca/joy/api/v1alpha1"
+	"github.com/nestoca/joy/internal/info"
+)
+
+func TestParseEntry(t *testing.T) {
+	issueKeyPattern := regexp.MustCompile(DefaultIssueKeyPattern)
+
+	cases := []struct {
+		Name     string
+		Message  string
+		Expected Entry
+	}{
+		{
+			Name:    "conventional commit",
+			Message: "feat: add login page",
+			Expected: Entry{
+				Type:        "feat",
+				Description: "add login page",
+			},
+		},
+		{
+			Name:    "conventional commit with scope and issue key",
+			Message: "fix(api): handle empty payload (PAY-42)",
+			Expected: Entry{
+				Type:        "fix",
+				Scope:       "api",
+				Description: "handle empty payload (PAY-42)",
+				IssueKeys:   []string{"PAY-42"},
+			},
+		},
+		{
+			Name:    "breaking change marker",
+			Message: "refactor(db)!: drop legacy tables",
+			Expected: Entry{
+				Type:        "refactor",
+				Scope:       "db",
+				Description: "drop legacy tables",
+				Breaking:    true,
+			},
+		},
+		{
+			Name:    "breaking change footer and issue keys in body",
+			Message: "feat: new auth flow\n\nRefs AUTH-1 and AUTH-2, AUTH-1\n\nBREAKING CHANGE: tokens must be renewed",
+			Expected: Entry{
+				Type:         "feat",
+				Description:  "new auth flow",
+				Breaking:     true,
+				BreakingNote: "tokens must be renewed",
+				IssueKeys:    []string{"AUTH-1", "AUTH-2"},
+			},
+		},
+		{
+			Name:    "non conventional commit",
+			Message: "Merge branch 'main' into feature",
+			Expected: Entry{
+				Type:        OtherType,
+				Description: "Merge branch 'main' into feature",
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			tc.Expected.Message = tc.Message
+			require.Equal(t, &tc.Expected, ParseEntry(tc.Message, issueKeyPattern))
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	commits := []*info.CommitMetadata{
+		{Sha: "1111111111", Author: "john", Message: "chore: bump deps"},
+		{Sha: "2222222222", Author: "jane", Message: "fix: null pointer (APP-2)"},
+		{Sha: "3333333333", Author: "john", Message: "feat(ui): dark mode (APP-1)", Body: "BREAKING CHANGE: theme setting renamed"},
+		{Sha: "4444444444", Author: "jane", Message: "wip: experiment"},
+		{Sha: "5555555555", Author: "jane", Message: "feat: export to csv"},
+	}
+
+	changelog := New(commits, regexp.MustCompile(DefaultIssueKeyPattern))
+
+	var types []string
+	for _, section := range changelog.Sections {
+		types = append(types, section.Type)
+	}
+	require.Equal(t, []string{"feat", "fix", "chore", OtherType}, types)
+
+	require.Len(t, changelog.Features(), 2)
+	require.Equal(t, "3333333", changelog.Features()[0].ShortSha)
+	require.Len(t, changelog.Fixes(), 1)
+	require.Equal(t, "wip", changelog.Section(OtherType).Entries[0].Type)
+	require.Nil(t, changelog.Section("docs"))
+
+	require.True(t, changelog.HasBreakingChanges())
+	require.Len(t, changelog.Breaking, 1)
+	require.Equal(t, "theme setting renamed", changelog.Breaking[0].BreakingNote)
+
+	require.Equal(t, []string{"APP-1", "APP-2"}, changelog.IssueKeys)
+
+	expectedMarkdown := `### ⚠️ Breaking Changes
+
+- **ui:** dark mode (APP-1) (3333333): theme setting renamed
+
+### Features
+
+- **ui:** dark mode (APP-1) (3333333)
+- export to csv (5555555)
+
+### Bug Fixes
+
+- null pointer (APP-2) (2222222)
+
+### Chores
+
+- bump deps (1111111)
+
+### Other Changes
+
+- wip: experiment (4444444)
+`
+	require.Equal(t, expectedMarkdown, changelog.Markdown())
+}
+
+func TestEmptyChangelog(t *testing.T) {
+	changelog := New(nil, nil)
+	require.True(t, changelog.IsEmpty())
+	require.False(t, changelog.HasBreakingChanges())
+	require.Empty(t, changelog.Markdown())
+	require.Nil(t, changelog.Features())
+
+	var nilChangelog *Changelog
+	require.False(t, nilChangelog.HasBreakingChanges())
+	require.True(t, nilChangelog.IsEmpty())
+}
+
+func TestNewRange(t *testing.T) {
+	newRelease := func(version string) *v1alpha1.Release {
+		return &v1alpha1.Release{Spec: v1alpha1.ReleaseSpec{Version: version}}
+	}
+
+	require.Equal(t, Range{OlderTag: "v1.0.0", NewerTag: "v1.1.0"}, NewRange(newRelease("1.1.0"), newRelease("1.0.0"), "v1.1.0", "v1.0.0"))
+	require.Equal(t, Range{OlderTag: "v1.0.0", NewerTag: "v1.1.0"}, NewRange(newRelease("1.0.0"), newRelease("1.1.0"), "v1.0.0", "v1.1.0"))
+	require.True(t, NewRange(newRelease("1.1.0"), nil, "v1.1.0", "").IsEmpty())
+	require.True(t, NewRange(newRelease("1.0.0"), newRelease("1.0.0"), "v1.0.0", "v1.0.0").IsEmpty())
+}
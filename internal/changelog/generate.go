@@ -0,0 +1,74 @@
+package changelog
+
+import (
+	"fmt"
+	"regexp"
+
+	"golang.org/x/mod/semver"
+
+	"github.com/nestoca/joy/api/v1alpha1"
+	"github.com/nestoca/joy/internal/info"
+)
+
+// Generate builds the changelog of the commits between the versions of given target and source releases, that is
+// the changes that promoting the source release over the target one would introduce, or revert in case of a downgrade.
+func Generate(provider info.Provider, sourceRelease, targetRelease *v1alpha1.Release, issueKeyPattern *regexp.Regexp) (*Changelog, error) {
+	sourceTag, err := provider.GetReleaseGitTag(sourceRelease)
+	if err != nil {
+		return nil, fmt.Errorf("getting tag for source version %s of release %s: %w", sourceRelease.Spec.Version, sourceRelease.Name, err)
+	}
+
+	targetTag, err := provider.GetReleaseGitTag(targetRelease)
+	if err != nil {
+		return nil, fmt.Errorf("getting tag for target version %s of release %s: %w", targetRelease.Spec.Version, targetRelease.Name, err)
+	}
+
+	commitRange := NewRange(sourceRelease, targetRelease, sourceTag, targetTag)
+	if commitRange.IsEmpty() {
+		return New(nil, issueKeyPattern), nil
+	}
+
+	commits, err := commitRange.GetCommitsMetadata(provider, sourceRelease.Project)
+	if err != nil {
+		return nil, err
+	}
+	return New(commits, issueKeyPattern), nil
+}
+
+// Range is the range of commits between the git tags of the source and target versions of a release.
+type Range struct {
+	OlderTag string
+	NewerTag string
+}
+
+// NewRange returns the range of commits between given source and target tags, ordered by the versions of their
+// releases, such that it covers the commits introduced by promoting source over target, or reverted by it in case
+// of a downgrade. A missing target release yields an empty range.
+func NewRange(sourceRelease, targetRelease *v1alpha1.Release, sourceTag, targetTag string) Range {
+	if targetRelease == nil {
+		return Range{OlderTag: sourceTag, NewerTag: sourceTag}
+	}
+	if semver.Compare("v"+sourceRelease.Spec.Version, "v"+targetRelease.Spec.Version) < 0 {
+		return Range{OlderTag: sourceTag, NewerTag: targetTag}
+	}
+	return Range{OlderTag: targetTag, NewerTag: sourceTag}
+}
+
+// IsEmpty returns true if both ends of range are the same tag.
+func (r Range) IsEmpty() bool {
+	return r.OlderTag == r.NewerTag
+}
+
+// GetCommitsMetadata returns the metadata of the commits within range in the repository of given project.
+func (r Range) GetCommitsMetadata(provider info.Provider, project *v1alpha1.Project) ([]*info.CommitMetadata, error) {
+	projectDir, err := provider.GetProjectSourceDir(project)
+	if err != nil {
+		return nil, fmt.Errorf("getting project clone: %w", err)
+	}
+
+	commits, err := provider.GetCommitsMetadata(projectDir, r.OlderTag, r.NewerTag)
+	if err != nil {
+		return nil, fmt.Errorf("getting commits metadata: %w", err)
+	}
+	return commits, nil
+}
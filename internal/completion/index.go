@@ -0,0 +1,226 @@
+package completion
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/nestoca/joy/internal/config"
+	"github.com/nestoca/joy/pkg/catalog"
+)
+
+// Index is the subset of catalog needed for shell completion. It is cached on disk and only rebuilt when catalog
+// files change, such that pressing tab does not require loading and validating the whole catalog.
+type Index struct {
+	Fingerprint  string    `json:"fingerprint"`
+	Environments []string  `json:"environments"`
+	Projects     []Project `json:"projects"`
+	Releases     []Release `json:"releases"`
+}
+
+type Project struct {
+	Name string `json:"name"`
+
+	// Links and ReleaseLinks are the names of project-level link overrides.
+	Links        []string `json:"links,omitempty"`
+	ReleaseLinks []string `json:"releaseLinks,omitempty"`
+}
+
+type Release struct {
+	Name        string `json:"name"`
+	Environment string `json:"environment"`
+	Project     string `json:"project,omitempty"`
+
+	// Links are the names of release-level link overrides.
+	Links []string `json:"links,omitempty"`
+}
+
+// LoadIndex returns the completion index of catalog, from cache directory if still up to date with catalog, or
+// otherwise from catalog itself, loaded without validation, in which case cache gets updated on a best-effort basis.
+func LoadIndex(ctx context.Context, catalogDir, cacheDir string) (*Index, error) {
+	fingerprint, err := catalog.Fingerprint(catalogDir)
+	if err != nil {
+		return nil, fmt.Errorf("fingerprinting catalog: %w", err)
+	}
+
+	cachePath, err := getCachePath(catalogDir, cacheDir)
+	if err != nil {
+		return nil, err
+	}
+
+	if data, err := os.ReadFile(cachePath); err == nil {
+		var index Index
+		if err := json.Unmarshal(data, &index); err == nil && index.Fingerprint == fingerprint {
+			return &index, nil
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("reading completion cache: %w", err)
+	}
+
+	cat, err := catalog.LoadWithoutValidation(ctx, catalogDir)
+	if err != nil {
+		return nil, fmt.Errorf("loading catalog: %w", err)
+	}
+
+	index := NewIndex(cat)
+	index.Fingerprint = fingerprint
+
+	if data, err := json.Marshal(index); err == nil {
+		if err := os.MkdirAll(filepath.Dir(cachePath), 0o755); err == nil {
+			_ = os.WriteFile(cachePath, data, 0o644)
+		}
+	}
+
+	return index, nil
+}
+
+// getCachePath returns the path of the completion cache file of catalog, distinct for each catalog directory.
+func getCachePath(catalogDir, cacheDir string) (string, error) {
+	catalogDir, err := filepath.Abs(catalogDir)
+	if err != nil {
+		return "", fmt.Errorf("getting absolute path of %s: %w", catalogDir, err)
+	}
+	hash := sha256.Sum256([]byte(catalogDir))
+	return filepath.Join(cacheDir, "completion", hex.EncodeToString(hash[:8])+".json"), nil
+}
+
+// NewIndex returns the completion index of given catalog.
+func NewIndex(cat *catalog.Catalog) *Index {
+	index := &Index{}
+	for _, env := range cat.Environments {
+		index.Environments = append(index.Environments, env.Name)
+	}
+	for _, project := range cat.Projects {
+		index.Projects = append(index.Projects, Project{
+			Name:         project.Name,
+			Links:        sortedKeys(project.Spec.Links),
+			ReleaseLinks: sortedKeys(project.Spec.ReleaseLinks),
+		})
+	}
+	for _, crossRelease := range cat.Releases.Items {
+		for _, release := range crossRelease.Releases {
+			if release == nil || release.Environment == nil {
+				continue
+			}
+			index.Releases = append(index.Releases, Release{
+				Name:        release.Name,
+				Environment: release.Environment.Name,
+				Project:     release.Spec.Project,
+				Links:       sortedKeys(release.Spec.Links),
+			})
+		}
+	}
+	return index
+}
+
+// EnvironmentNames returns the names of all environments, in display order.
+func (index *Index) EnvironmentNames() []string {
+	return index.Environments
+}
+
+// ProjectNames returns the names of all projects.
+func (index *Index) ProjectNames() []string {
+	var names []string
+	for _, project := range index.Projects {
+		names = append(names, project.Name)
+	}
+	return names
+}
+
+// ReleaseNames returns the sorted and unique names of releases in given environments, or in all environments if
+// none are given.
+func (index *Index) ReleaseNames(environments ...string) []string {
+	var names []string
+	for _, release := range index.Releases {
+		if len(environments) > 0 && !slices.Contains(environments, release.Environment) {
+			continue
+		}
+		names = append(names, release.Name)
+	}
+	slices.Sort(names)
+	return slices.Compact(names)
+}
+
+// EnvironmentLinkNames returns the names of environment links defined in catalog configuration.
+func (index *Index) EnvironmentLinkNames(templates config.Templates) []string {
+	return sortedKeys(templates.Environment.Links)
+}
+
+// ProjectLinkNames returns the names of links of given project, or of any project if name is empty, following the
+// same precedence as links.Provider.
+func (index *Index) ProjectLinkNames(templates config.Templates, projectName string) []string {
+	names := sortedKeys(templates.Project.Links)
+	for _, project := range index.Projects {
+		if projectName == "" || project.Name == projectName {
+			names = append(names, project.Links...)
+		}
+	}
+	slices.Sort(names)
+	return slices.Compact(names)
+}
+
+// ReleaseLinkNames returns the names of links of given release in given environments, or of any release or
+// environment for empty values, following the same precedence as links.Provider.
+func (index *Index) ReleaseLinkNames(templates config.Templates, releaseName string, environments ...string) []string {
+	names := append(sortedKeys(templates.Project.Links), sortedKeys(templates.Release.Links)...)
+	for _, release := range index.Releases {
+		if releaseName != "" && release.Name != releaseName {
+			continue
+		}
+		if len(environments) > 0 && !slices.Contains(environments, release.Environment) {
+			continue
+		}
+		names = append(names, release.Links...)
+		for _, project := range index.Projects {
+			if project.Name == release.Project {
+				names = append(names, project.Links...)
+				names = append(names, project.ReleaseLinks...)
+			}
+		}
+	}
+	slices.Sort(names)
+	return slices.Compact(names)
+}
+
+// Filter returns the values starting with the value being completed, excluding those to exclude, such as arguments
+// already provided.
+func Filter(values []string, toComplete string, exclude ...string) []string {
+	var result []string
+	for _, value := range values {
+		if strings.HasPrefix(value, toComplete) && !slices.Contains(exclude, value) {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+// FilterCommaSeparated is like Filter, but for flags accepting comma-separated values, completing only the last
+// value and excluding those already listed before it.
+func FilterCommaSeparated(values []string, toComplete string) []string {
+	index := strings.LastIndex(toComplete, ",")
+	if index == -1 {
+		return Filter(values, toComplete)
+	}
+	prefix, last := toComplete[:index+1], toComplete[index+1:]
+	var result []string
+	for _, value := range Filter(values, last, strings.Split(prefix, ",")...) {
+		result = append(result, prefix+value)
+	}
+	return result
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	var keys []string
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
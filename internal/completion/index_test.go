@@ -0,0 +1,83 @@
+package completion
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/nestoca/joy/internal/config"
+	"github.com/nestoca/joy/internal/testutils"
+)
+
+func TestLoadIndex(t *testing.T) {
+	catalogDir := testutils.CopyToTempDir(t, "testdata/catalog")
+	cacheDir := t.TempDir()
+
+	index, err := LoadIndex(context.Background(), catalogDir, cacheDir)
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"dev", "staging"}, index.EnvironmentNames())
+	require.Equal(t, []string{"my-project"}, index.ProjectNames())
+	require.Equal(t, []string{"api", "worker"}, index.ReleaseNames())
+	require.Equal(t, []string{"worker"}, index.ReleaseNames("staging"))
+
+	templates := config.Templates{
+		Environment: config.EnvironmentTemplates{Links: map[string]string{"dashboard": ""}},
+		Project:     config.ProjectTemplates{Links: map[string]string{"ci": ""}},
+		Release:     config.ReleaseTemplates{Links: map[string]string{"argocd": ""}},
+	}
+	require.Equal(t, []string{"dashboard"}, index.EnvironmentLinkNames(templates))
+	require.Equal(t, []string{"ci", "repo"}, index.ProjectLinkNames(templates, "my-project"))
+	require.Equal(t, []string{"argocd", "ci", "logs", "repo", "traces"}, index.ReleaseLinkNames(templates, "api", "dev"))
+	require.Equal(t, []string{"argocd", "ci", "logs", "repo"}, index.ReleaseLinkNames(templates, "worker"))
+
+	t.Run("uses cache while catalog is unchanged", func(t *testing.T) {
+		cachePath, err := getCachePath(catalogDir, cacheDir)
+		require.NoError(t, err)
+
+		index.Environments = []string{"cached"}
+		cached := *index
+		require.NoError(t, os.MkdirAll(filepath.Dir(cachePath), 0o755))
+		require.NoError(t, os.WriteFile(cachePath, []byte(mustMarshal(t, cached)), 0o644))
+
+		index, err := LoadIndex(context.Background(), catalogDir, cacheDir)
+		require.NoError(t, err)
+		require.Equal(t, []string{"cached"}, index.EnvironmentNames())
+	})
+
+	t.Run("rebuilds cache when catalog changes", func(t *testing.T) {
+		envDir := filepath.Join(catalogDir, "environments/prod")
+		require.NoError(t, os.MkdirAll(envDir, 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(envDir, "env.yaml"), []byte(`apiVersion: joy.nesto.ca/v1alpha1
+kind: Environment
+metadata:
+  name: prod
+spec:
+  order: 3
+`), 0o644))
+
+		index, err := LoadIndex(context.Background(), catalogDir, cacheDir)
+		require.NoError(t, err)
+		require.Equal(t, []string{"dev", "staging", "prod"}, index.EnvironmentNames())
+	})
+}
+
+func TestFilter(t *testing.T) {
+	values := []string{"dev", "demo", "staging"}
+
+	require.Equal(t, []string{"dev", "demo"}, Filter(values, "d"))
+	require.Equal(t, []string{"demo"}, Filter(values, "d", "dev"))
+	require.Equal(t, []string{"staging"}, FilterCommaSeparated(values, "s"))
+	require.Equal(t, []string{"dev,demo", "dev,staging"}, FilterCommaSeparated(values, "dev,"))
+	require.Equal(t, []string{"dev,staging,demo"}, FilterCommaSeparated(values, "dev,staging,d"))
+}
+
+func mustMarshal(t *testing.T, value any) string {
+	data, err := json.Marshal(value)
+	require.NoError(t, err)
+	return string(data)
+}
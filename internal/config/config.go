@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 
 	"github.com/davidmdm/x/xerr"
 	"golang.org/x/mod/semver"
@@ -61,6 +62,12 @@ type Catalog struct {
 
 	Templates Templates `yaml:"templates,omitempty"`
 
+	// Changelog configures how changelogs are generated from the commits of promoted releases.
+	Changelog Changelog `yaml:"changelog,omitempty"`
+
+	// PullRequests configures the service hosting the catalog repository, where promotion pull requests are created.
+	PullRequests PullRequests `yaml:"pullRequests,omitempty"`
+
 	Helps map[string][]Help `yaml:"help,omitempty"`
 }
 
@@ -110,7 +117,10 @@ type ProjectTemplates struct {
 
 type ReleaseTemplates struct {
 	Promote ReleasePromoteTemplates `yaml:"promote,omitempty"`
-	Links   map[string]string       `yaml:"links,omitempty"`
+
+	// Links are templates with access to .Environment, .Project, .Release, .Repository, .GitTag and .Values, the
+	// latter being the hydrated release values, including chart mappings.
+	Links map[string]string `yaml:"links,omitempty"`
 }
 
 type ReleasePromoteTemplates struct {
@@ -118,6 +128,53 @@ type ReleasePromoteTemplates struct {
 	PullRequest string `yaml:"pullRequest,omitempty"`
 }
 
+type Changelog struct {
+	// IssueKeyPattern is the regular expression used to extract issue keys from commit messages.
+	// Defaults to matching Jira-style keys, such as "PROJ-123".
+	IssueKeyPattern string `yaml:"issueKeyPattern,omitempty"`
+}
+
+const (
+	PullRequestProviderGitHub = "github"
+	PullRequestProviderGitLab = "gitlab"
+	PullRequestProviderGitea  = "gitea"
+)
+
+type PullRequests struct {
+	// Provider is the service hosting the catalog repository: github (default), gitlab or gitea.
+	Provider string `yaml:"provider,omitempty"`
+
+	// URL is the base URL of the service, ie: https://gitea.example.com.
+	// Defaults to https://gitlab.com for gitlab and is required for gitea.
+	URL string `yaml:"url,omitempty"`
+
+	// Repository is the path of the catalog repository on the service, ie: group/catalog.
+	// Required for gitlab and gitea.
+	Repository string `yaml:"repository,omitempty"`
+
+	// TokenEnv is the environment variable holding the API token.
+	// Defaults to GITLAB_TOKEN for gitlab and GITEA_TOKEN for gitea.
+	TokenEnv string `yaml:"tokenEnv,omitempty"`
+}
+
+func (prs PullRequests) Validate() error {
+	switch prs.Provider {
+	case "", PullRequestProviderGitHub:
+		return nil
+	case PullRequestProviderGitLab:
+	case PullRequestProviderGitea:
+		if prs.URL == "" {
+			return fmt.Errorf("url is required for %s provider", prs.Provider)
+		}
+	default:
+		return fmt.Errorf("unknown provider %q: must be one of %s, %s or %s", prs.Provider, PullRequestProviderGitHub, PullRequestProviderGitLab, PullRequestProviderGitea)
+	}
+	if prs.Repository == "" {
+		return fmt.Errorf("repository is required for %s provider", prs.Provider)
+	}
+	return nil
+}
+
 type Help struct {
 	// ErrorPattern is an optional regex pattern to match against the error message to determine if this help message should be displayed.
 	ErrorPattern string `yaml:"error,omitempty"`
@@ -212,6 +269,16 @@ func (cfg Config) Validate() error {
 		return fmt.Errorf("invalid minimum version: %s", cfg.MinVersion)
 	}
 
+	if pattern := cfg.Changelog.IssueKeyPattern; pattern != "" {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid changelog issue key pattern: %w", err)
+		}
+	}
+
+	if err := cfg.PullRequests.Validate(); err != nil {
+		return fmt.Errorf("invalid pull requests config: %w", err)
+	}
+
 	return nil
 }
 
@@ -0,0 +1,238 @@
+package environment
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/nestoca/joy/api/v1alpha1"
+	"github.com/nestoca/joy/internal/style"
+	"github.com/nestoca/joy/pkg/catalog"
+	"github.com/nestoca/joy/pkg/helm"
+)
+
+type DiffParams struct {
+	Source string
+	Target string
+
+	// Charts resolves the chart and chart version of each release, as compared between environments.
+	Charts helm.ChartCache
+}
+
+// Diff describes the differences between two environments. Releases missing from either side are only reported
+// as such, while version and chart differences are only reported for releases present on both sides.
+type Diff struct {
+	Source         string       `json:"source"`
+	Target         string       `json:"target"`
+	OnlyInSource   []string     `json:"onlyInSource"`
+	OnlyInTarget   []string     `json:"onlyInTarget"`
+	Versions       []Difference `json:"versions"`
+	Charts         []Difference `json:"charts"`
+	ChartOverrides []Difference `json:"chartOverrides"`
+	Values         []Difference `json:"values"`
+}
+
+// Difference is a value that differs between source and target environments, identified by key, which is either a
+// release name, a chart reference or a dot-separated environment values path. Empty values are missing on that side.
+type Difference struct {
+	Key    string `json:"key"`
+	Source string `json:"source"`
+	Target string `json:"target"`
+}
+
+func (diff Diff) IsEmpty() bool {
+	return len(diff.OnlyInSource) == 0 &&
+		len(diff.OnlyInTarget) == 0 &&
+		len(diff.Versions) == 0 &&
+		len(diff.Charts) == 0 &&
+		len(diff.ChartOverrides) == 0 &&
+		len(diff.Values) == 0
+}
+
+// Compare returns the differences between source and target environments of catalog.
+func Compare(cat *catalog.Catalog, params DiffParams) (Diff, error) {
+	source := FindByName(cat.Environments, params.Source)
+	if source == nil {
+		return Diff{}, fmt.Errorf("environment %s not found", params.Source)
+	}
+	target := FindByName(cat.Environments, params.Target)
+	if target == nil {
+		return Diff{}, fmt.Errorf("environment %s not found", params.Target)
+	}
+
+	diff := Diff{
+		Source:         source.Name,
+		Target:         target.Name,
+		OnlyInSource:   []string{},
+		OnlyInTarget:   []string{},
+		Versions:       []Difference{},
+		Charts:         []Difference{},
+		ChartOverrides: compareMaps(source.Spec.ChartVersions, target.Spec.ChartVersions),
+		Values:         compareValues(source.Spec.Values, target.Spec.Values),
+	}
+
+	charts := params.Charts
+	sourceIndex := cat.Releases.GetEnvironmentIndex(source)
+	targetIndex := cat.Releases.GetEnvironmentIndex(target)
+
+	for _, crossRelease := range cat.Releases.SortedCrossReleases() {
+		sourceRelease := crossRelease.Releases[sourceIndex]
+		targetRelease := crossRelease.Releases[targetIndex]
+
+		switch {
+		case sourceRelease == nil && targetRelease == nil:
+			continue
+		case targetRelease == nil:
+			diff.OnlyInSource = append(diff.OnlyInSource, crossRelease.Name)
+			continue
+		case sourceRelease == nil:
+			diff.OnlyInTarget = append(diff.OnlyInTarget, crossRelease.Name)
+			continue
+		}
+
+		if sourceRelease.Spec.Version != targetRelease.Spec.Version {
+			diff.Versions = append(diff.Versions, Difference{
+				Key:    crossRelease.Name,
+				Source: sourceRelease.Spec.Version,
+				Target: targetRelease.Spec.Version,
+			})
+		}
+
+		sourceChart, err := describeChart(charts, sourceRelease)
+		if err != nil {
+			return Diff{}, fmt.Errorf("resolving chart of release %s in %s: %w", crossRelease.Name, source.Name, err)
+		}
+		targetChart, err := describeChart(charts, targetRelease)
+		if err != nil {
+			return Diff{}, fmt.Errorf("resolving chart of release %s in %s: %w", crossRelease.Name, target.Name, err)
+		}
+		if sourceChart != targetChart {
+			diff.Charts = append(diff.Charts, Difference{Key: crossRelease.Name, Source: sourceChart, Target: targetChart})
+		}
+	}
+
+	return diff, nil
+}
+
+// describeChart returns the catalog chart reference or the repository URL and name of release chart, along with its
+// version resolved from release, environment overrides and catalog charts.
+func describeChart(charts helm.ChartCache, release *v1alpha1.Release) (string, error) {
+	chart, err := charts.GetReleaseChart(release)
+	if err != nil {
+		return "", err
+	}
+	name := cmp.Or(release.Spec.Chart.Ref, charts.DefaultChartRef)
+	if release.Spec.Chart.RepoUrl != "" {
+		name = strings.TrimSuffix(chart.RepoURL, "/") + "/" + chart.Name
+	}
+	if chart.Version == "" {
+		return name, nil
+	}
+	return name + "@" + chart.Version, nil
+}
+
+func compareMaps(source, target map[string]string) []Difference {
+	differences := []Difference{}
+	for _, key := range sortedKeys(source, target) {
+		if source[key] != target[key] {
+			differences = append(differences, Difference{Key: key, Source: source[key], Target: target[key]})
+		}
+	}
+	return differences
+}
+
+// compareValues returns the differences between source and target values, recursing into maps present on both sides
+// and comparing any other values as a whole.
+func compareValues(source, target map[string]any) []Difference {
+	differences := []Difference{}
+	var compare func(prefix string, source, target map[string]any)
+	compare = func(prefix string, source, target map[string]any) {
+		for _, key := range sortedKeys(source, target) {
+			sourceValue, targetValue := source[key], target[key]
+			sourceMap, sourceIsMap := sourceValue.(map[string]any)
+			targetMap, targetIsMap := targetValue.(map[string]any)
+			if sourceIsMap && targetIsMap {
+				compare(prefix+key+".", sourceMap, targetMap)
+				continue
+			}
+			if !reflect.DeepEqual(sourceValue, targetValue) {
+				differences = append(differences, Difference{
+					Key:    prefix + key,
+					Source: formatValue(sourceValue),
+					Target: formatValue(targetValue),
+				})
+			}
+		}
+	}
+	compare("", source, target)
+	return differences
+}
+
+func formatValue(value any) string {
+	if value == nil {
+		return ""
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+func sortedKeys[V any](maps ...map[string]V) []string {
+	var keys []string
+	for _, m := range maps {
+		for key := range m {
+			if !slices.Contains(keys, key) {
+				keys = append(keys, key)
+			}
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// FormatDiff writes a report of given differences, section by section.
+func FormatDiff(w io.Writer, diff Diff) {
+	if diff.IsEmpty() {
+		_, _ = fmt.Fprintf(w, "✅ Environments %s and %s match\n", style.Resource(diff.Source), style.Resource(diff.Target))
+		return
+	}
+
+	_, _ = fmt.Fprintf(w, "🔍 Comparing environments %s and %s\n", style.Resource(diff.Source), style.Resource(diff.Target))
+
+	formatNames := func(title string, names []string) {
+		if len(names) == 0 {
+			return
+		}
+		_, _ = fmt.Fprintf(w, "\n%s\n", title)
+		for _, name := range names {
+			_, _ = fmt.Fprintf(w, "  - %s\n", name)
+		}
+	}
+	formatNames(fmt.Sprintf("Releases only in %s:", diff.Source), diff.OnlyInSource)
+	formatNames(fmt.Sprintf("Releases only in %s:", diff.Target), diff.OnlyInTarget)
+
+	formatDifferences := func(title, keyHeader string, differences []Difference) {
+		if len(differences) == 0 {
+			return
+		}
+		_, _ = fmt.Fprintf(w, "\n%s\n", title)
+
+		table := style.NewTable(w)
+
+		table.SetHeader([]string{"  " + keyHeader, strings.ToUpper(diff.Source), strings.ToUpper(diff.Target)})
+		for _, difference := range differences {
+			table.Append([]string{"  " + difference.Key, orNone(difference.Source), orNone(difference.Target)})
+		}
+		table.Render()
+	}
+	formatDifferences("Version differences:", "RELEASE", diff.Versions)
+	formatDifferences("Chart differences:", "RELEASE", diff.Charts)
+	formatDifferences("Chart version overrides:", "CHART", diff.ChartOverrides)
+	formatDifferences("Environment values:", "PATH", diff.Values)
+}
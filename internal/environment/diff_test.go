@@ -0,0 +1,82 @@
+package environment
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/acarl005/stripansi"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nestoca/joy/pkg/catalog"
+	"github.com/nestoca/joy/pkg/helm"
+)
+
+func TestCompare(t *testing.T) {
+	cat, err := catalog.Load(context.Background(), "testdata/diff", []string{"generic"})
+	require.NoError(t, err)
+
+	params := DiffParams{
+		Source: "prod",
+		Target: "prod-west",
+		Charts: helm.ChartCache{Refs: map[string]helm.Chart{
+			"generic": {RepoURL: "oci://registry.example.com/charts", Name: "generic", Version: "1.0.0"},
+		}},
+	}
+
+	diff, err := Compare(cat, params)
+	require.NoError(t, err)
+	require.Equal(t, Diff{
+		Source:       "prod",
+		Target:       "prod-west",
+		OnlyInSource: []string{},
+		OnlyInTarget: []string{"web"},
+		Versions:     []Difference{{Key: "api", Source: "1.1.0", Target: "1.0.0"}},
+		Charts: []Difference{
+			{Key: "worker", Source: "generic@1.0.0", Target: "generic@2.0.0"},
+		},
+		ChartOverrides: []Difference{{Key: "generic", Source: "", Target: "2.0.0"}},
+		Values: []Difference{
+			{Key: "region", Source: `"us-east-1"`, Target: `"us-west-2"`},
+			{Key: "tracing", Source: "", Target: "true"},
+		},
+	}, diff)
+
+	var output bytes.Buffer
+	FormatDiff(&output, diff)
+	require.Equal(t, `🔍 Comparing environments prod and prod-west
+
+Releases only in prod-west:
+  - web
+
+Version differences:
+  RELEASE  PROD   PROD-WEST
+  api      1.1.0  1.0.0
+
+Chart differences:
+  RELEASE  PROD           PROD-WEST
+  worker   generic@1.0.0  generic@2.0.0
+
+Chart version overrides:
+  CHART    PROD  PROD-WEST
+  generic  -     2.0.0
+
+Environment values:
+  PATH     PROD         PROD-WEST
+  region   "us-east-1"  "us-west-2"
+  tracing  -            true
+`, trimLines(stripansi.Strip(output.String())))
+
+	params.Target = "prod"
+	diff, err = Compare(cat, params)
+	require.NoError(t, err)
+	require.True(t, diff.IsEmpty())
+
+	output.Reset()
+	FormatDiff(&output, diff)
+	require.Equal(t, "✅ Environments prod and prod match\n", stripansi.Strip(output.String()))
+
+	params.Target = "unknown"
+	_, err = Compare(cat, params)
+	require.EqualError(t, err, "environment unknown not found")
+}
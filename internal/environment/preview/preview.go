@@ -0,0 +1,345 @@
+package preview
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/nestoca/joy/api/v1alpha1"
+	"github.com/nestoca/joy/internal/style"
+	"github.com/nestoca/joy/internal/yml"
+	"github.com/nestoca/joy/pkg/catalog"
+)
+
+const (
+	// PullRequestAnnotation marks an environment as a preview of the pull request with given number.
+	PullRequestAnnotation = "joy.nesto.ca/preview-pull-request"
+
+	// SourceAnnotation is the name of the environment a preview environment was created from.
+	SourceAnnotation = "joy.nesto.ca/preview-source"
+
+	// ExpiresAtAnnotation is the RFC3339 time after which a preview environment gets cleaned up by GC.
+	ExpiresAtAnnotation = "joy.nesto.ca/preview-expires-at"
+)
+
+// Name returns the name of the preview environment for given source environment and pull request.
+func Name(source string, pullRequest int) string {
+	return fmt.Sprintf("%s-pr-%d", source, pullRequest)
+}
+
+// IsPreview returns true if given environment was created as a pull request preview.
+func IsPreview(env *v1alpha1.Environment) bool {
+	_, ok := env.Annotations[PullRequestAnnotation]
+	return ok
+}
+
+// ExpiresAt returns the expiry time of given preview environment, or false if it never expires.
+func ExpiresAt(env *v1alpha1.Environment) (time.Time, bool, error) {
+	value, ok := env.Annotations[ExpiresAtAnnotation]
+	if !ok {
+		return time.Time{}, false, nil
+	}
+	expiresAt, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid %s annotation of environment %s: %w", ExpiresAtAnnotation, env.Name, err)
+	}
+	return expiresAt, true, nil
+}
+
+type CreateOpts struct {
+	Catalog *catalog.Catalog
+	Writer  yml.Writer
+	Out     io.Writer
+
+	// PullRequest is the number of the pull request to preview.
+	PullRequest int
+
+	// Source is the name of the environment to copy releases from.
+	Source string
+
+	// Releases are the names of the releases to copy into the preview environment.
+	Releases []string
+
+	// Version is the pull request build version to set on copied releases.
+	Version string
+
+	// Owners are the owners of the preview environment, such as the pull request author, replacing those of the
+	// source environment, which do not own previews.
+	Owners []string
+
+	// TTL is the duration after which the preview environment expires. Zero means it never expires.
+	TTL time.Duration
+
+	// Now is the current time, used to compute expiry. Defaults to time.Now().
+	Now time.Time
+}
+
+// Create generates a new preview environment directory next to the source environment, with its own namespace,
+// containing copies of the chosen releases set to the pull request build version.
+func Create(opts CreateOpts) (*v1alpha1.Environment, error) {
+	if opts.PullRequest <= 0 {
+		return nil, fmt.Errorf("pull request number is required")
+	}
+	if opts.Version == "" {
+		return nil, fmt.Errorf("version is required")
+	}
+	if len(opts.Releases) == 0 {
+		return nil, fmt.Errorf("at least one release is required")
+	}
+
+	source, err := v1alpha1.GetEnvironmentByName(opts.Catalog.Environments, opts.Source)
+	if err != nil {
+		return nil, err
+	}
+	if source == nil {
+		return nil, fmt.Errorf("source environment is required")
+	}
+	if IsPreview(source) {
+		return nil, fmt.Errorf("cannot create preview from preview environment %s", source.Name)
+	}
+
+	name := Name(source.Name, opts.PullRequest)
+	for _, env := range opts.Catalog.Environments {
+		if env.Name == name {
+			return nil, fmt.Errorf("preview environment %s already exists", name)
+		}
+	}
+
+	var releases []*v1alpha1.Release
+	for _, releaseName := range opts.Releases {
+		release, err := opts.Catalog.Releases.GetEnvironmentRelease(source, releaseName)
+		if err != nil {
+			return nil, err
+		}
+		if release == nil {
+			return nil, fmt.Errorf("release %s not found in environment %s", releaseName, source.Name)
+		}
+		releases = append(releases, release)
+	}
+
+	dir := filepath.Join(filepath.Dir(source.Dir), name)
+	envTree, err := newEnvironmentTree(source, name, opts)
+	if err != nil {
+		return nil, fmt.Errorf("creating preview environment: %w", err)
+	}
+	envFile, err := yml.NewFileFromTree(filepath.Join(dir, "env.yaml"), source.File.Indent, envTree)
+	if err != nil {
+		return nil, fmt.Errorf("creating preview environment file: %w", err)
+	}
+	env, err := v1alpha1.NewEnvironment(envFile)
+	if err != nil {
+		return nil, fmt.Errorf("loading preview environment: %w", err)
+	}
+
+	releaseFiles := make([]*yml.File, len(releases))
+	for i, release := range releases {
+		releaseFiles[i], err = copyRelease(release, source.Dir, dir, opts.Version)
+		if err != nil {
+			return nil, fmt.Errorf("copying release %s: %w", release.Name, err)
+		}
+	}
+
+	for _, file := range append([]*yml.File{envFile}, releaseFiles...) {
+		if err := os.MkdirAll(filepath.Dir(file.Path), 0o755); err != nil {
+			return nil, fmt.Errorf("creating directory: %w", err)
+		}
+		if err := opts.Writer.WriteFile(file); err != nil {
+			return nil, fmt.Errorf("writing file %s: %w", file.Path, err)
+		}
+	}
+
+	_, _ = fmt.Fprintf(opts.Out, "✅ Created preview environment %s in namespace %s with %d release(s) at version %s\n",
+		style.Resource(name), style.Resource(env.Spec.Namespace), len(releases), style.Version(opts.Version))
+	if expiresAt, ok, _ := ExpiresAt(env); ok {
+		_, _ = fmt.Fprintf(opts.Out, "⏳ Expires at %s\n", style.SecondaryInfo(expiresAt.Format(time.RFC3339)))
+	}
+	return env, nil
+}
+
+// newEnvironmentTree returns a preview copy of source environment's yaml tree, preserving its comments, with its own
+// name, namespace, order and owners. Previews only accept promotions of pull request builds, as they are otherwise
+// only ever updated through their own pull request, and they are ordered after all existing environments.
+func newEnvironmentTree(source *v1alpha1.Environment, name string, opts CreateOpts) (*yaml.Node, error) {
+	order := source.Spec.Order
+	for _, env := range opts.Catalog.Environments {
+		order = max(order, env.Spec.Order)
+	}
+
+	tree := yml.Clone(source.File.Tree)
+	yml.RemoveNode(tree, "spec.promotion.fromEnvironments")
+	yml.RemoveNode(tree, "spec.promotion.allowAutoMerge")
+	yml.RemoveNode(tree, "spec.owners")
+
+	type scalar struct{ path, tag, value string }
+	scalars := []scalar{
+		{"metadata.name", "!!str", name},
+		{annotationPath(PullRequestAnnotation), "!!str", strconv.Itoa(opts.PullRequest)},
+		{annotationPath(SourceAnnotation), "!!str", source.Name},
+		{"spec.namespace", "!!str", Name(cmp.Or(source.Spec.Namespace, source.Name), opts.PullRequest)},
+		{"spec.order", "!!int", strconv.Itoa(order + 1)},
+		{"spec.promotion.fromPullRequests", "!!bool", "true"},
+	}
+	if opts.TTL > 0 {
+		now := opts.Now
+		if now.IsZero() {
+			now = time.Now()
+		}
+		scalars = append(scalars, scalar{annotationPath(ExpiresAtAnnotation), "!!str", now.Add(opts.TTL).UTC().Format(time.RFC3339)})
+	}
+	for _, scalar := range scalars {
+		if err := setScalar(tree, scalar.path, scalar.tag, scalar.value); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(opts.Owners) > 0 {
+		if err := yml.SetOrAddNodeValue(tree, "spec.owners", ""); err != nil {
+			return nil, fmt.Errorf("setting owners: %w", err)
+		}
+		owners, err := yml.FindNode(tree, "spec.owners")
+		if err != nil {
+			return nil, err
+		}
+		owners.Kind = yaml.SequenceNode
+		for _, owner := range opts.Owners {
+			owners.Content = append(owners.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: owner})
+		}
+	}
+	return tree, nil
+}
+
+// annotationPath returns the path of given annotation, escaping the dots of its name.
+func annotationPath(name string) string {
+	return "metadata.annotations." + strings.ReplaceAll(name, ".", `\.`)
+}
+
+// setScalar sets the value at given path of tree, adding it if missing, as a scalar with given tag, so that it
+// gets quoted whenever needed to preserve its type.
+func setScalar(tree *yaml.Node, path, tag, value string) error {
+	if err := yml.SetOrAddNodeValue(tree, path, value); err != nil {
+		return fmt.Errorf("setting %s: %w", path, err)
+	}
+	node, err := yml.FindNode(tree, path)
+	if err != nil {
+		return err
+	}
+	node.Kind = yaml.ScalarNode
+	node.Tag = tag
+	node.Style = 0
+	node.Content = nil
+	return nil
+}
+
+// copyRelease returns a copy of given release's file, relocated from source to target environment directory and set
+// to given version. Any digest is dropped, as it pins the image of the source version rather than the new one.
+func copyRelease(release *v1alpha1.Release, sourceDir, targetDir, version string) (*yml.File, error) {
+	relativePath, err := filepath.Rel(sourceDir, release.File.Path)
+	if err != nil {
+		return nil, fmt.Errorf("getting release file's relative path within environment: %w", err)
+	}
+
+	tree := yml.Clone(release.File.Tree)
+	if err := yml.SetOrAddNodeValue(tree, v1alpha1.ReleaseVersionPath, version); err != nil {
+		return nil, fmt.Errorf("setting version: %w", err)
+	}
+	yml.RemoveNode(tree, v1alpha1.ReleaseDigestPath)
+
+	return yml.NewFileFromTree(filepath.Join(targetDir, relativePath), release.File.Indent, tree)
+}
+
+type DestroyOpts struct {
+	Catalog *catalog.Catalog
+	Out     io.Writer
+
+	// PullRequest is the number of the pull request whose preview environments to destroy.
+	PullRequest int
+
+	// Source optionally restricts destruction to the preview created from given environment.
+	Source string
+}
+
+// Destroy removes the directories of all preview environments of given pull request.
+func Destroy(opts DestroyOpts) error {
+	if opts.PullRequest <= 0 {
+		return fmt.Errorf("pull request number is required")
+	}
+
+	var environments []*v1alpha1.Environment
+	for _, env := range opts.Catalog.Environments {
+		if env.Annotations[PullRequestAnnotation] != strconv.Itoa(opts.PullRequest) {
+			continue
+		}
+		if opts.Source != "" && env.Annotations[SourceAnnotation] != opts.Source {
+			continue
+		}
+		environments = append(environments, env)
+	}
+
+	if len(environments) == 0 {
+		return fmt.Errorf("no preview environment found for pull request #%d", opts.PullRequest)
+	}
+
+	for _, env := range environments {
+		if err := remove(env, opts.Out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type GCOpts struct {
+	Catalog *catalog.Catalog
+	Out     io.Writer
+
+	// Now is the time against which expiry is checked. Defaults to time.Now().
+	Now time.Time
+}
+
+// GC removes the directories of all preview environments whose TTL has expired.
+func GC(opts GCOpts) error {
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	count := 0
+	for _, env := range opts.Catalog.Environments {
+		if !IsPreview(env) {
+			continue
+		}
+		expiresAt, ok, err := ExpiresAt(env)
+		if err != nil {
+			return err
+		}
+		if !ok || now.Before(expiresAt) {
+			continue
+		}
+		if err := remove(env, opts.Out); err != nil {
+			return err
+		}
+		count++
+	}
+
+	if count == 0 {
+		_, _ = fmt.Fprintln(opts.Out, "🤷 No expired preview environments found.")
+	}
+	return nil
+}
+
+func remove(env *v1alpha1.Environment, out io.Writer) error {
+	if !IsPreview(env) {
+		return fmt.Errorf("refusing to remove non-preview environment %s", env.Name)
+	}
+	if err := os.RemoveAll(env.Dir); err != nil {
+		return fmt.Errorf("removing preview environment %s: %w", env.Name, err)
+	}
+	_, _ = fmt.Fprintf(out, "🗑️ Destroyed preview environment %s\n", style.Resource(env.Name))
+	return nil
+}
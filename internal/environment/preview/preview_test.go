@@ -0,0 +1,132 @@
+package preview
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/nestoca/joy/api/v1alpha1"
+	"github.com/nestoca/joy/internal/testutils"
+	"github.com/nestoca/joy/internal/yml"
+	"github.com/nestoca/joy/pkg/catalog"
+)
+
+func loadCatalog(t *testing.T, dir string) *catalog.Catalog {
+	cat, err := catalog.Load(context.Background(), dir, nil)
+	require.NoError(t, err)
+	return cat
+}
+
+func TestCreate(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		Name          string
+		Opts          CreateOpts
+		ExpectedError string
+	}{
+		{
+			Name: "create preview with ttl",
+			Opts: CreateOpts{PullRequest: 123, Source: "dev", Releases: []string{"my-service"}, Version: "1.1.0-pr123", Owners: []string{"alice"}, TTL: 48 * time.Hour, Now: now},
+		},
+		{
+			Name:          "unknown release",
+			Opts:          CreateOpts{PullRequest: 123, Source: "dev", Releases: []string{"missing"}, Version: "1.1.0-pr123"},
+			ExpectedError: "release missing not found in environment dev",
+		},
+		{
+			Name:          "missing version",
+			Opts:          CreateOpts{PullRequest: 123, Source: "dev", Releases: []string{"my-service"}},
+			ExpectedError: "version is required",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			dir := testutils.CopyToTempDir(t, "testdata/catalog")
+
+			var out bytes.Buffer
+			opts := tc.Opts
+			opts.Catalog = loadCatalog(t, dir)
+			opts.Writer = yml.DiskWriter
+			opts.Out = &out
+
+			_, err := Create(opts)
+			if tc.ExpectedError != "" {
+				require.EqualError(t, err, tc.ExpectedError)
+				return
+			}
+			require.NoError(t, err)
+
+			cat := loadCatalog(t, dir)
+			require.ElementsMatch(t, []string{"dev", "staging", "dev-pr-123"}, cat.GetEnvironmentNames())
+
+			env, err := v1alpha1.GetEnvironmentByName(cat.Environments, "dev-pr-123")
+			require.NoError(t, err)
+			require.True(t, IsPreview(env))
+			require.Equal(t, "dev-pr-123", env.Spec.Namespace)
+			require.Equal(t, "dev-cluster", env.Spec.Cluster)
+			require.Equal(t, v1alpha1.Promotion{FromPullRequests: true}, env.Spec.Promotion)
+			require.Equal(t, 3, env.Spec.Order)
+			require.Equal(t, []string{"alice"}, env.Spec.Owners)
+			require.Equal(t, "123", env.Annotations[PullRequestAnnotation])
+			require.Equal(t, "dev", env.Annotations[SourceAnnotation])
+			require.Equal(t, "2024-01-03T00:00:00Z", env.Annotations[ExpiresAtAnnotation])
+			require.Contains(t, string(env.File.Yaml), "# Shared development cluster")
+
+			release, err := cat.LookupRelease("dev-pr-123", "my-service")
+			require.NoError(t, err)
+			require.Equal(t, "1.1.0-pr123", release.Spec.Version)
+			require.Empty(t, release.Spec.Digest)
+			require.Equal(t, map[string]any{"key": "value"}, release.Spec.Values)
+
+			_, err = cat.LookupRelease("dev-pr-123", "other")
+			require.Error(t, err)
+
+			source, err := cat.LookupRelease("dev", "my-service")
+			require.NoError(t, err)
+			require.Equal(t, "1.0.0", source.Spec.Version)
+
+			require.Contains(t, out.String(), "Created preview environment")
+		})
+	}
+}
+
+func TestDestroyAndGC(t *testing.T) {
+	dir := testutils.CopyToTempDir(t, "testdata/catalog")
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var out bytes.Buffer
+	for pullRequest, ttl := range map[int]time.Duration{1: time.Hour, 2: 0, 3: 24 * time.Hour} {
+		_, err := Create(CreateOpts{
+			Catalog:     loadCatalog(t, dir),
+			Writer:      yml.DiskWriter,
+			Out:         &out,
+			PullRequest: pullRequest,
+			Source:      "dev",
+			Releases:    []string{"my-service"},
+			Version:     "1.1.0",
+			TTL:         ttl,
+			Now:         now,
+		})
+		require.NoError(t, err)
+	}
+	require.Len(t, loadCatalog(t, dir).Environments, 5)
+
+	require.EqualError(t, Destroy(DestroyOpts{Catalog: loadCatalog(t, dir), Out: &out, PullRequest: 9}), "no preview environment found for pull request #9")
+
+	require.NoError(t, Destroy(DestroyOpts{Catalog: loadCatalog(t, dir), Out: &out, PullRequest: 2}))
+	require.ElementsMatch(t, []string{"dev", "staging", "dev-pr-1", "dev-pr-3"}, loadCatalog(t, dir).GetEnvironmentNames())
+
+	out.Reset()
+	require.NoError(t, GC(GCOpts{Catalog: loadCatalog(t, dir), Out: &out, Now: now.Add(2 * time.Hour)}))
+	require.ElementsMatch(t, []string{"dev", "staging", "dev-pr-3"}, loadCatalog(t, dir).GetEnvironmentNames())
+	require.Contains(t, out.String(), "dev-pr-1")
+
+	out.Reset()
+	require.NoError(t, GC(GCOpts{Catalog: loadCatalog(t, dir), Out: &out, Now: now.Add(2 * time.Hour)}))
+	require.Contains(t, out.String(), "No expired preview environments found")
+}
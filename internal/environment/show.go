@@ -0,0 +1,134 @@
+package environment
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/nestoca/joy/api/v1alpha1"
+	"github.com/nestoca/joy/internal/style"
+	"github.com/nestoca/joy/pkg/catalog"
+)
+
+// Summary describes an environment along with the number of releases it contains.
+type Summary struct {
+	Name          string             `json:"name"`
+	Cluster       string             `json:"cluster,omitempty"`
+	Namespace     string             `json:"namespace,omitempty"`
+	Order         int                `json:"order"`
+	Promotion     v1alpha1.Promotion `json:"promotion"`
+	Owners        []string           `json:"owners,omitempty"`
+	ChartVersions map[string]string  `json:"chartVersions,omitempty"`
+	ReleaseCount  int                `json:"releaseCount"`
+}
+
+// GetSummaries returns the summaries of all environments of catalog, in display order.
+func GetSummaries(cat *catalog.Catalog) []Summary {
+	summaries := make([]Summary, len(cat.Environments))
+	for i, env := range cat.Environments {
+		summaries[i] = getSummary(cat, env)
+	}
+	return summaries
+}
+
+// GetSummary returns the summary of environment with given name.
+func GetSummary(cat *catalog.Catalog, name string) (Summary, error) {
+	env := FindByName(cat.Environments, name)
+	if env == nil {
+		return Summary{}, fmt.Errorf("environment %s not found", name)
+	}
+	return getSummary(cat, env), nil
+}
+
+func getSummary(cat *catalog.Catalog, env *v1alpha1.Environment) Summary {
+	summary := Summary{
+		Name:          env.Name,
+		Cluster:       env.Spec.Cluster,
+		Namespace:     env.Spec.Namespace,
+		Order:         env.Spec.Order,
+		Promotion:     env.Spec.Promotion,
+		Owners:        env.Spec.Owners,
+		ChartVersions: env.Spec.ChartVersions,
+	}
+	index := cat.Releases.GetEnvironmentIndexByName(env.Name)
+	if index == -1 {
+		return summary
+	}
+	for _, crossRelease := range cat.Releases.Items {
+		if crossRelease.Releases[index] != nil {
+			summary.ReleaseCount++
+		}
+	}
+	return summary
+}
+
+// FormatSummaries writes given summaries as a table.
+func FormatSummaries(w io.Writer, summaries []Summary) {
+	table := style.NewTable(w)
+
+	table.SetHeader([]string{"NAME", "ORDER", "CLUSTER", "NAMESPACE", "PROMOTES FROM", "OWNERS", "RELEASES"})
+	for _, summary := range summaries {
+		table.Append([]string{
+			summary.Name,
+			strconv.Itoa(summary.Order),
+			orNone(summary.Cluster),
+			orNone(summary.Namespace),
+			orNone(strings.Join(summary.Promotion.FromEnvironments, " ")),
+			orNone(strings.Join(summary.Owners, " ")),
+			strconv.Itoa(summary.ReleaseCount),
+		})
+	}
+	table.Render()
+}
+
+// FormatSummary writes the details of given summary.
+func FormatSummary(w io.Writer, summary Summary) {
+	field := func(label string, value any) {
+		_, _ = fmt.Fprintf(w, "%-15s %v\n", label+":", value)
+	}
+
+	field("Environment", style.Resource(summary.Name))
+	field("Cluster", orNone(summary.Cluster))
+	field("Namespace", orNone(summary.Namespace))
+	field("Order", summary.Order)
+	field("Promotion", formatPromotion(summary.Promotion))
+	field("Owners", orNone(strings.Join(summary.Owners, ", ")))
+	field("Releases", summary.ReleaseCount)
+
+	if len(summary.ChartVersions) == 0 {
+		field("Chart versions", orNone(""))
+		return
+	}
+	_, _ = fmt.Fprintln(w, "Chart versions:")
+	var refs []string
+	for ref := range summary.ChartVersions {
+		refs = append(refs, ref)
+	}
+	slices.Sort(refs)
+	for _, ref := range refs {
+		_, _ = fmt.Fprintf(w, "  %s: %s\n", ref, style.Version(summary.ChartVersions[ref]))
+	}
+}
+
+func formatPromotion(promotion v1alpha1.Promotion) string {
+	var parts []string
+	if len(promotion.FromEnvironments) > 0 {
+		parts = append(parts, "from "+strings.Join(promotion.FromEnvironments, ", "))
+	}
+	if promotion.FromPullRequests {
+		parts = append(parts, "from pull requests")
+	}
+	if promotion.AllowAutoMerge {
+		parts = append(parts, "auto-merge allowed")
+	}
+	return orNone(strings.Join(parts, ", "))
+}
+
+func orNone(value string) string {
+	if value == "" {
+		return style.SecondaryInfo("-")
+	}
+	return value
+}
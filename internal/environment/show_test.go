@@ -0,0 +1,72 @@
+package environment
+
+import (
+	"bytes"
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/acarl005/stripansi"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nestoca/joy/api/v1alpha1"
+	"github.com/nestoca/joy/pkg/catalog"
+)
+
+func TestSummaries(t *testing.T) {
+	cat, err := catalog.Load(context.Background(), "testdata/show", []string{"generic"})
+	require.NoError(t, err)
+
+	staging := Summary{
+		Name:          "staging",
+		Cluster:       "prod-cluster",
+		Order:         2,
+		Promotion:     v1alpha1.Promotion{FromEnvironments: []string{"dev"}, AllowAutoMerge: true},
+		Owners:        []string{"team-a", "team-b"},
+		ChartVersions: map[string]string{"generic": "2.0.0"},
+		ReleaseCount:  1,
+	}
+
+	summaries := GetSummaries(cat)
+	require.Len(t, summaries, 2)
+	require.Equal(t, Summary{Name: "dev", Cluster: "dev-cluster", Namespace: "dev", Order: 1, ReleaseCount: 2}, summaries[0])
+	require.Equal(t, staging, summaries[1])
+
+	summary, err := GetSummary(cat, "staging")
+	require.NoError(t, err)
+	require.Equal(t, staging, summary)
+
+	_, err = GetSummary(cat, "prod")
+	require.EqualError(t, err, "environment prod not found")
+
+	var table bytes.Buffer
+	FormatSummaries(&table, summaries)
+	require.Equal(t, `NAME     ORDER  CLUSTER       NAMESPACE  PROMOTES FROM  OWNERS         RELEASES
+dev      1      dev-cluster   dev        -              -              2
+staging  2      prod-cluster  -          dev            team-a team-b  1
+`, trimLines(stripansi.Strip(table.String())))
+
+	var details bytes.Buffer
+	FormatSummary(&details, staging)
+	require.Equal(t, `Environment:    staging
+Cluster:        prod-cluster
+Namespace:      -
+Order:          2
+Promotion:      from dev, auto-merge allowed
+Owners:         team-a, team-b
+Releases:       1
+Chart versions:
+  generic: 2.0.0
+`, trimLines(stripansi.Strip(details.String())))
+}
+
+func TestFormatPromotion(t *testing.T) {
+	require.Equal(t, "-", stripansi.Strip(formatPromotion(v1alpha1.Promotion{})))
+	require.Equal(t, "from pull requests", formatPromotion(v1alpha1.Promotion{FromPullRequests: true}))
+	require.Equal(t, "auto-merge allowed", formatPromotion(v1alpha1.Promotion{AllowAutoMerge: true}))
+}
+
+// trimLines removes the trailing whitespace tables pad their last column with.
+func trimLines(text string) string {
+	return regexp.MustCompile(`(?m)[ \t]+$`).ReplaceAllString(text, "")
+}
@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/nestoca/survey/v2"
 
@@ -211,6 +212,37 @@ func Pull(dir string, args ...string) error {
 	return nil
 }
 
+// Discard discards uncommitted changes to given files, whether staged or not, removing those absent from HEAD.
+func Discard(dir string, files []string) error {
+	run := func(args ...string) (string, error) {
+		output, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+		if err != nil {
+			return "", fmt.Errorf("%w: %s", err, string(output))
+		}
+		return string(output), nil
+	}
+
+	if _, err := run(append([]string{"reset", "--quiet", "--"}, files...)...); err != nil {
+		return fmt.Errorf("unstaging files: %w", err)
+	}
+	if _, err := run(append([]string{"clean", "--force", "--quiet", "--"}, files...)...); err != nil {
+		return fmt.Errorf("removing untracked files: %w", err)
+	}
+
+	output, err := run(append([]string{"ls-files", "-z", "--"}, files...)...)
+	if err != nil {
+		return fmt.Errorf("listing tracked files: %w", err)
+	}
+	tracked := strings.FieldsFunc(output, func(r rune) bool { return r == 0 })
+	if len(tracked) == 0 {
+		return nil
+	}
+	if _, err := run(append([]string{"checkout", "HEAD", "--"}, tracked...)...); err != nil {
+		return fmt.Errorf("restoring tracked files: %w", err)
+	}
+	return nil
+}
+
 func Diff(dir string, ref string) ([]string, error) {
 	cmd := exec.Command("git", "-C", dir, "diff", "--name-only", ref)
 
@@ -308,3 +340,49 @@ func FetchTags(dir string) error {
 
 	return nil
 }
+
+// FileCommit identifies a commit of a file and when it was committed.
+type FileCommit struct {
+	Sha  string
+	Time time.Time
+}
+
+// GetFileCommits returns the commits adding or removing lines of given file that match given regular expression, from
+// most to least recent, or none if the file was never committed.
+func GetFileCommits(dir, file, pattern string) ([]FileCommit, error) {
+	cmd := exec.Command("git", "-C", dir, "log", "--format=%H %cI", "-G", pattern, "--", file)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("getting commits of %s: %s", file, string(output))
+	}
+
+	var commits []FileCommit
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		sha, value, ok := strings.Cut(line, " ")
+		if !ok {
+			continue
+		}
+		commitTime, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, fmt.Errorf("parsing time of commit %s: %w", sha, err)
+		}
+		commits = append(commits, FileCommit{Sha: sha, Time: commitTime})
+	}
+	return commits, nil
+}
+
+// GetFileAtRevision returns the content of given file, relative to dir, at given revision, or false if the file does
+// not exist at that revision.
+func GetFileAtRevision(dir, revision, file string) ([]byte, bool, error) {
+	cmd := exec.Command("git", "-C", dir, "cat-file", "-e", revision+":./"+file)
+	if err := cmd.Run(); err != nil {
+		return nil, false, nil
+	}
+
+	cmd = exec.Command("git", "-C", dir, "show", revision+":./"+file)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, false, fmt.Errorf("getting %s at revision %s: %w", file, revision, err)
+	}
+	return output, true, nil
+}
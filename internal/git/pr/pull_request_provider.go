@@ -1,5 +1,54 @@
 package pr
 
+import "strings"
+
+// promotionLabelPrefix prefixes the labels of pull requests whose builds are auto-promoted to a given environment.
+const promotionLabelPrefix = "promote:"
+
+const (
+	// environmentLabelPrefix prefixes the labels of release promotion pull requests targeting a given environment.
+	environmentLabelPrefix = "environment:"
+
+	// releaseLabelPrefix prefixes the labels of release promotion pull requests promoting a given release.
+	releaseLabelPrefix = "release:"
+)
+
+// AutoMergeLabel is the label of release promotion pull requests that should be merged automatically once ready.
+const AutoMergeLabel = "auto-merge"
+
+// EnvironmentLabel returns the label of release promotion pull requests targeting given environment.
+func EnvironmentLabel(env string) string {
+	return environmentLabelPrefix + env
+}
+
+// ParseEnvironmentLabel returns the environment that given label targets, if it is an environment label.
+func ParseEnvironmentLabel(label string) (env string, ok bool) {
+	env, ok = strings.CutPrefix(label, environmentLabelPrefix)
+	return env, ok && env != ""
+}
+
+// ReleaseLabel returns the label of release promotion pull requests promoting given release.
+func ReleaseLabel(release string) string {
+	return releaseLabelPrefix + release
+}
+
+// ParseReleaseLabel returns the release that given label promotes, if it is a release label.
+func ParseReleaseLabel(label string) (release string, ok bool) {
+	release, ok = strings.CutPrefix(label, releaseLabelPrefix)
+	return release, ok && release != ""
+}
+
+// PromotionLabel returns the label for auto-promoting builds of a pull request to given environment.
+func PromotionLabel(env string) string {
+	return promotionLabelPrefix + env
+}
+
+// ParsePromotionLabel returns the environment that given label auto-promotes builds to, if it is a promotion label.
+func ParsePromotionLabel(label string) (env string, ok bool) {
+	env, ok = strings.CutPrefix(label, promotionLabelPrefix)
+	return env, ok && env != ""
+}
+
 type CreateParams struct {
 	Branch    string
 	Title     string
@@ -9,6 +58,63 @@ type CreateParams struct {
 	Draft     bool
 }
 
+// PullRequest describes an open pull request.
+type PullRequest struct {
+	Number int      `json:"number"`
+	URL    string   `json:"url"`
+	Title  string   `json:"title"`
+	Branch string   `json:"branch"`
+	Labels []string `json:"labels"`
+}
+
+// State is the lifecycle state of a pull request.
+type State string
+
+const (
+	StateOpen   State = "open"
+	StateMerged State = "merged"
+	StateClosed State = "closed"
+)
+
+// ChecksState summarizes the results of the CI checks of a pull request's head commit.
+type ChecksState string
+
+const (
+	ChecksNone    ChecksState = "none"
+	ChecksPending ChecksState = "pending"
+	ChecksSuccess ChecksState = "success"
+	ChecksFailure ChecksState = "failure"
+)
+
+// ReviewState summarizes the reviews of a pull request.
+type ReviewState string
+
+const (
+	ReviewRequired         ReviewState = "review_required"
+	ReviewApproved         ReviewState = "approved"
+	ReviewChangesRequested ReviewState = "changes_requested"
+)
+
+// Status describes the current state of a pull request along with its checks, reviews and auto-merge.
+type Status struct {
+	PullRequest
+	State     State       `json:"state"`
+	Checks    ChecksState `json:"checks"`
+	Review    ReviewState `json:"review"`
+	AutoMerge bool        `json:"autoMerge"`
+}
+
+// IsFinal returns whether the pull request has either been merged or closed, or has failing checks that
+// require intervention before it can be merged.
+func (s Status) IsFinal() bool {
+	return s.State != StateOpen || s.Checks == ChecksFailure
+}
+
+// IsFailed returns whether the pull request was closed without being merged or has failing checks.
+func (s Status) IsFailed() bool {
+	return s.State == StateClosed || (s.State == StateOpen && s.Checks == ChecksFailure)
+}
+
 //go:generate moq -stub -out ./pull_request_provider_mock.go . PullRequestProvider
 type PullRequestProvider interface {
 	// EnsureInstalledAndAuthenticated ensures the service provider is installed and authorized.
@@ -27,6 +133,15 @@ type PullRequestProvider interface {
 	// Create creates a pull request for given branch
 	Create(CreateParams) (string, error)
 
+	// ListOpen returns the open pull requests having all given labels.
+	ListOpen(labels ...string) ([]PullRequest, error)
+
+	// GetStatus returns the current status of the pull request with given number, whether open or not.
+	GetStatus(number int) (*Status, error)
+
+	// Close closes the pull request with given number, leaving given comment and deleting its branch.
+	Close(number int, comment string) error
+
 	// GetPromotionEnvironment returns the environment to promote builds of given branch's pull request to.
 	// If empty string is returned, promotion is disabled.
 	GetPromotionEnvironment(branch string) (string, error)
@@ -17,6 +17,9 @@ var _ PullRequestProvider = &PullRequestProviderMock{}
 //
 //		// make and configure a mocked PullRequestProvider
 //		mockedPullRequestProvider := &PullRequestProviderMock{
+//			CloseFunc: func(number int, comment string) error {
+//				panic("mock out the Close method")
+//			},
 //			CreateFunc: func(createParams CreateParams) (string, error) {
 //				panic("mock out the Create method")
 //			},
@@ -35,6 +38,12 @@ var _ PullRequestProvider = &PullRequestProviderMock{}
 //			GetPromotionEnvironmentFunc: func(branch string) (string, error) {
 //				panic("mock out the GetPromotionEnvironment method")
 //			},
+//			GetStatusFunc: func(number int) (*Status, error) {
+//				panic("mock out the GetStatus method")
+//			},
+//			ListOpenFunc: func(labels ...string) ([]PullRequest, error) {
+//				panic("mock out the ListOpen method")
+//			},
 //			SetPromotionEnvironmentFunc: func(branch string, env string) error {
 //				panic("mock out the SetPromotionEnvironment method")
 //			},
@@ -45,6 +54,9 @@ var _ PullRequestProvider = &PullRequestProviderMock{}
 //
 //	}
 type PullRequestProviderMock struct {
+	// CloseFunc mocks the Close method.
+	CloseFunc func(number int, comment string) error
+
 	// CreateFunc mocks the Create method.
 	CreateFunc func(createParams CreateParams) (string, error)
 
@@ -63,11 +75,24 @@ type PullRequestProviderMock struct {
 	// GetPromotionEnvironmentFunc mocks the GetPromotionEnvironment method.
 	GetPromotionEnvironmentFunc func(branch string) (string, error)
 
+	// GetStatusFunc mocks the GetStatus method.
+	GetStatusFunc func(number int) (*Status, error)
+
+	// ListOpenFunc mocks the ListOpen method.
+	ListOpenFunc func(labels ...string) ([]PullRequest, error)
+
 	// SetPromotionEnvironmentFunc mocks the SetPromotionEnvironment method.
 	SetPromotionEnvironmentFunc func(branch string, env string) error
 
 	// calls tracks calls to the methods.
 	calls struct {
+		// Close holds details about calls to the Close method.
+		Close []struct {
+			// Number is the number argument value.
+			Number int
+			// Comment is the comment argument value.
+			Comment string
+		}
 		// Create holds details about calls to the Create method.
 		Create []struct {
 			// CreateParams is the createParams argument value.
@@ -96,6 +121,16 @@ type PullRequestProviderMock struct {
 			// Branch is the branch argument value.
 			Branch string
 		}
+		// GetStatus holds details about calls to the GetStatus method.
+		GetStatus []struct {
+			// Number is the number argument value.
+			Number int
+		}
+		// ListOpen holds details about calls to the ListOpen method.
+		ListOpen []struct {
+			// Labels is the labels argument value.
+			Labels []string
+		}
 		// SetPromotionEnvironment holds details about calls to the SetPromotionEnvironment method.
 		SetPromotionEnvironment []struct {
 			// Branch is the branch argument value.
@@ -104,15 +139,57 @@ type PullRequestProviderMock struct {
 			Env string
 		}
 	}
+	lockClose                             sync.RWMutex
 	lockCreate                            sync.RWMutex
 	lockCreateInteractively               sync.RWMutex
 	lockEnsureInstalledAndAuthenticated   sync.RWMutex
 	lockExists                            sync.RWMutex
 	lockGetBranchesPromotingToEnvironment sync.RWMutex
 	lockGetPromotionEnvironment           sync.RWMutex
+	lockGetStatus                         sync.RWMutex
+	lockListOpen                          sync.RWMutex
 	lockSetPromotionEnvironment           sync.RWMutex
 }
 
+// Close calls CloseFunc.
+func (mock *PullRequestProviderMock) Close(number int, comment string) error {
+	callInfo := struct {
+		Number  int
+		Comment string
+	}{
+		Number:  number,
+		Comment: comment,
+	}
+	mock.lockClose.Lock()
+	mock.calls.Close = append(mock.calls.Close, callInfo)
+	mock.lockClose.Unlock()
+	if mock.CloseFunc == nil {
+		var (
+			errOut error
+		)
+		return errOut
+	}
+	return mock.CloseFunc(number, comment)
+}
+
+// CloseCalls gets all the calls that were made to Close.
+// Check the length with:
+//
+//	len(mockedPullRequestProvider.CloseCalls())
+func (mock *PullRequestProviderMock) CloseCalls() []struct {
+	Number  int
+	Comment string
+} {
+	var calls []struct {
+		Number  int
+		Comment string
+	}
+	mock.lockClose.RLock()
+	calls = mock.calls.Close
+	mock.lockClose.RUnlock()
+	return calls
+}
+
 // Create calls CreateFunc.
 func (mock *PullRequestProviderMock) Create(createParams CreateParams) (string, error) {
 	callInfo := struct {
@@ -322,6 +399,78 @@ func (mock *PullRequestProviderMock) GetPromotionEnvironmentCalls() []struct {
 	return calls
 }
 
+// GetStatus calls GetStatusFunc.
+func (mock *PullRequestProviderMock) GetStatus(number int) (*Status, error) {
+	callInfo := struct {
+		Number int
+	}{
+		Number: number,
+	}
+	mock.lockGetStatus.Lock()
+	mock.calls.GetStatus = append(mock.calls.GetStatus, callInfo)
+	mock.lockGetStatus.Unlock()
+	if mock.GetStatusFunc == nil {
+		var (
+			statusOut *Status
+			errOut    error
+		)
+		return statusOut, errOut
+	}
+	return mock.GetStatusFunc(number)
+}
+
+// GetStatusCalls gets all the calls that were made to GetStatus.
+// Check the length with:
+//
+//	len(mockedPullRequestProvider.GetStatusCalls())
+func (mock *PullRequestProviderMock) GetStatusCalls() []struct {
+	Number int
+} {
+	var calls []struct {
+		Number int
+	}
+	mock.lockGetStatus.RLock()
+	calls = mock.calls.GetStatus
+	mock.lockGetStatus.RUnlock()
+	return calls
+}
+
+// ListOpen calls ListOpenFunc.
+func (mock *PullRequestProviderMock) ListOpen(labels ...string) ([]PullRequest, error) {
+	callInfo := struct {
+		Labels []string
+	}{
+		Labels: labels,
+	}
+	mock.lockListOpen.Lock()
+	mock.calls.ListOpen = append(mock.calls.ListOpen, callInfo)
+	mock.lockListOpen.Unlock()
+	if mock.ListOpenFunc == nil {
+		var (
+			pullRequestsOut []PullRequest
+			errOut          error
+		)
+		return pullRequestsOut, errOut
+	}
+	return mock.ListOpenFunc(labels...)
+}
+
+// ListOpenCalls gets all the calls that were made to ListOpen.
+// Check the length with:
+//
+//	len(mockedPullRequestProvider.ListOpenCalls())
+func (mock *PullRequestProviderMock) ListOpenCalls() []struct {
+	Labels []string
+} {
+	var calls []struct {
+		Labels []string
+	}
+	mock.lockListOpen.RLock()
+	calls = mock.calls.ListOpen
+	mock.lockListOpen.RUnlock()
+	return calls
+}
+
 // SetPromotionEnvironment calls SetPromotionEnvironmentFunc.
 func (mock *PullRequestProviderMock) SetPromotionEnvironment(branch string, env string) error {
 	callInfo := struct {
@@ -0,0 +1,458 @@
+package gitea
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/browser"
+
+	"github.com/nestoca/joy/internal/git/pr"
+	"github.com/nestoca/joy/internal/rest"
+	"github.com/nestoca/joy/internal/style"
+)
+
+// TokenEnvVar is the default environment variable holding the Gitea API token.
+const TokenEnvVar = "GITEA_TOKEN"
+
+const (
+	pageSize     = 50
+	defaultColor = "#ededed"
+)
+
+// PullRequestProvider manages Gitea pull requests of a given repository via the Gitea REST API.
+type PullRequestProvider struct {
+	client     *rest.Client
+	url        string
+	repository string
+	token      string
+}
+
+// NewPullRequestProvider returns a provider for the pull requests of given repository (ie: "owner/catalog")
+// on the Gitea instance at given base URL, authenticating with given token.
+func NewPullRequestProvider(baseURL, repository, token string) *PullRequestProvider {
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	header := http.Header{}
+	if token != "" {
+		header.Set("Authorization", "token "+token)
+	}
+	return &PullRequestProvider{
+		client:     rest.NewClient(baseURL+"/api/v1", header),
+		url:        baseURL,
+		repository: repository,
+		token:      token,
+	}
+}
+
+type label struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+type pullRequest struct {
+	Number  int    `json:"number"`
+	HTMLURL string `json:"html_url"`
+	Title   string `json:"title"`
+	State   string `json:"state"`
+	Merged  bool   `json:"merged"`
+	Head    struct {
+		Ref string `json:"ref"`
+		SHA string `json:"sha"`
+	} `json:"head"`
+	Labels []label `json:"labels"`
+}
+
+type combinedStatus struct {
+	State      string `json:"state"`
+	TotalCount int    `json:"total_count"`
+}
+
+type review struct {
+	User struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	State     string `json:"state"`
+	Dismissed bool   `json:"dismissed"`
+}
+
+type repository struct {
+	DefaultBranch string `json:"default_branch"`
+}
+
+func (p *PullRequestProvider) EnsureInstalledAndAuthenticated() error {
+	if p.token == "" {
+		fmt.Printf("🔐 Please set the %s environment variable to a Gitea access token.\n", style.Code(TokenEnvVar))
+		return errors.New("gitea token not set")
+	}
+	if err := p.client.Do(http.MethodGet, "user", nil, nil); err != nil {
+		return fmt.Errorf("authenticating with gitea: %w", err)
+	}
+	return nil
+}
+
+func (p *PullRequestProvider) Exists(branch string) (bool, error) {
+	pullRequest, err := p.get(branch)
+	if err != nil {
+		return false, fmt.Errorf("getting pull request for branch %s: %w", branch, err)
+	}
+	return pullRequest != nil, nil
+}
+
+func (p *PullRequestProvider) GetBranchesPromotingToEnvironment(env string) ([]string, error) {
+	prs, err := p.listOpen(pr.PromotionLabel(env))
+	if err != nil {
+		return nil, fmt.Errorf("getting pull requests: %w", err)
+	}
+
+	var branches []string
+	for _, pullRequest := range prs {
+		branches = append(branches, pullRequest.Head.Ref)
+	}
+	return branches, nil
+}
+
+func (p *PullRequestProvider) CreateInteractively(branch string) error {
+	repo, err := p.getRepository()
+	if err != nil {
+		return err
+	}
+
+	compareURL := fmt.Sprintf("%s/%s/compare/%s...%s", p.url, p.repository, repo.DefaultBranch, branch)
+	if err := browser.OpenURL(compareURL); err != nil {
+		return fmt.Errorf("opening pull request creation page for branch %s: %w", branch, err)
+	}
+	return nil
+}
+
+func (p *PullRequestProvider) Create(params pr.CreateParams) (string, error) {
+	repo, err := p.getRepository()
+	if err != nil {
+		return "", err
+	}
+
+	labelIDs, err := p.ensureLabels(params.Labels...)
+	if err != nil {
+		return "", err
+	}
+
+	title := params.Title
+	if params.Draft {
+		title = "WIP: " + title
+	}
+
+	body := map[string]any{
+		"head":   params.Branch,
+		"base":   repo.DefaultBranch,
+		"title":  title,
+		"body":   params.Body,
+		"labels": labelIDs,
+	}
+
+	var pullRequest pullRequest
+	if err := p.client.Do(http.MethodPost, p.repoPath("pulls"), body, &pullRequest); err != nil {
+		return "", fmt.Errorf("creating pull request for branch %s: %w", params.Branch, err)
+	}
+
+	reviewers, err := p.getKnownUsers(params.Reviewers)
+	if err != nil {
+		return "", fmt.Errorf("getting reviewers: %w", err)
+	}
+	if len(reviewers) > 0 {
+		reviewersPath := p.repoPath(fmt.Sprintf("pulls/%d/requested_reviewers", pullRequest.Number))
+		if err := p.client.Do(http.MethodPost, reviewersPath, map[string]any{"reviewers": reviewers}, nil); err != nil {
+			return "", fmt.Errorf("requesting reviewers for pull request #%d: %w", pullRequest.Number, err)
+		}
+	}
+
+	return pullRequest.HTMLURL, nil
+}
+
+func (p *PullRequestProvider) ListOpen(labels ...string) ([]pr.PullRequest, error) {
+	prs, err := p.listOpen(labels...)
+	if err != nil {
+		return nil, fmt.Errorf("listing pull requests with labels %s: %w", strings.Join(labels, ", "), err)
+	}
+
+	result := make([]pr.PullRequest, len(prs))
+	for i, pullRequest := range prs {
+		result[i] = pr.PullRequest{
+			Number: pullRequest.Number,
+			URL:    pullRequest.HTMLURL,
+			Title:  pullRequest.Title,
+			Branch: pullRequest.Head.Ref,
+			Labels: pullRequest.labelNames(),
+		}
+	}
+	return result, nil
+}
+
+func (p *PullRequestProvider) GetStatus(number int) (*pr.Status, error) {
+	pullPath := p.repoPath("pulls/" + strconv.Itoa(number))
+
+	var pullRequest pullRequest
+	if err := p.client.Do(http.MethodGet, pullPath, nil, &pullRequest); err != nil {
+		return nil, fmt.Errorf("getting pull request #%d: %w", number, err)
+	}
+
+	var combined combinedStatus
+	if err := p.client.Do(http.MethodGet, p.repoPath("commits/"+pullRequest.Head.SHA+"/status"), nil, &combined); err != nil {
+		return nil, fmt.Errorf("getting checks of pull request #%d: %w", number, err)
+	}
+
+	var reviews []review
+	if err := p.client.Do(http.MethodGet, pullPath+"/reviews", nil, &reviews); err != nil {
+		return nil, fmt.Errorf("getting reviews of pull request #%d: %w", number, err)
+	}
+
+	status := &pr.Status{
+		PullRequest: pr.PullRequest{
+			Number: pullRequest.Number,
+			URL:    pullRequest.HTMLURL,
+			Title:  pullRequest.Title,
+			Branch: pullRequest.Head.Ref,
+			Labels: pullRequest.labelNames(),
+		},
+		State:     pr.StateOpen,
+		Checks:    pr.ChecksNone,
+		Review:    pr.ReviewRequired,
+		AutoMerge: slices.Contains(pullRequest.labelNames(), pr.AutoMergeLabel),
+	}
+
+	switch {
+	case pullRequest.Merged:
+		status.State = pr.StateMerged
+	case pullRequest.State == "closed":
+		status.State = pr.StateClosed
+	}
+
+	if combined.TotalCount > 0 {
+		switch combined.State {
+		case "success", "warning":
+			status.Checks = pr.ChecksSuccess
+		case "failure", "error":
+			status.Checks = pr.ChecksFailure
+		default:
+			status.Checks = pr.ChecksPending
+		}
+	}
+
+	// Only the latest review of each reviewer counts
+	latest := make(map[string]string)
+	for _, review := range reviews {
+		if !review.Dismissed && (review.State == "APPROVED" || review.State == "REQUEST_CHANGES") {
+			latest[review.User.Login] = review.State
+		}
+	}
+	for _, reviewState := range latest {
+		if reviewState == "REQUEST_CHANGES" {
+			status.Review = pr.ReviewChangesRequested
+			break
+		}
+		status.Review = pr.ReviewApproved
+	}
+
+	return status, nil
+}
+
+func (p *PullRequestProvider) Close(number int, comment string) error {
+	commentPath := p.repoPath(fmt.Sprintf("issues/%d/comments", number))
+	if err := p.client.Do(http.MethodPost, commentPath, map[string]string{"body": comment}, nil); err != nil {
+		return fmt.Errorf("commenting on pull request #%d: %w", number, err)
+	}
+
+	var pullRequest pullRequest
+	if err := p.client.Do(http.MethodPatch, p.repoPath(fmt.Sprintf("pulls/%d", number)), map[string]string{"state": "closed"}, &pullRequest); err != nil {
+		return fmt.Errorf("closing pull request #%d: %w", number, err)
+	}
+
+	branchPath := p.repoPath("branches/" + url.PathEscape(pullRequest.Head.Ref))
+	if err := p.client.Do(http.MethodDelete, branchPath, nil, nil); err != nil && !rest.IsNotFound(err) {
+		return fmt.Errorf("deleting branch %s of pull request #%d: %w", pullRequest.Head.Ref, number, err)
+	}
+	return nil
+}
+
+func (p *PullRequestProvider) GetPromotionEnvironment(branch string) (string, error) {
+	pullRequest, err := p.get(branch)
+	if err != nil {
+		return "", fmt.Errorf("getting pull request for branch %s: %w", branch, err)
+	}
+	if pullRequest == nil {
+		return "", nil
+	}
+	for _, label := range pullRequest.Labels {
+		if env, ok := pr.ParsePromotionLabel(label.Name); ok {
+			return env, nil
+		}
+	}
+	return "", nil
+}
+
+func (p *PullRequestProvider) SetPromotionEnvironment(branch, env string) error {
+	pullRequest, err := p.get(branch)
+	if err != nil {
+		return fmt.Errorf("getting pull request for branch %s: %w", branch, err)
+	}
+	if pullRequest == nil {
+		return fmt.Errorf("no pull request found for branch %s", branch)
+	}
+
+	// Remove existing labels, if any
+	// Typically, there is only one or none, but we cannot guarantee there are not many
+	labelsPath := p.repoPath(fmt.Sprintf("issues/%d/labels", pullRequest.Number))
+	for _, label := range pullRequest.Labels {
+		if _, ok := pr.ParsePromotionLabel(label.Name); !ok {
+			continue
+		}
+		if err := p.client.Do(http.MethodDelete, fmt.Sprintf("%s/%d", labelsPath, label.ID), nil, nil); err != nil {
+			return fmt.Errorf("removing label %s from branch %s: %w", label.Name, branch, err)
+		}
+	}
+
+	// Add new label
+	if env != "" {
+		name := pr.PromotionLabel(env)
+		ids, err := p.ensureLabels(name)
+		if err != nil {
+			return err
+		}
+		if err := p.client.Do(http.MethodPost, labelsPath, map[string]any{"labels": ids}, nil); err != nil {
+			return fmt.Errorf("adding label %s to branch %s: %w", name, branch, err)
+		}
+	}
+	return nil
+}
+
+func (p *PullRequestProvider) get(branch string) (*pullRequest, error) {
+	prs, err := p.listOpen()
+	if err != nil {
+		return nil, err
+	}
+
+	// We can safely assume that there is either none or only one PR for a given branch
+	for _, pullRequest := range prs {
+		if pullRequest.Head.Ref == branch {
+			return &pullRequest, nil
+		}
+	}
+	return nil, nil
+}
+
+// listOpen returns all open pull requests having all given labels, across all pages.
+func (p *PullRequestProvider) listOpen(labels ...string) ([]pullRequest, error) {
+	var result []pullRequest
+	for page := 1; ; page++ {
+		query := url.Values{
+			"state": {"open"},
+			"limit": {strconv.Itoa(pageSize)},
+			"page":  {strconv.Itoa(page)},
+		}
+
+		var prs []pullRequest
+		if err := p.client.Do(http.MethodGet, p.repoPath("pulls?"+query.Encode()), nil, &prs); err != nil {
+			return nil, err
+		}
+
+		for _, pullRequest := range prs {
+			if pullRequest.hasLabels(labels) {
+				result = append(result, pullRequest)
+			}
+		}
+
+		if len(prs) < pageSize {
+			return result, nil
+		}
+	}
+}
+
+// ensureLabels creates given labels in the repository if they do not exist yet and returns their IDs.
+func (p *PullRequestProvider) ensureLabels(names ...string) ([]int64, error) {
+	ids := []int64{}
+	if len(names) == 0 {
+		return ids, nil
+	}
+
+	existingLabels := make(map[string]int64)
+	for page := 1; ; page++ {
+		query := url.Values{"limit": {strconv.Itoa(pageSize)}, "page": {strconv.Itoa(page)}}
+
+		var labels []label
+		if err := p.client.Do(http.MethodGet, p.repoPath("labels?"+query.Encode()), nil, &labels); err != nil {
+			return nil, fmt.Errorf("listing labels: %w", err)
+		}
+		for _, label := range labels {
+			existingLabels[label.Name] = label.ID
+		}
+
+		if len(labels) < pageSize {
+			break
+		}
+	}
+
+	for _, name := range names {
+		id, ok := existingLabels[name]
+		if !ok {
+			var created label
+			if err := p.client.Do(http.MethodPost, p.repoPath("labels"), map[string]string{"name": name, "color": defaultColor}, &created); err != nil {
+				return nil, fmt.Errorf("creating label %s: %w", name, err)
+			}
+			id = created.ID
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// getKnownUsers returns given usernames, skipping those unknown to Gitea.
+func (p *PullRequestProvider) getKnownUsers(usernames []string) ([]string, error) {
+	var known []string
+	for _, username := range usernames {
+		err := p.client.Do(http.MethodGet, "users/"+url.PathEscape(username), nil, nil)
+		if rest.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("getting user %s: %w", username, err)
+		}
+		known = append(known, username)
+	}
+	return known, nil
+}
+
+func (p *PullRequestProvider) getRepository() (*repository, error) {
+	var repo repository
+	if err := p.client.Do(http.MethodGet, p.repoPath(""), nil, &repo); err != nil {
+		return nil, fmt.Errorf("getting repository %s: %w", p.repository, err)
+	}
+	return &repo, nil
+}
+
+func (p *PullRequestProvider) repoPath(subPath string) string {
+	path := "repos/" + p.repository
+	if subPath != "" {
+		path += "/" + subPath
+	}
+	return path
+}
+
+func (pullRequest pullRequest) labelNames() []string {
+	var names []string
+	for _, label := range pullRequest.Labels {
+		names = append(names, label.Name)
+	}
+	return names
+}
+
+func (pullRequest pullRequest) hasLabels(labels []string) bool {
+	names := pullRequest.labelNames()
+	for _, label := range labels {
+		if !slices.Contains(names, label) {
+			return false
+		}
+	}
+	return true
+}
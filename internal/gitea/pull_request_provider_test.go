@@ -0,0 +1,368 @@
+package gitea
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/nestoca/joy/internal/git/pr"
+)
+
+const testRepository = "owner/catalog"
+
+// fakeGitea is a minimal in-memory stand-in for the Gitea pull requests API.
+type fakeGitea struct {
+	pullRequests       []*pullRequest
+	labels             []label
+	comments           map[int][]string
+	requestedReviewers map[int][]string
+	deletedBranches    []string
+	created            map[string]any
+	statuses           map[string]combinedStatus
+	reviews            map[int][]review
+}
+
+func newFakeGitea(t *testing.T) (*fakeGitea, *PullRequestProvider) {
+	fake := &fakeGitea{
+		labels:             []label{{ID: 1, Name: "environment:prod"}},
+		comments:           make(map[int][]string),
+		requestedReviewers: make(map[int][]string),
+		statuses:           make(map[string]combinedStatus),
+		reviews:            make(map[int][]review),
+	}
+
+	mux := http.NewServeMux()
+	repoPath := "/api/v1/repos/owner/catalog"
+
+	mux.HandleFunc("GET /api/v1/user", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "token secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		writeJSON(w, map[string]string{"login": "joy"})
+	})
+
+	mux.HandleFunc("GET /api/v1/users/{username}", func(w http.ResponseWriter, r *http.Request) {
+		if !slices.Contains([]string{"alice", "bob"}, r.PathValue("username")) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		writeJSON(w, map[string]string{"login": r.PathValue("username")})
+	})
+
+	mux.HandleFunc("GET "+repoPath, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, repository{DefaultBranch: "main"})
+	})
+
+	mux.HandleFunc("GET "+repoPath+"/labels", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, paginate(r, fake.labels))
+	})
+
+	mux.HandleFunc("POST "+repoPath+"/labels", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		require.Equal(t, defaultColor, body["color"])
+		created := label{ID: int64(len(fake.labels) + 1), Name: body["name"]}
+		fake.labels = append(fake.labels, created)
+		writeJSON(w, created)
+	})
+
+	mux.HandleFunc("GET "+repoPath+"/pulls", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "open", r.URL.Query().Get("state"))
+		writeJSON(w, paginate(r, fake.pullRequests))
+	})
+
+	mux.HandleFunc("POST "+repoPath+"/pulls", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		fake.created = body
+
+		created := &pullRequest{
+			Number: len(fake.pullRequests) + 1,
+			Title:  body["title"].(string),
+		}
+		created.Head.Ref = body["head"].(string)
+		created.HTMLURL = fmt.Sprintf("https://gitea.example.com/%s/pulls/%d", testRepository, created.Number)
+		for _, id := range body["labels"].([]any) {
+			created.Labels = append(created.Labels, fake.label(int64(id.(float64))))
+		}
+		fake.pullRequests = append(fake.pullRequests, created)
+		writeJSON(w, created)
+	})
+
+	mux.HandleFunc("GET "+repoPath+"/pulls/{number}", func(w http.ResponseWriter, r *http.Request) {
+		pullRequest := fake.find(r.PathValue("number"))
+		if pullRequest == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		writeJSON(w, pullRequest)
+	})
+
+	mux.HandleFunc("GET "+repoPath+"/pulls/{number}/reviews", func(w http.ResponseWriter, r *http.Request) {
+		number, _ := strconv.Atoi(r.PathValue("number"))
+		writeJSON(w, fake.reviews[number])
+	})
+
+	mux.HandleFunc("GET "+repoPath+"/commits/{sha}/status", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, fake.statuses[r.PathValue("sha")])
+	})
+
+	mux.HandleFunc("PATCH "+repoPath+"/pulls/{number}", func(w http.ResponseWriter, r *http.Request) {
+		closed := fake.find(r.PathValue("number"))
+		if closed == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		if body["state"] == "closed" {
+			fake.pullRequests = slices.DeleteFunc(fake.pullRequests, func(candidate *pullRequest) bool {
+				return candidate == closed
+			})
+		}
+		writeJSON(w, closed)
+	})
+
+	mux.HandleFunc("POST "+repoPath+"/pulls/{number}/requested_reviewers", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string][]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		number, _ := strconv.Atoi(r.PathValue("number"))
+		fake.requestedReviewers[number] = body["reviewers"]
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	mux.HandleFunc("POST "+repoPath+"/issues/{number}/comments", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		number, _ := strconv.Atoi(r.PathValue("number"))
+		fake.comments[number] = append(fake.comments[number], body["body"])
+		writeJSON(w, body)
+	})
+
+	mux.HandleFunc("POST "+repoPath+"/issues/{number}/labels", func(w http.ResponseWriter, r *http.Request) {
+		pullRequest := fake.find(r.PathValue("number"))
+		var body map[string][]int64
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		for _, id := range body["labels"] {
+			pullRequest.Labels = append(pullRequest.Labels, fake.label(id))
+		}
+		writeJSON(w, pullRequest.Labels)
+	})
+
+	mux.HandleFunc("DELETE "+repoPath+"/issues/{number}/labels/{id}", func(w http.ResponseWriter, r *http.Request) {
+		pullRequest := fake.find(r.PathValue("number"))
+		pullRequest.Labels = slices.DeleteFunc(pullRequest.Labels, func(label label) bool {
+			return strconv.FormatInt(label.ID, 10) == r.PathValue("id")
+		})
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	mux.HandleFunc("DELETE "+repoPath+"/branches/{branch}", func(w http.ResponseWriter, r *http.Request) {
+		fake.deletedBranches = append(fake.deletedBranches, r.PathValue("branch"))
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return fake, NewPullRequestProvider(server.URL, testRepository, "secret")
+}
+
+func (fake *fakeGitea) find(number string) *pullRequest {
+	for _, pullRequest := range fake.pullRequests {
+		if strconv.Itoa(pullRequest.Number) == number {
+			return pullRequest
+		}
+	}
+	return nil
+}
+
+func (fake *fakeGitea) label(id int64) label {
+	for _, label := range fake.labels {
+		if label.ID == id {
+			return label
+		}
+	}
+	return label{}
+}
+
+func paginate[T any](r *http.Request, items []T) []T {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	start := min((page-1)*limit, len(items))
+	end := min(start+limit, len(items))
+	return items[start:end]
+}
+
+func writeJSON(w http.ResponseWriter, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+func newPullRequest(number int, branch string, labels ...label) *pullRequest {
+	pullRequest := &pullRequest{Number: number, Labels: labels}
+	pullRequest.Head.Ref = branch
+	return pullRequest
+}
+
+func TestEnsureInstalledAndAuthenticated(t *testing.T) {
+	_, provider := newFakeGitea(t)
+	require.NoError(t, provider.EnsureInstalledAndAuthenticated())
+
+	provider.client.Header.Set("Authorization", "token wrong")
+	require.ErrorContains(t, provider.EnsureInstalledAndAuthenticated(), "401 Unauthorized")
+
+	provider.token = ""
+	require.EqualError(t, provider.EnsureInstalledAndAuthenticated(), "gitea token not set")
+}
+
+func TestCreate(t *testing.T) {
+	fake, provider := newFakeGitea(t)
+
+	url, err := provider.Create(pr.CreateParams{
+		Branch:    "promote-release1",
+		Title:     "Promote release1",
+		Body:      "Some description",
+		Labels:    []string{"environment:prod", "release:release1"},
+		Reviewers: []string{"alice", "unknown", "bob"},
+		Draft:     true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "https://gitea.example.com/owner/catalog/pulls/1", url)
+
+	require.Equal(t, map[string]any{
+		"head":   "promote-release1",
+		"base":   "main",
+		"title":  "WIP: Promote release1",
+		"body":   "Some description",
+		"labels": []any{1.0, 2.0},
+	}, fake.created)
+	require.Equal(t, []label{{ID: 1, Name: "environment:prod"}, {ID: 2, Name: "release:release1"}}, fake.labels)
+	require.Equal(t, []string{"alice", "bob"}, fake.requestedReviewers[1])
+
+	exists, err := provider.Exists("promote-release1")
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	exists, err = provider.Exists("other")
+	require.NoError(t, err)
+	require.False(t, exists)
+}
+
+func TestListOpen(t *testing.T) {
+	fake, provider := newFakeGitea(t)
+	for i := 1; i <= pageSize+5; i++ {
+		labels := []label{{ID: 3, Name: "environment:staging"}}
+		if i%2 == 0 {
+			labels = []label{{ID: 1, Name: "environment:prod"}, {ID: 2, Name: "release:release1"}}
+		}
+		fake.pullRequests = append(fake.pullRequests, newPullRequest(i, fmt.Sprintf("branch%d", i), labels...))
+	}
+
+	all, err := provider.ListOpen()
+	require.NoError(t, err)
+	require.Len(t, all, pageSize+5)
+
+	prod, err := provider.ListOpen("environment:prod", "release:release1")
+	require.NoError(t, err)
+	require.Len(t, prod, (pageSize+5)/2)
+	require.Equal(t, pr.PullRequest{Number: 2, Branch: "branch2", Labels: []string{"environment:prod", "release:release1"}}, prod[0])
+}
+
+func TestClose(t *testing.T) {
+	fake, provider := newFakeGitea(t)
+	fake.pullRequests = []*pullRequest{newPullRequest(7, "promote-release1")}
+
+	require.NoError(t, provider.Close(7, "Superseded by #8"))
+	require.Empty(t, fake.pullRequests)
+	require.Equal(t, []string{"Superseded by #8"}, fake.comments[7])
+	require.Equal(t, []string{"promote-release1"}, fake.deletedBranches)
+
+	require.ErrorContains(t, provider.Close(9, "Superseded"), "404 Not Found")
+}
+
+func TestPromotionEnvironment(t *testing.T) {
+	fake, provider := newFakeGitea(t)
+	fake.labels = append(fake.labels, label{ID: 2, Name: "bug"}, label{ID: 3, Name: "promote:staging"})
+	fake.pullRequests = []*pullRequest{newPullRequest(1, "feature", fake.labels[1], fake.labels[2])}
+
+	env, err := provider.GetPromotionEnvironment("feature")
+	require.NoError(t, err)
+	require.Equal(t, "staging", env)
+
+	require.NoError(t, provider.SetPromotionEnvironment("feature", "prod"))
+	require.Equal(t, []string{"bug", "promote:prod"}, fake.pullRequests[0].labelNames())
+
+	branches, err := provider.GetBranchesPromotingToEnvironment("prod")
+	require.NoError(t, err)
+	require.Equal(t, []string{"feature"}, branches)
+
+	require.NoError(t, provider.SetPromotionEnvironment("feature", ""))
+	require.Equal(t, []string{"bug"}, fake.pullRequests[0].labelNames())
+
+	env, err = provider.GetPromotionEnvironment("feature")
+	require.NoError(t, err)
+	require.Empty(t, env)
+
+	require.EqualError(t, provider.SetPromotionEnvironment("missing", "prod"), "no pull request found for branch missing")
+}
+
+func TestGetStatus(t *testing.T) {
+	fake, provider := newFakeGitea(t)
+	promotion := newPullRequest(3, "promote-release1", label{ID: 1, Name: "environment:prod"}, label{ID: 2, Name: "auto-merge"})
+	promotion.State = "open"
+	promotion.Head.SHA = "abc123"
+	fake.pullRequests = []*pullRequest{promotion}
+
+	status, err := provider.GetStatus(3)
+	require.NoError(t, err)
+	require.Equal(t, &pr.Status{
+		PullRequest: pr.PullRequest{
+			Number: 3,
+			Branch: "promote-release1",
+			Labels: []string{"environment:prod", "auto-merge"},
+		},
+		State:     pr.StateOpen,
+		Checks:    pr.ChecksNone,
+		Review:    pr.ReviewRequired,
+		AutoMerge: true,
+	}, status)
+
+	newReview := func(login, state string) review {
+		var review review
+		review.User.Login = login
+		review.State = state
+		return review
+	}
+
+	fake.statuses["abc123"] = combinedStatus{State: "pending", TotalCount: 2}
+	fake.reviews[3] = []review{newReview("alice", "REQUEST_CHANGES"), newReview("bob", "APPROVED")}
+	status, err = provider.GetStatus(3)
+	require.NoError(t, err)
+	require.Equal(t, pr.ChecksPending, status.Checks)
+	require.Equal(t, pr.ReviewChangesRequested, status.Review)
+
+	fake.statuses["abc123"] = combinedStatus{State: "success", TotalCount: 2}
+	fake.reviews[3] = append(fake.reviews[3], newReview("alice", "APPROVED"))
+	promotion.State = "closed"
+	promotion.Merged = true
+	status, err = provider.GetStatus(3)
+	require.NoError(t, err)
+	require.Equal(t, pr.StateMerged, status.State)
+	require.Equal(t, pr.ChecksSuccess, status.Checks)
+	require.Equal(t, pr.ReviewApproved, status.Review)
+
+	fake.statuses["abc123"] = combinedStatus{State: "failure", TotalCount: 2}
+	promotion.Merged = false
+	status, err = provider.GetStatus(3)
+	require.NoError(t, err)
+	require.Equal(t, pr.StateClosed, status.State)
+	require.Equal(t, pr.ChecksFailure, status.Checks)
+}
@@ -0,0 +1,83 @@
+package github
+
+import (
+	"net/http"
+	"os"
+	"sync"
+
+	"github.com/nestoca/joy/internal/rest"
+)
+
+// DefaultAPIURL is the base URL of the GitHub REST API, overridable with the GITHUB_API_URL environment variable.
+const DefaultAPIURL = "https://api.github.com"
+
+// APIURL returns the base URL of the REST API of given GitHub host, which is /api/v3 on GitHub Enterprise hosts.
+// The GITHUB_API_URL environment variable, set in GitHub Actions, takes precedence.
+func APIURL(host string) string {
+	if apiURL := os.Getenv("GITHUB_API_URL"); apiURL != "" {
+		return apiURL
+	}
+	if host == defaultHost {
+		return DefaultAPIURL
+	}
+	return "https://" + host + "/api/v3"
+}
+
+// Client calls the GitHub REST API, resolving its token lazily upon first request.
+type Client struct {
+	rest func() (*rest.Client, error)
+}
+
+// NewClient returns a client for the GitHub REST API at given base URL, authenticating with the token returned by getToken.
+func NewClient(baseURL string, getToken func() (string, error)) *Client {
+	return &Client{
+		rest: sync.OnceValues(func() (*rest.Client, error) {
+			token, err := getToken()
+			if err != nil {
+				return nil, err
+			}
+			return newRESTClient(baseURL, token), nil
+		}),
+	}
+}
+
+// NewHostClient returns a client for the REST API of the GitHub host returned by getHost, resolving the host and its
+// token lazily upon first request.
+func NewHostClient(getHost func() (string, error)) *Client {
+	return &Client{
+		rest: sync.OnceValues(func() (*rest.Client, error) {
+			host, err := getHost()
+			if err != nil {
+				return nil, err
+			}
+			token, err := GetToken(host)
+			if err != nil {
+				return nil, err
+			}
+			return newRESTClient(APIURL(host), token), nil
+		}),
+	}
+}
+
+// NewDefaultClient returns a client for the REST API of the default GitHub host.
+func NewDefaultClient() *Client {
+	return NewHostClient(func() (string, error) {
+		return DefaultHost(), nil
+	})
+}
+
+func newRESTClient(baseURL, token string) *rest.Client {
+	return rest.NewClient(baseURL, http.Header{
+		"Authorization":        {"Bearer " + token},
+		"X-GitHub-Api-Version": {"2022-11-28"},
+	})
+}
+
+// Do sends a request to the GitHub REST API, as described by rest.Client.Do.
+func (c *Client) Do(method, path string, body, out any) error {
+	client, err := c.rest()
+	if err != nil {
+		return err
+	}
+	return client.Do(method, path, body, out)
+}
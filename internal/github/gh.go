@@ -1,6 +1,7 @@
 package github
 
 import (
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"os"
@@ -14,7 +15,8 @@ import (
 var dependency = &dependencies.Dependency{
 	Command:    "gh",
 	Url:        "https://github.com/cli/cli",
-	IsRequired: true,
+	IsRequired: false,
+	RequiredBy: []string{"setup"},
 }
 
 func init() {
@@ -76,7 +78,13 @@ type CloneOptions struct {
 	OutDir string
 }
 
+// Clone clones given repository using gh if installed, respecting its configured git protocol,
+// or otherwise using git over https with the resolved GitHub token.
 func Clone(dir string, opts CloneOptions) error {
+	if !dependency.IsInstalled() {
+		return cloneWithGit(dir, opts)
+	}
+
 	args := []string{"repo", "clone", opts.Repo}
 	if opts.OutDir != "" {
 		args = append(args, opts.OutDir)
@@ -85,6 +93,35 @@ func Clone(dir string, opts CloneOptions) error {
 	return err
 }
 
+// cloneWithGit clones given repository from the default GitHub host over https, authenticating with its token.
+// The token is passed as an extra http header through the environment, so that it neither shows up in the process
+// arguments nor gets persisted in the git config of the clone.
+func cloneWithGit(dir string, opts CloneOptions) error {
+	host := DefaultHost()
+	token, err := GetToken(host)
+	if err != nil {
+		return fmt.Errorf("cloning %s from %s without gh cli: %w", opts.Repo, host, err)
+	}
+
+	args := []string{"clone", fmt.Sprintf("https://%s/%s.git", host, opts.Repo)}
+	if opts.OutDir != "" {
+		args = append(args, opts.OutDir)
+	}
+
+	credentials := base64.StdEncoding.EncodeToString([]byte("x-access-token:" + token))
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GIT_CONFIG_COUNT=1",
+		fmt.Sprintf("GIT_CONFIG_KEY_0=http.https://%s/.extraheader", host),
+		"GIT_CONFIG_VALUE_0=Authorization: Basic "+credentials,
+	)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("running git command with args %q: %w: %q", strings.Join(args, " "), err, output)
+	}
+	return nil
+}
+
 func CloneInteractive(dir string, opts CloneOptions) error {
 	args := []string{"repo", "clone", opts.Repo}
 	if opts.OutDir != "" {
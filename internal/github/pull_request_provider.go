@@ -1,170 +1,418 @@
 package github
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/url"
 	"os/exec"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
+
+	"github.com/pkg/browser"
 
 	"github.com/nestoca/joy/internal/git/pr"
+	"github.com/nestoca/joy/internal/rest"
+	"github.com/nestoca/joy/internal/style"
 )
 
+const pageSize = 100
+
+// PullRequestProvider manages the GitHub pull requests of the repository that a local git directory was cloned from.
 type PullRequestProvider struct {
-	dir string
+	dir        string
+	client     *Client
+	repository func() (string, error)
 }
 
+// NewPullRequestProvider returns a provider for the pull requests of the GitHub repository that given directory's
+// origin remote points to, on github.com or a GitHub Enterprise host.
 func NewPullRequestProvider(dir string) *PullRequestProvider {
+	loadRemote := sync.OnceValues(func() (remote, error) {
+		return getRemote(dir)
+	})
 	return &PullRequestProvider{
 		dir: dir,
+		client: NewHostClient(func() (string, error) {
+			remote, err := loadRemote()
+			return remote.host, err
+		}),
+		repository: func() (string, error) {
+			remote, err := loadRemote()
+			return remote.repository, err
+		},
 	}
 }
 
-var labelRegex = regexp.MustCompile(`^promote:(.+)$`)
-
 type label struct {
 	Name string `json:"name"`
 }
 
+// issue is the subset of an issue returned by the issues API that is needed to find pull requests, which the
+// issues API also lists, as opposed to the pulls API which cannot filter by labels.
+type issue struct {
+	Number      int       `json:"number"`
+	PullRequest *struct{} `json:"pull_request"`
+}
+
 type pullRequest struct {
-	HeadRefName string  `json:"headRefName"`
-	Labels      []label `json:"labels"`
+	Number  int    `json:"number"`
+	HTMLURL string `json:"html_url"`
+	Title   string `json:"title"`
+	State   string `json:"state"`
+	Merged  bool   `json:"merged"`
+	Head    struct {
+		Ref string `json:"ref"`
+		SHA string `json:"sha"`
+	} `json:"head"`
+	Labels    []label `json:"labels"`
+	AutoMerge *struct {
+		MergeMethod string `json:"merge_method"`
+	} `json:"auto_merge"`
+}
+
+type checkRuns struct {
+	CheckRuns []struct {
+		Status     string `json:"status"`
+		Conclusion string `json:"conclusion"`
+	} `json:"check_runs"`
+}
+
+type combinedStatus struct {
+	State      string `json:"state"`
+	TotalCount int    `json:"total_count"`
 }
 
+type review struct {
+	User struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	State string `json:"state"`
+}
+
+type repository struct {
+	DefaultBranch string `json:"default_branch"`
+	HTMLURL       string `json:"html_url"`
+}
+
+// EnsureInstalledAndAuthenticated checks that the token grants access to the repository. The repository itself is
+// queried, rather than the authenticated user, as GitHub Actions and GitHub App installation tokens are scoped to
+// repositories and cannot access the user endpoint.
 func (p *PullRequestProvider) EnsureInstalledAndAuthenticated() error {
-	return EnsureInstalledAndAuthenticated()
+	err := p.do(http.MethodGet, "", nil, nil)
+	if errors.Is(err, ErrNoToken) {
+		fmt.Printf("🔐 Please set the %s environment variable or run %s to authenticate with GitHub.\n", style.Code("GITHUB_TOKEN"), style.Code("gh auth login"))
+	}
+	if err != nil {
+		return fmt.Errorf("authenticating with github: %w", err)
+	}
+	return nil
 }
 
 func (p *PullRequestProvider) Exists(branch string) (bool, error) {
-	pr, err := p.get(branch)
+	pullRequest, err := p.get(branch)
 	if err != nil {
 		return false, fmt.Errorf("getting pull request for branch %s: %w", branch, err)
 	}
-	return pr != nil, nil
+	return pullRequest != nil, nil
 }
 
 func (p *PullRequestProvider) GetBranchesPromotingToEnvironment(env string) ([]string, error) {
-	prs, err := p.getAllWithLabel(fmt.Sprintf("promote:%s", env))
+	prs, err := p.listOpen(pr.PromotionLabel(env))
 	if err != nil {
 		return nil, fmt.Errorf("getting pull requests: %w", err)
 	}
 
 	var branches []string
-	for _, pr := range prs {
-		branches = append(branches, pr.HeadRefName)
+	for _, pullRequest := range prs {
+		branches = append(branches, pullRequest.Head.Ref)
 	}
 	return branches, nil
 }
 
+// CreateInteractively lets user create the pull request of given branch with the interactive flow of the gh cli,
+// when installed, or otherwise by opening the pull request creation page in the browser.
 func (p *PullRequestProvider) CreateInteractively(branch string) error {
-	err := executeInteractively(p.dir, "pr", "create", "--head", branch)
+	if dependency.IsInstalled() {
+		if err := executeInteractively(p.dir, "pr", "create", "--head", branch); err != nil {
+			return fmt.Errorf("creating pull request for branch %s: %w", branch, err)
+		}
+		return nil
+	}
+
+	repo, err := p.getRepository()
 	if err != nil {
-		return fmt.Errorf("creating pull request for branch %s: %w", branch, err)
+		return err
+	}
+
+	compareURL := fmt.Sprintf("%s/compare/%s?expand=1", repo.HTMLURL, url.PathEscape(branch))
+	fmt.Printf("🌐 Opening %s in browser to create pull request.\n", style.Link(compareURL))
+	if err := browser.OpenURL(compareURL); err != nil {
+		return fmt.Errorf("opening pull request creation page for branch %s: %w", branch, err)
 	}
 	return nil
 }
 
 func (p *PullRequestProvider) Create(params pr.CreateParams) (string, error) {
-	args := []string{
-		"pr", "create",
-		"--head", params.Branch,
-		"--title", params.Title,
-		"--body", params.Body,
+	repo, err := p.getRepository()
+	if err != nil {
+		return "", err
 	}
 
-	if params.Draft {
-		args = append(args, "--draft")
+	if err := p.createLabels(params.Labels...); err != nil {
+		return "", err
 	}
 
-	for _, label := range params.Labels {
-		args = append(args, "--label", label)
+	body := map[string]any{
+		"head":  params.Branch,
+		"base":  repo.DefaultBranch,
+		"title": params.Title,
+		"body":  params.Body,
+		"draft": params.Draft,
 	}
 
+	var pullRequest pullRequest
+	if err := p.do(http.MethodPost, "pulls", body, &pullRequest); err != nil {
+		return "", fmt.Errorf("creating pull request for branch %s: %w", params.Branch, err)
+	}
+
+	if len(params.Labels) > 0 {
+		labelsPath := fmt.Sprintf("issues/%d/labels", pullRequest.Number)
+		if err := p.do(http.MethodPost, labelsPath, map[string]any{"labels": params.Labels}, nil); err != nil {
+			return "", fmt.Errorf("labelling pull request #%d: %w", pullRequest.Number, err)
+		}
+	}
+
+	var reviewers []string
 	for _, reviewer := range params.Reviewers {
 		if reviewer == "github-actions[bot]" {
 			continue
 		}
-		args = append(args, "--reviewer", reviewer)
+		reviewers = append(reviewers, reviewer)
+	}
+	if len(reviewers) > 0 {
+		reviewersPath := fmt.Sprintf("pulls/%d/requested_reviewers", pullRequest.Number)
+		if err := p.do(http.MethodPost, reviewersPath, map[string]any{"reviewers": reviewers}, nil); err != nil {
+			return "", fmt.Errorf("requesting reviewers for pull request #%d: %w", pullRequest.Number, err)
+		}
 	}
 
-	err := p.createLabels(params.Labels...)
+	return pullRequest.HTMLURL, nil
+}
+
+func (p *PullRequestProvider) ListOpen(labels ...string) ([]pr.PullRequest, error) {
+	prs, err := p.listOpen(labels...)
 	if err != nil {
+		return nil, fmt.Errorf("listing pull requests with labels %s: %w", strings.Join(labels, ", "), err)
+	}
+
+	result := make([]pr.PullRequest, len(prs))
+	for i, pullRequest := range prs {
+		result[i] = pr.PullRequest{
+			Number: pullRequest.Number,
+			URL:    pullRequest.HTMLURL,
+			Title:  pullRequest.Title,
+			Branch: pullRequest.Head.Ref,
+			Labels: pullRequest.labelNames(),
+		}
+	}
+	return result, nil
+}
+
+func (p *PullRequestProvider) GetStatus(number int) (*pr.Status, error) {
+	var pullRequest pullRequest
+	if err := p.do(http.MethodGet, "pulls/"+strconv.Itoa(number), nil, &pullRequest); err != nil {
+		return nil, fmt.Errorf("getting pull request #%d: %w", number, err)
+	}
+
+	status := &pr.Status{
+		PullRequest: pr.PullRequest{
+			Number: pullRequest.Number,
+			URL:    pullRequest.HTMLURL,
+			Title:  pullRequest.Title,
+			Branch: pullRequest.Head.Ref,
+			Labels: pullRequest.labelNames(),
+		},
+		State:     pr.StateOpen,
+		AutoMerge: pullRequest.AutoMerge != nil || slices.Contains(pullRequest.labelNames(), pr.AutoMergeLabel),
+	}
+	switch {
+	case pullRequest.Merged:
+		status.State = pr.StateMerged
+	case pullRequest.State == "closed":
+		status.State = pr.StateClosed
+	}
+
+	var err error
+	if status.Checks, err = p.getChecksState(pullRequest.Head.SHA); err != nil {
+		return nil, fmt.Errorf("getting checks of pull request #%d: %w", number, err)
+	}
+	if status.Review, err = p.getReviewState(number); err != nil {
+		return nil, fmt.Errorf("getting reviews of pull request #%d: %w", number, err)
+	}
+	return status, nil
+}
+
+// getChecksState combines the check runs and commit statuses of given commit into a single state.
+func (p *PullRequestProvider) getChecksState(sha string) (pr.ChecksState, error) {
+	var runs checkRuns
+	if err := p.do(http.MethodGet, "commits/"+sha+"/check-runs?per_page="+strconv.Itoa(pageSize), nil, &runs); err != nil {
 		return "", err
 	}
-	prURL, err := ExecuteAndGetOutput(p.dir, args...)
-	if err != nil {
-		return "", fmt.Errorf("creating pull request for branch %s: %w", params.Branch, err)
+
+	var combined combinedStatus
+	if err := p.do(http.MethodGet, "commits/"+sha+"/status", nil, &combined); err != nil {
+		return "", err
+	}
+
+	if len(runs.CheckRuns) == 0 && combined.TotalCount == 0 {
+		return pr.ChecksNone, nil
+	}
+
+	state := pr.ChecksSuccess
+	switch combined.State {
+	case "failure", "error":
+		return pr.ChecksFailure, nil
+	case "pending":
+		if combined.TotalCount > 0 {
+			state = pr.ChecksPending
+		}
 	}
 
-	return strings.TrimSpace(prURL), err
+	for _, run := range runs.CheckRuns {
+		switch {
+		case run.Status != "completed":
+			state = pr.ChecksPending
+		case slices.Contains([]string{"failure", "cancelled", "timed_out", "action_required"}, run.Conclusion):
+			return pr.ChecksFailure, nil
+		}
+	}
+	return state, nil
 }
 
-func (p *PullRequestProvider) getPromotionLabels(branch string) ([]string, error) {
-	pr, err := p.get(branch)
+// getReviewState returns the review state of given pull request, based on the latest review of each reviewer.
+func (p *PullRequestProvider) getReviewState(number int) (pr.ReviewState, error) {
+	var reviews []review
+	if err := p.do(http.MethodGet, fmt.Sprintf("pulls/%d/reviews?per_page=%d", number, pageSize), nil, &reviews); err != nil {
+		return "", err
+	}
+
+	latest := make(map[string]string)
+	for _, review := range reviews {
+		if review.State == "APPROVED" || review.State == "CHANGES_REQUESTED" || review.State == "DISMISSED" {
+			latest[review.User.Login] = review.State
+		}
+	}
+
+	state := pr.ReviewRequired
+	for _, reviewState := range latest {
+		switch reviewState {
+		case "CHANGES_REQUESTED":
+			return pr.ReviewChangesRequested, nil
+		case "APPROVED":
+			state = pr.ReviewApproved
+		}
+	}
+	return state, nil
+}
+
+func (p *PullRequestProvider) Close(number int, comment string) error {
+	commentPath := fmt.Sprintf("issues/%d/comments", number)
+	if err := p.do(http.MethodPost, commentPath, map[string]string{"body": comment}, nil); err != nil {
+		return fmt.Errorf("commenting on pull request #%d: %w", number, err)
+	}
+
+	var pullRequest pullRequest
+	if err := p.do(http.MethodPatch, "pulls/"+strconv.Itoa(number), map[string]string{"state": "closed"}, &pullRequest); err != nil {
+		return fmt.Errorf("closing pull request #%d: %w", number, err)
+	}
+
+	if err := p.do(http.MethodDelete, "git/refs/heads/"+pullRequest.Head.Ref, nil, nil); err != nil && !rest.IsNotFound(err) {
+		return fmt.Errorf("deleting branch %s of pull request #%d: %w", pullRequest.Head.Ref, number, err)
+	}
+	return nil
+}
+
+func (p *PullRequestProvider) getPromotionLabels(branch string) (*pullRequest, []string, error) {
+	pullRequest, err := p.get(branch)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	if pr == nil {
-		return nil, nil
+	if pullRequest == nil {
+		return nil, nil, nil
 	}
 	var labels []string
-	for _, label := range pr.Labels {
-		if labelRegex.MatchString(label.Name) {
-			labels = append(labels, label.Name)
+	for _, label := range pullRequest.labelNames() {
+		if _, ok := pr.ParsePromotionLabel(label); ok {
+			labels = append(labels, label)
 		}
 	}
-	return labels, nil
+	return pullRequest, labels, nil
 }
 
 func (p *PullRequestProvider) GetPromotionEnvironment(branch string) (string, error) {
-	labels, err := p.getPromotionLabels(branch)
+	_, labels, err := p.getPromotionLabels(branch)
 	if err != nil {
 		return "", fmt.Errorf("getting promotion labels for branch %s: %w", branch, err)
 	}
 	if len(labels) == 0 {
 		return "", nil
 	}
-	return labelRegex.FindStringSubmatch(labels[0])[1], nil
+	env, _ := pr.ParsePromotionLabel(labels[0])
+	return env, nil
 }
 
 func (p *PullRequestProvider) SetPromotionEnvironment(branch string, env string) error {
 	// Get current promotion labels
 	// Typically, there is only one or none, but we cannot guarantee there are not many
-	labels, err := p.getPromotionLabels(branch)
+	pullRequest, labels, err := p.getPromotionLabels(branch)
 	if err != nil {
 		return fmt.Errorf("getting promotion labels for branch %s: %w", branch, err)
 	}
+	if pullRequest == nil {
+		return fmt.Errorf("no pull request found for branch %s", branch)
+	}
 
 	// Remove existing labels, if any
+	labelsPath := fmt.Sprintf("issues/%d/labels", pullRequest.Number)
 	for _, label := range labels {
-		if err := p.removeLabel(branch, label); err != nil {
+		if err := p.do(http.MethodDelete, labelsPath+"/"+url.PathEscape(label), nil, nil); err != nil {
 			return fmt.Errorf("removing label %s from branch %s: %w", label, branch, err)
 		}
 	}
 
 	// Add new label
 	if env != "" {
-		label := fmt.Sprintf("promote:%s", env)
-		if err := p.addLabel(branch, label); err != nil {
-			return fmt.Errorf("adding label %s to branch %s: %w", env, branch, err)
+		label := pr.PromotionLabel(env)
+		if err := p.createLabels(label); err != nil {
+			return err
+		}
+		if err := p.do(http.MethodPost, labelsPath, map[string]any{"labels": []string{label}}, nil); err != nil {
+			return fmt.Errorf("adding label %s to branch %s: %w", label, branch, err)
 		}
 	}
 	return nil
 }
 
+// get returns the open pull request of given branch of the repository, if any.
 func (p *PullRequestProvider) get(branch string) (*pullRequest, error) {
-	// List pull requests for branch
-	cmd := exec.Command("gh", "pr", "list", "--head", branch, "--state", "open", "--json", "headRefName,labels")
-	cmd.Dir = p.dir
-	output, err := cmd.CombinedOutput()
+	repository, err := p.repository()
 	if err != nil {
-		return nil, fmt.Errorf("listing pull requests for branch %s: %s", branch, output)
+		return nil, err
+	}
+	owner, _, _ := strings.Cut(repository, "/")
+
+	query := url.Values{
+		"state": {"open"},
+		"head":  {owner + ":" + branch},
 	}
 
-	// Unmarshal JSON
 	var prs []pullRequest
-	if err := json.Unmarshal(output, &prs); err != nil {
-		return nil, fmt.Errorf("unmarshaling pull request list: %w", err)
+	if err := p.do(http.MethodGet, "pulls?"+query.Encode(), nil, &prs); err != nil {
+		return nil, fmt.Errorf("listing pull requests for branch %s: %w", branch, err)
 	}
 
 	// We can safely assume that there is either none or only one PR for a given branch
@@ -174,79 +422,160 @@ func (p *PullRequestProvider) get(branch string) (*pullRequest, error) {
 	return &prs[0], nil
 }
 
-func (p *PullRequestProvider) getAllWithLabel(label string) ([]pullRequest, error) {
-	// List pull requests for branch
-	cmd := exec.Command("gh", "pr", "list", "--label", label, "--state", "open", "--json", "headRefName")
-	cmd.Dir = p.dir
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return nil, fmt.Errorf("listing pull requests: %s", output)
+// listOpen returns all open pull requests having all given labels. As the pulls API cannot filter by labels, labelled
+// pull requests are found with the issues API and then fetched individually.
+func (p *PullRequestProvider) listOpen(labels ...string) ([]pullRequest, error) {
+	if len(labels) == 0 {
+		return getAllPages[pullRequest](p, "pulls", url.Values{"state": {"open"}})
 	}
 
-	// Unmarshal JSON
-	var prs []pullRequest
-	if err := json.Unmarshal(output, &prs); err != nil {
-		return nil, fmt.Errorf("unmarshaling pull request list: %w", err)
+	issues, err := getAllPages[issue](p, "issues", url.Values{
+		"state":  {"open"},
+		"labels": {strings.Join(labels, ",")},
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return prs, nil
+	var result []pullRequest
+	for _, issue := range issues {
+		if issue.PullRequest == nil {
+			continue
+		}
+		var pullRequest pullRequest
+		if err := p.do(http.MethodGet, "pulls/"+strconv.Itoa(issue.Number), nil, &pullRequest); err != nil {
+			return nil, fmt.Errorf("getting pull request #%d: %w", issue.Number, err)
+		}
+		result = append(result, pullRequest)
+	}
+	return result, nil
 }
 
-func (p *PullRequestProvider) removeLabel(branch string, label string) error {
-	cmd := exec.Command("gh", "pr", "edit", branch, "--remove-label", label)
-	cmd.Dir = p.dir
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("removing label %s from branch %s: %s", label, branch, output)
+// getAllPages returns the items listed at given path of the repository with given query, across all pages.
+func getAllPages[T any](p *PullRequestProvider, path string, query url.Values) ([]T, error) {
+	var result []T
+	for page := 1; ; page++ {
+		query.Set("per_page", strconv.Itoa(pageSize))
+		query.Set("page", strconv.Itoa(page))
+
+		var items []T
+		if err := p.do(http.MethodGet, path+"?"+query.Encode(), nil, &items); err != nil {
+			return nil, err
+		}
+		result = append(result, items...)
+
+		if len(items) < pageSize {
+			return result, nil
+		}
 	}
-	return nil
 }
 
+// createLabels creates given labels in the repository if they do not exist yet.
 func (p *PullRequestProvider) createLabels(labels ...string) error {
 	if len(labels) == 0 {
 		return nil
 	}
-	cmd := exec.Command("gh", "label", "list", "--json", "name", "--limit", "1000")
-	cmd.Dir = p.dir
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("listing labels: %s", output)
-	}
 
-	var existingLabels []label
-	if err := json.Unmarshal(output, &existingLabels); err != nil {
-		return fmt.Errorf("unmarshaling label list: %w", err)
+	existingLabels, err := getAllPages[label](p, "labels", url.Values{})
+	if err != nil {
+		return fmt.Errorf("listing labels: %w", err)
 	}
 	existingLabelSet := make(map[string]bool)
 	for _, existingLabel := range existingLabels {
 		existingLabelSet[existingLabel.Name] = true
 	}
+
 	for _, label := range labels {
 		if !existingLabelSet[label] {
-			cmd = exec.Command("gh", "label", "create", label)
-			cmd.Dir = p.dir
-			output, err = cmd.CombinedOutput()
-			if err != nil {
-				return fmt.Errorf("creating label %s: %s", label, output)
+			if err := p.do(http.MethodPost, "labels", map[string]string{"name": label, "color": "ededed"}, nil); err != nil {
+				return fmt.Errorf("creating label %s: %w", label, err)
 			}
 		}
 	}
 	return nil
 }
 
-func (p *PullRequestProvider) addLabel(branch string, label string) error {
-	// Ensure label exists in repo
-	err := p.createLabels(label)
+func (p *PullRequestProvider) getRepository() (*repository, error) {
+	var repo repository
+	if err := p.do(http.MethodGet, "", nil, &repo); err != nil {
+		return nil, fmt.Errorf("getting repository: %w", err)
+	}
+	return &repo, nil
+}
+
+// do sends a request to given path relative to the repository's API URL.
+func (p *PullRequestProvider) do(method, path string, body, out any) error {
+	repository, err := p.repository()
 	if err != nil {
 		return err
 	}
 
-	// Add label to PR
-	cmd := exec.Command("gh", "pr", "edit", branch, "--add-label", label)
-	cmd.Dir = p.dir
+	repoPath := "repos/" + repository
+	if path != "" {
+		repoPath += "/" + path
+	}
+	return p.client.Do(method, repoPath, body, out)
+}
+
+func (pullRequest pullRequest) labelNames() []string {
+	var names []string
+	for _, label := range pullRequest.Labels {
+		names = append(names, label.Name)
+	}
+	return names
+}
+
+var remoteRepositoryRegex = regexp.MustCompile(`[:/]([^/:]+/[^/]+?)(?:\.git)?/?$`)
+
+// remote is the GitHub host and "owner/repo" path of the repository that a git remote points to.
+type remote struct {
+	host       string
+	repository string
+}
+
+// getRemote returns the host and repository that given directory's origin remote points to. The host of the remote
+// url is only trusted when known to be a GitHub host, falling back to the default host otherwise.
+func getRemote(dir string) (remote, error) {
+	cmd := exec.Command("git", "remote", "get-url", "origin")
+	cmd.Dir = dir
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return fmt.Errorf("adding label %s to branch %s: %s", label, branch, output)
+		return remote{}, fmt.Errorf("getting origin remote url: %s", output)
 	}
-	return nil
+	remoteURL := strings.TrimSpace(string(output))
+
+	repository, err := parseRemoteRepository(remoteURL)
+	if err != nil {
+		return remote{}, err
+	}
+
+	host := parseRemoteHost(remoteURL)
+	if host == "" || !isKnownHost(host) {
+		host = DefaultHost()
+	}
+	return remote{host: host, repository: repository}, nil
+}
+
+func parseRemoteRepository(remoteURL string) (string, error) {
+	match := remoteRepositoryRegex.FindStringSubmatch(remoteURL)
+	if match == nil {
+		return "", fmt.Errorf("cannot determine github repository from remote url %q", remoteURL)
+	}
+	return match[1], nil
+}
+
+// parseRemoteHost returns the host of given remote url, which can also use the scp-like syntax of git
+// (ie: git@github.com:owner/repo.git), or an empty string if it has none.
+func parseRemoteHost(remoteURL string) string {
+	if parsed, err := url.Parse(remoteURL); err == nil && parsed.Host != "" {
+		return parsed.Hostname()
+	}
+	host, _, ok := strings.Cut(remoteURL, ":")
+	if !ok {
+		return ""
+	}
+	if _, afterUser, ok := strings.Cut(host, "@"); ok {
+		host = afterUser
+	}
+	return host
 }
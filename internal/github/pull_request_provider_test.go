@@ -0,0 +1,484 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/nestoca/joy/internal/git/pr"
+)
+
+// fakeGitHub is a minimal in-memory stand-in for the GitHub pull requests API.
+type fakeGitHub struct {
+	pullRequests       []*pullRequest
+	labels             []string
+	comments           map[int][]string
+	requestedReviewers map[int][]string
+	deletedRefs        []string
+	created            map[string]any
+	url                string
+	checkRuns          map[string]checkRuns
+	statuses           map[string]combinedStatus
+	reviews            map[int][]review
+	listQueries        []url.Values
+	issueQueries       []url.Values
+}
+
+func newFakeGitHub(t *testing.T) (*fakeGitHub, *PullRequestProvider) {
+	fake := &fakeGitHub{
+		labels:             []string{"environment:prod"},
+		comments:           make(map[int][]string),
+		requestedReviewers: make(map[int][]string),
+		checkRuns:          make(map[string]checkRuns),
+		statuses:           make(map[string]combinedStatus),
+		reviews:            make(map[int][]review),
+	}
+
+	mux := http.NewServeMux()
+	repoPath := "/repos/owner/catalog"
+
+	mux.HandleFunc("GET "+repoPath, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		writeJSON(w, repository{DefaultBranch: "master", HTMLURL: "https://github.com/owner/catalog"})
+	})
+
+	mux.HandleFunc("GET "+repoPath+"/issues", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "open", r.URL.Query().Get("state"))
+		fake.issueQueries = append(fake.issueQueries, r.URL.Query())
+		labels := strings.Split(r.URL.Query().Get("labels"), ",")
+
+		// Plain issues are listed along with pull requests, as with the actual API
+		issues := []map[string]any{{"number": 1000}}
+		for _, pullRequest := range fake.pullRequests {
+			if pullRequest.hasLabels(labels) {
+				issues = append(issues, map[string]any{"number": pullRequest.Number, "pull_request": map[string]any{}})
+			}
+		}
+		writeJSON(w, paginate(r, issues))
+	})
+
+	mux.HandleFunc("GET "+repoPath+"/labels", func(w http.ResponseWriter, r *http.Request) {
+		var labels []label
+		for _, name := range fake.labels {
+			labels = append(labels, label{Name: name})
+		}
+		writeJSON(w, paginate(r, labels))
+	})
+
+	mux.HandleFunc("POST "+repoPath+"/labels", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		fake.labels = append(fake.labels, body["name"])
+		writeJSON(w, label{Name: body["name"]})
+	})
+
+	mux.HandleFunc("GET "+repoPath+"/pulls", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "open", r.URL.Query().Get("state"))
+		fake.listQueries = append(fake.listQueries, r.URL.Query())
+		if head := r.URL.Query().Get("head"); head != "" {
+			var matching []*pullRequest
+			for _, pullRequest := range fake.pullRequests {
+				if "owner:"+pullRequest.Head.Ref == head {
+					matching = append(matching, pullRequest)
+				}
+			}
+			writeJSON(w, matching)
+			return
+		}
+		writeJSON(w, paginate(r, fake.pullRequests))
+	})
+
+	mux.HandleFunc("POST "+repoPath+"/pulls", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		fake.created = body
+
+		created := newPullRequest(len(fake.pullRequests)+1, body["head"].(string))
+		created.Title = body["title"].(string)
+		created.HTMLURL = fmt.Sprintf("https://github.com/owner/catalog/pull/%d", created.Number)
+		fake.pullRequests = append(fake.pullRequests, created)
+		writeJSON(w, created)
+	})
+
+	mux.HandleFunc("GET "+repoPath+"/pulls/{number}", func(w http.ResponseWriter, r *http.Request) {
+		pullRequest := fake.find(r.PathValue("number"))
+		if pullRequest == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		writeJSON(w, pullRequest)
+	})
+
+	mux.HandleFunc("GET "+repoPath+"/pulls/{number}/reviews", func(w http.ResponseWriter, r *http.Request) {
+		number, _ := strconv.Atoi(r.PathValue("number"))
+		writeJSON(w, fake.reviews[number])
+	})
+
+	mux.HandleFunc("GET "+repoPath+"/commits/{sha}/check-runs", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, fake.checkRuns[r.PathValue("sha")])
+	})
+
+	mux.HandleFunc("GET "+repoPath+"/commits/{sha}/status", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, fake.statuses[r.PathValue("sha")])
+	})
+
+	mux.HandleFunc("PATCH "+repoPath+"/pulls/{number}", func(w http.ResponseWriter, r *http.Request) {
+		closed := fake.find(r.PathValue("number"))
+		if closed == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		if body["state"] == "closed" {
+			fake.pullRequests = slices.DeleteFunc(fake.pullRequests, func(candidate *pullRequest) bool {
+				return candidate == closed
+			})
+		}
+		writeJSON(w, closed)
+	})
+
+	mux.HandleFunc("POST "+repoPath+"/pulls/{number}/requested_reviewers", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string][]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		number, _ := strconv.Atoi(r.PathValue("number"))
+		fake.requestedReviewers[number] = body["reviewers"]
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	mux.HandleFunc("POST "+repoPath+"/issues/{number}/comments", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		number, _ := strconv.Atoi(r.PathValue("number"))
+		fake.comments[number] = append(fake.comments[number], body["body"])
+		writeJSON(w, body)
+	})
+
+	mux.HandleFunc("POST "+repoPath+"/issues/{number}/labels", func(w http.ResponseWriter, r *http.Request) {
+		labelled := fake.find(r.PathValue("number"))
+		var body map[string][]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		for _, name := range body["labels"] {
+			labelled.Labels = append(labelled.Labels, label{Name: name})
+		}
+		writeJSON(w, labelled.Labels)
+	})
+
+	mux.HandleFunc("DELETE "+repoPath+"/issues/{number}/labels/{name}", func(w http.ResponseWriter, r *http.Request) {
+		labelled := fake.find(r.PathValue("number"))
+		labelled.Labels = slices.DeleteFunc(labelled.Labels, func(label label) bool {
+			return label.Name == r.PathValue("name")
+		})
+		writeJSON(w, labelled.Labels)
+	})
+
+	mux.HandleFunc("DELETE "+repoPath+"/git/refs/heads/{ref...}", func(w http.ResponseWriter, r *http.Request) {
+		fake.deletedRefs = append(fake.deletedRefs, r.PathValue("ref"))
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	fake.url = server.URL
+
+	return fake, newTestPullRequestProvider(server.URL, "secret")
+}
+
+func newTestPullRequestProvider(url, token string) *PullRequestProvider {
+	return &PullRequestProvider{
+		client: NewClient(url, func() (string, error) {
+			if token == "" {
+				return "", ErrNoToken
+			}
+			return token, nil
+		}),
+		repository: func() (string, error) {
+			return "owner/catalog", nil
+		},
+	}
+}
+
+func (fake *fakeGitHub) find(number string) *pullRequest {
+	for _, pullRequest := range fake.pullRequests {
+		if strconv.Itoa(pullRequest.Number) == number {
+			return pullRequest
+		}
+	}
+	return nil
+}
+
+func (pullRequest pullRequest) hasLabels(labels []string) bool {
+	names := pullRequest.labelNames()
+	for _, label := range labels {
+		if !slices.Contains(names, label) {
+			return false
+		}
+	}
+	return true
+}
+
+func paginate[T any](r *http.Request, items []T) []T {
+	perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	start := min((page-1)*perPage, len(items))
+	end := min(start+perPage, len(items))
+	return items[start:end]
+}
+
+func writeJSON(w http.ResponseWriter, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+func newPullRequest(number int, branch string, labels ...string) *pullRequest {
+	pullRequest := &pullRequest{Number: number}
+	pullRequest.Head.Ref = branch
+	for _, name := range labels {
+		pullRequest.Labels = append(pullRequest.Labels, label{Name: name})
+	}
+	return pullRequest
+}
+
+func TestEnsureInstalledAndAuthenticated(t *testing.T) {
+	fake, provider := newFakeGitHub(t)
+	require.NoError(t, provider.EnsureInstalledAndAuthenticated())
+
+	provider = newTestPullRequestProvider(fake.url, "wrong")
+	require.ErrorContains(t, provider.EnsureInstalledAndAuthenticated(), "401")
+
+	provider = newTestPullRequestProvider(fake.url, "")
+	require.ErrorIs(t, provider.EnsureInstalledAndAuthenticated(), ErrNoToken)
+}
+
+func TestCreate(t *testing.T) {
+	fake, provider := newFakeGitHub(t)
+
+	url, err := provider.Create(pr.CreateParams{
+		Branch:    "promote-release1",
+		Title:     "Promote release1",
+		Body:      "Some description",
+		Labels:    []string{"environment:prod", "release:release1"},
+		Reviewers: []string{"alice", "github-actions[bot]", "bob"},
+		Draft:     true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "https://github.com/owner/catalog/pull/1", url)
+
+	require.Equal(t, map[string]any{
+		"head":  "promote-release1",
+		"base":  "master",
+		"title": "Promote release1",
+		"body":  "Some description",
+		"draft": true,
+	}, fake.created)
+	require.Equal(t, []string{"environment:prod", "release:release1"}, fake.labels)
+	require.Equal(t, []string{"environment:prod", "release:release1"}, fake.pullRequests[0].labelNames())
+	require.Equal(t, []string{"alice", "bob"}, fake.requestedReviewers[1])
+
+	exists, err := provider.Exists("promote-release1")
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	exists, err = provider.Exists("other")
+	require.NoError(t, err)
+	require.False(t, exists)
+}
+
+func TestListOpen(t *testing.T) {
+	fake, provider := newFakeGitHub(t)
+	for i := 1; i <= pageSize+5; i++ {
+		labels := []string{"environment:staging"}
+		if i%2 == 0 {
+			labels = []string{"environment:prod", "release:release1"}
+		}
+		fake.pullRequests = append(fake.pullRequests, newPullRequest(i, fmt.Sprintf("branch%d", i), labels...))
+	}
+
+	all, err := provider.ListOpen()
+	require.NoError(t, err)
+	require.Len(t, all, pageSize+5)
+
+	prod, err := provider.ListOpen("environment:prod", "release:release1")
+	require.NoError(t, err)
+	require.Len(t, prod, (pageSize+5)/2)
+	require.Equal(t, pr.PullRequest{Number: 2, Branch: "branch2", Labels: []string{"environment:prod", "release:release1"}}, prod[0])
+
+	// Labelled pull requests are filtered by the issues API rather than by paging through all pull requests
+	require.Len(t, fake.listQueries, 2)
+	require.Len(t, fake.issueQueries, 1)
+	require.Equal(t, "environment:prod,release:release1", fake.issueQueries[0].Get("labels"))
+}
+
+func TestExistsQueriesBranchOnly(t *testing.T) {
+	fake, provider := newFakeGitHub(t)
+	for i := 1; i <= pageSize+5; i++ {
+		fake.pullRequests = append(fake.pullRequests, newPullRequest(i, fmt.Sprintf("branch%d", i)))
+	}
+
+	exists, err := provider.Exists("branch103")
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	require.Len(t, fake.listQueries, 1)
+	require.Equal(t, "owner:branch103", fake.listQueries[0].Get("head"))
+}
+
+func TestClose(t *testing.T) {
+	fake, provider := newFakeGitHub(t)
+	fake.pullRequests = []*pullRequest{newPullRequest(7, "promote/release1")}
+
+	require.NoError(t, provider.Close(7, "Superseded by #8"))
+	require.Empty(t, fake.pullRequests)
+	require.Equal(t, []string{"Superseded by #8"}, fake.comments[7])
+	require.Equal(t, []string{"promote/release1"}, fake.deletedRefs)
+
+	require.ErrorContains(t, provider.Close(9, "Superseded"), "404")
+}
+
+func TestPromotionEnvironment(t *testing.T) {
+	fake, provider := newFakeGitHub(t)
+	fake.pullRequests = []*pullRequest{newPullRequest(1, "feature", "bug", "promote:staging")}
+
+	env, err := provider.GetPromotionEnvironment("feature")
+	require.NoError(t, err)
+	require.Equal(t, "staging", env)
+
+	require.NoError(t, provider.SetPromotionEnvironment("feature", "prod"))
+	require.Equal(t, []string{"bug", "promote:prod"}, fake.pullRequests[0].labelNames())
+	require.Contains(t, fake.labels, "promote:prod")
+
+	branches, err := provider.GetBranchesPromotingToEnvironment("prod")
+	require.NoError(t, err)
+	require.Equal(t, []string{"feature"}, branches)
+
+	require.NoError(t, provider.SetPromotionEnvironment("feature", ""))
+	require.Equal(t, []string{"bug"}, fake.pullRequests[0].labelNames())
+
+	env, err = provider.GetPromotionEnvironment("feature")
+	require.NoError(t, err)
+	require.Empty(t, env)
+
+	require.EqualError(t, provider.SetPromotionEnvironment("missing", "prod"), "no pull request found for branch missing")
+}
+
+func TestParseRemoteHost(t *testing.T) {
+	cases := map[string]string{
+		"git@github.com:owner/catalog.git":                 "github.com",
+		"https://github.example.com/owner/catalog.git":     "github.example.com",
+		"https://token@github.example.com:8443/owner/repo": "github.example.com",
+		"ssh://git@github.example.com/owner/catalog.git":   "github.example.com",
+		"github-work:owner/catalog.git":                    "github-work",
+		"catalog":                                          "",
+	}
+	for remoteURL, expected := range cases {
+		t.Run(remoteURL, func(t *testing.T) {
+			require.Equal(t, expected, parseRemoteHost(remoteURL))
+		})
+	}
+}
+
+func TestParseRemoteRepository(t *testing.T) {
+	cases := []struct {
+		RemoteURL string
+		Expected  string
+		Error     string
+	}{
+		{RemoteURL: "git@github.com:owner/catalog.git", Expected: "owner/catalog"},
+		{RemoteURL: "https://github.com/owner/catalog.git", Expected: "owner/catalog"},
+		{RemoteURL: "https://github.com/owner/catalog", Expected: "owner/catalog"},
+		{RemoteURL: "ssh://git@github.example.com/owner/my.catalog.git", Expected: "owner/my.catalog"},
+		{RemoteURL: "catalog", Error: `cannot determine github repository from remote url "catalog"`},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.RemoteURL, func(t *testing.T) {
+			repository, err := parseRemoteRepository(tc.RemoteURL)
+			if tc.Error != "" {
+				require.EqualError(t, err, tc.Error)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.Expected, repository)
+		})
+	}
+}
+
+func TestGetStatus(t *testing.T) {
+	fake, provider := newFakeGitHub(t)
+	promotion := newPullRequest(3, "promote-release1", "environment:prod")
+	promotion.State = "open"
+	promotion.Head.SHA = "abc123"
+	fake.pullRequests = []*pullRequest{promotion}
+
+	status, err := provider.GetStatus(3)
+	require.NoError(t, err)
+	require.Equal(t, &pr.Status{
+		PullRequest: pr.PullRequest{
+			Number: 3,
+			Branch: "promote-release1",
+			Labels: []string{"environment:prod"},
+		},
+		State:  pr.StateOpen,
+		Checks: pr.ChecksNone,
+		Review: pr.ReviewRequired,
+	}, status)
+
+	newReview := func(login, state string) review {
+		var review review
+		review.User.Login = login
+		review.State = state
+		return review
+	}
+
+	newCheckRuns := func(statusAndConclusions ...string) checkRuns {
+		var runs checkRuns
+		for i := 0; i < len(statusAndConclusions); i += 2 {
+			runs.CheckRuns = append(runs.CheckRuns, struct {
+				Status     string `json:"status"`
+				Conclusion string `json:"conclusion"`
+			}{Status: statusAndConclusions[i], Conclusion: statusAndConclusions[i+1]})
+		}
+		return runs
+	}
+
+	fake.checkRuns["abc123"] = newCheckRuns("completed", "success", "in_progress", "")
+	fake.reviews[3] = []review{newReview("alice", "CHANGES_REQUESTED"), newReview("bob", "APPROVED")}
+	status, err = provider.GetStatus(3)
+	require.NoError(t, err)
+	require.Equal(t, pr.ChecksPending, status.Checks)
+	require.Equal(t, pr.ReviewChangesRequested, status.Review)
+
+	fake.checkRuns["abc123"] = newCheckRuns("completed", "success", "completed", "skipped")
+	fake.statuses["abc123"] = combinedStatus{State: "success", TotalCount: 1}
+	fake.reviews[3] = append(fake.reviews[3], newReview("alice", "COMMENTED"), newReview("alice", "APPROVED"))
+	promotion.AutoMerge = &struct {
+		MergeMethod string `json:"merge_method"`
+	}{MergeMethod: "squash"}
+	status, err = provider.GetStatus(3)
+	require.NoError(t, err)
+	require.Equal(t, pr.ChecksSuccess, status.Checks)
+	require.Equal(t, pr.ReviewApproved, status.Review)
+	require.True(t, status.AutoMerge)
+
+	fake.statuses["abc123"] = combinedStatus{State: "failure", TotalCount: 1}
+	promotion.State = "closed"
+	promotion.Merged = true
+	status, err = provider.GetStatus(3)
+	require.NoError(t, err)
+	require.Equal(t, pr.StateMerged, status.State)
+	require.Equal(t, pr.ChecksFailure, status.Checks)
+
+	_, err = provider.GetStatus(4)
+	require.ErrorContains(t, err, "getting pull request #4")
+}
@@ -0,0 +1,122 @@
+package github
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const defaultHost = "github.com"
+
+// ErrNoToken is returned when no GitHub token could be found in the environment nor in the gh config.
+var ErrNoToken = errors.New("no github token found: set GITHUB_TOKEN or authenticate with `gh auth login`")
+
+// DefaultHost returns the GitHub host to use when none can be determined from a repository, which is github.com
+// unless overridden with the GH_HOST environment variable, as with the gh cli.
+func DefaultHost() string {
+	return cmp.Or(os.Getenv("GH_HOST"), defaultHost)
+}
+
+// tokenEnvVars returns the environment variables checked, in order, for a token of given host.
+func tokenEnvVars(host string) []string {
+	if host == defaultHost {
+		return []string{"GITHUB_TOKEN", "GH_TOKEN"}
+	}
+	return []string{"GH_ENTERPRISE_TOKEN", "GITHUB_ENTERPRISE_TOKEN", "GITHUB_TOKEN", "GH_TOKEN"}
+}
+
+// GetToken returns the token of given GitHub host from the environment, falling back to the token of the gh cli,
+// either from its hosts.yml config file or, when stored in the system keyring, from gh itself.
+// For GitHub Enterprise hosts, GH_ENTERPRISE_TOKEN and GITHUB_ENTERPRISE_TOKEN take precedence over GITHUB_TOKEN
+// and GH_TOKEN.
+func GetToken(host string) (string, error) {
+	for _, name := range tokenEnvVars(host) {
+		if token := os.Getenv(name); token != "" {
+			return token, nil
+		}
+	}
+
+	hosts, err := getHostsConfig(getConfigDir())
+	if err != nil {
+		return "", err
+	}
+	if token := hosts[host].OAuthToken; token != "" {
+		return token, nil
+	}
+
+	if dependency.IsInstalled() {
+		output, err := exec.Command("gh", "auth", "token", "--hostname", host).Output()
+		if err == nil && len(output) > 0 {
+			return strings.TrimSpace(string(output)), nil
+		}
+	}
+
+	return "", ErrNoToken
+}
+
+// isKnownHost returns whether given host is known to be a GitHub host, either because it is the default host, the
+// server running the current GitHub Actions workflow or a host that the gh cli is authenticated with. This prevents
+// mistaking ssh host aliases, such as "github-work", for GitHub Enterprise hosts.
+func isKnownHost(host string) bool {
+	if host == DefaultHost() {
+		return true
+	}
+	if serverURL, err := url.Parse(os.Getenv("GITHUB_SERVER_URL")); err == nil && serverURL.Hostname() == host {
+		return true
+	}
+	hosts, err := getHostsConfig(getConfigDir())
+	if err != nil {
+		return false
+	}
+	_, ok := hosts[host]
+	return ok
+}
+
+// getConfigDir returns the gh cli config directory, following the same precedence as gh itself.
+func getConfigDir() string {
+	if dir := os.Getenv("GH_CONFIG_DIR"); dir != "" {
+		return dir
+	}
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "gh")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "gh")
+}
+
+type hostConfig struct {
+	OAuthToken string `yaml:"oauth_token"`
+}
+
+// getHostsConfig returns the hosts configured in the hosts.yml file of given gh config directory, by host name,
+// or nil if there is none.
+func getHostsConfig(configDir string) (map[string]hostConfig, error) {
+	if configDir == "" {
+		return nil, nil
+	}
+
+	path := filepath.Join(configDir, "hosts.yml")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading gh config: %w", err)
+	}
+
+	var hosts map[string]hostConfig
+	if err := yaml.Unmarshal(data, &hosts); err != nil {
+		return nil, fmt.Errorf("parsing gh config %s: %w", path, err)
+	}
+	return hosts, nil
+}
@@ -0,0 +1,102 @@
+package github
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetToken(t *testing.T) {
+	configDir := t.TempDir()
+	hosts := "github.com:\n  user: joy\n  oauth_token: config-token\n"
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "hosts.yml"), []byte(hosts), 0o600))
+
+	t.Setenv("GH_CONFIG_DIR", configDir)
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GH_TOKEN", "")
+
+	token, err := GetToken("github.com")
+	require.NoError(t, err)
+	require.Equal(t, "config-token", token)
+
+	t.Setenv("GH_TOKEN", "gh-token")
+	token, err = GetToken("github.com")
+	require.NoError(t, err)
+	require.Equal(t, "gh-token", token)
+
+	t.Setenv("GITHUB_TOKEN", "github-token")
+	token, err = GetToken("github.com")
+	require.NoError(t, err)
+	require.Equal(t, "github-token", token)
+}
+
+func TestGetEnterpriseToken(t *testing.T) {
+	configDir := t.TempDir()
+	hosts := "github.com:\n  oauth_token: config-token\ngithub.example.com:\n  oauth_token: enterprise-config-token\n"
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "hosts.yml"), []byte(hosts), 0o600))
+
+	t.Setenv("GH_CONFIG_DIR", configDir)
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GH_TOKEN", "")
+	t.Setenv("GH_ENTERPRISE_TOKEN", "")
+	t.Setenv("GITHUB_ENTERPRISE_TOKEN", "")
+
+	token, err := GetToken("github.example.com")
+	require.NoError(t, err)
+	require.Equal(t, "enterprise-config-token", token)
+
+	t.Setenv("GITHUB_TOKEN", "github-token")
+	token, err = GetToken("github.example.com")
+	require.NoError(t, err)
+	require.Equal(t, "github-token", token)
+
+	t.Setenv("GH_ENTERPRISE_TOKEN", "enterprise-token")
+	token, err = GetToken("github.example.com")
+	require.NoError(t, err)
+	require.Equal(t, "enterprise-token", token)
+
+	token, err = GetToken("github.com")
+	require.NoError(t, err)
+	require.Equal(t, "github-token", token)
+}
+
+func TestGetHostsConfig(t *testing.T) {
+	hosts, err := getHostsConfig(t.TempDir())
+	require.NoError(t, err)
+	require.Empty(t, hosts)
+
+	configDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "hosts.yml"), []byte("github.example.com:\n  oauth_token: other\n"), 0o600))
+	hosts, err = getHostsConfig(configDir)
+	require.NoError(t, err)
+	require.Empty(t, hosts["github.com"].OAuthToken)
+	require.Equal(t, "other", hosts["github.example.com"].OAuthToken)
+
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "hosts.yml"), []byte("- not a map"), 0o600))
+	_, err = getHostsConfig(configDir)
+	require.ErrorContains(t, err, "parsing gh config")
+}
+
+func TestIsKnownHost(t *testing.T) {
+	configDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "hosts.yml"), []byte("github.example.com:\n  oauth_token: other\n"), 0o600))
+	t.Setenv("GH_CONFIG_DIR", configDir)
+	t.Setenv("GH_HOST", "")
+	t.Setenv("GITHUB_SERVER_URL", "https://github.actions.example.com")
+
+	require.True(t, isKnownHost("github.com"))
+	require.True(t, isKnownHost("github.example.com"))
+	require.True(t, isKnownHost("github.actions.example.com"))
+	require.False(t, isKnownHost("github-work"))
+}
+
+func TestAPIURL(t *testing.T) {
+	t.Setenv("GITHUB_API_URL", "")
+	require.Equal(t, "https://api.github.com", APIURL("github.com"))
+	require.Equal(t, "https://github.example.com/api/v3", APIURL("github.example.com"))
+
+	t.Setenv("GITHUB_API_URL", "https://github.actions.example.com/api/v3")
+	require.Equal(t, "https://github.actions.example.com/api/v3", APIURL("github.com"))
+}
@@ -0,0 +1,346 @@
+package gitlab
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/browser"
+
+	"github.com/nestoca/joy/internal/git/pr"
+	"github.com/nestoca/joy/internal/rest"
+	"github.com/nestoca/joy/internal/style"
+)
+
+// TokenEnvVar is the default environment variable holding the GitLab API token.
+const TokenEnvVar = "GITLAB_TOKEN"
+
+const pageSize = 100
+
+// PullRequestProvider manages GitLab merge requests of a given project via the GitLab REST API.
+type PullRequestProvider struct {
+	client     *rest.Client
+	url        string
+	repository string
+	token      string
+}
+
+// NewPullRequestProvider returns a provider for the merge requests of given repository (ie: "group/catalog")
+// on the GitLab instance at given base URL, authenticating with given token.
+func NewPullRequestProvider(baseURL, repository, token string) *PullRequestProvider {
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	header := http.Header{}
+	if token != "" {
+		header.Set("PRIVATE-TOKEN", token)
+	}
+	return &PullRequestProvider{
+		client:     rest.NewClient(baseURL+"/api/v4", header),
+		url:        baseURL,
+		repository: repository,
+		token:      token,
+	}
+}
+
+type mergeRequest struct {
+	IID                       int      `json:"iid"`
+	WebURL                    string   `json:"web_url"`
+	Title                     string   `json:"title"`
+	SourceBranch              string   `json:"source_branch"`
+	Labels                    []string `json:"labels"`
+	State                     string   `json:"state"`
+	DetailedMergeStatus       string   `json:"detailed_merge_status"`
+	MergeWhenPipelineSucceeds bool     `json:"merge_when_pipeline_succeeds"`
+	HeadPipeline              *struct {
+		Status string `json:"status"`
+	} `json:"head_pipeline"`
+}
+
+type approvals struct {
+	Approved bool `json:"approved"`
+}
+
+type project struct {
+	DefaultBranch string `json:"default_branch"`
+}
+
+type user struct {
+	ID       int    `json:"id"`
+	Username string `json:"username"`
+}
+
+func (p *PullRequestProvider) EnsureInstalledAndAuthenticated() error {
+	if p.token == "" {
+		fmt.Printf("🔐 Please set the %s environment variable to a GitLab access token with api scope.\n", style.Code(TokenEnvVar))
+		return errors.New("gitlab token not set")
+	}
+	if err := p.client.Do(http.MethodGet, "user", nil, &user{}); err != nil {
+		return fmt.Errorf("authenticating with gitlab: %w", err)
+	}
+	return nil
+}
+
+func (p *PullRequestProvider) Exists(branch string) (bool, error) {
+	mr, err := p.get(branch)
+	if err != nil {
+		return false, fmt.Errorf("getting merge request for branch %s: %w", branch, err)
+	}
+	return mr != nil, nil
+}
+
+func (p *PullRequestProvider) GetBranchesPromotingToEnvironment(env string) ([]string, error) {
+	mrs, err := p.list(url.Values{"labels": {pr.PromotionLabel(env)}})
+	if err != nil {
+		return nil, fmt.Errorf("getting merge requests: %w", err)
+	}
+
+	var branches []string
+	for _, mr := range mrs {
+		branches = append(branches, mr.SourceBranch)
+	}
+	return branches, nil
+}
+
+func (p *PullRequestProvider) CreateInteractively(branch string) error {
+	query := url.Values{"merge_request[source_branch]": {branch}}
+	newURL := fmt.Sprintf("%s/%s/-/merge_requests/new?%s", p.url, p.repository, query.Encode())
+	if err := browser.OpenURL(newURL); err != nil {
+		return fmt.Errorf("opening merge request creation page for branch %s: %w", branch, err)
+	}
+	return nil
+}
+
+func (p *PullRequestProvider) Create(params pr.CreateParams) (string, error) {
+	var proj project
+	if err := p.client.Do(http.MethodGet, p.projectPath(""), nil, &proj); err != nil {
+		return "", fmt.Errorf("getting project %s: %w", p.repository, err)
+	}
+
+	reviewerIDs, err := p.getUserIDs(params.Reviewers)
+	if err != nil {
+		return "", fmt.Errorf("getting reviewers: %w", err)
+	}
+
+	title := params.Title
+	if params.Draft {
+		title = "Draft: " + title
+	}
+
+	body := map[string]any{
+		"source_branch":        params.Branch,
+		"target_branch":        proj.DefaultBranch,
+		"title":                title,
+		"description":          params.Body,
+		"labels":               strings.Join(params.Labels, ","),
+		"reviewer_ids":         reviewerIDs,
+		"remove_source_branch": true,
+	}
+
+	var mr mergeRequest
+	if err := p.client.Do(http.MethodPost, p.projectPath("merge_requests"), body, &mr); err != nil {
+		return "", fmt.Errorf("creating merge request for branch %s: %w", params.Branch, err)
+	}
+	return mr.WebURL, nil
+}
+
+func (p *PullRequestProvider) ListOpen(labels ...string) ([]pr.PullRequest, error) {
+	query := url.Values{}
+	if len(labels) > 0 {
+		query.Set("labels", strings.Join(labels, ","))
+	}
+
+	mrs, err := p.list(query)
+	if err != nil {
+		return nil, fmt.Errorf("listing merge requests with labels %s: %w", strings.Join(labels, ", "), err)
+	}
+
+	result := make([]pr.PullRequest, len(mrs))
+	for i, mr := range mrs {
+		result[i] = pr.PullRequest{
+			Number: mr.IID,
+			URL:    mr.WebURL,
+			Title:  mr.Title,
+			Branch: mr.SourceBranch,
+			Labels: mr.Labels,
+		}
+	}
+	return result, nil
+}
+
+func (p *PullRequestProvider) GetStatus(number int) (*pr.Status, error) {
+	mrPath := p.projectPath("merge_requests/" + strconv.Itoa(number))
+
+	var mr mergeRequest
+	if err := p.client.Do(http.MethodGet, mrPath, nil, &mr); err != nil {
+		return nil, fmt.Errorf("getting merge request !%d: %w", number, err)
+	}
+
+	var approvals approvals
+	if err := p.client.Do(http.MethodGet, mrPath+"/approvals", nil, &approvals); err != nil {
+		return nil, fmt.Errorf("getting approvals of merge request !%d: %w", number, err)
+	}
+
+	status := &pr.Status{
+		PullRequest: pr.PullRequest{
+			Number: mr.IID,
+			URL:    mr.WebURL,
+			Title:  mr.Title,
+			Branch: mr.SourceBranch,
+			Labels: mr.Labels,
+		},
+		State:     pr.StateOpen,
+		Checks:    pr.ChecksNone,
+		Review:    pr.ReviewRequired,
+		AutoMerge: mr.MergeWhenPipelineSucceeds || slices.Contains(mr.Labels, pr.AutoMergeLabel),
+	}
+
+	switch mr.State {
+	case "merged":
+		status.State = pr.StateMerged
+	case "closed", "locked":
+		status.State = pr.StateClosed
+	}
+
+	if mr.HeadPipeline != nil {
+		switch mr.HeadPipeline.Status {
+		case "success", "skipped":
+			status.Checks = pr.ChecksSuccess
+		case "failed", "canceled":
+			status.Checks = pr.ChecksFailure
+		default:
+			status.Checks = pr.ChecksPending
+		}
+	}
+
+	switch {
+	case mr.DetailedMergeStatus == "requested_changes":
+		status.Review = pr.ReviewChangesRequested
+	case approvals.Approved:
+		status.Review = pr.ReviewApproved
+	}
+
+	return status, nil
+}
+
+func (p *PullRequestProvider) Close(number int, comment string) error {
+	mrPath := p.projectPath("merge_requests/" + strconv.Itoa(number))
+
+	if err := p.client.Do(http.MethodPost, mrPath+"/notes", map[string]string{"body": comment}, nil); err != nil {
+		return fmt.Errorf("commenting on merge request !%d: %w", number, err)
+	}
+
+	var mr mergeRequest
+	if err := p.client.Do(http.MethodPut, mrPath, map[string]string{"state_event": "close"}, &mr); err != nil {
+		return fmt.Errorf("closing merge request !%d: %w", number, err)
+	}
+
+	branchPath := p.projectPath("repository/branches/" + url.PathEscape(mr.SourceBranch))
+	if err := p.client.Do(http.MethodDelete, branchPath, nil, nil); err != nil && !rest.IsNotFound(err) {
+		return fmt.Errorf("deleting branch %s of merge request !%d: %w", mr.SourceBranch, number, err)
+	}
+	return nil
+}
+
+func (p *PullRequestProvider) GetPromotionEnvironment(branch string) (string, error) {
+	mr, err := p.get(branch)
+	if err != nil {
+		return "", fmt.Errorf("getting merge request for branch %s: %w", branch, err)
+	}
+	if mr == nil {
+		return "", nil
+	}
+	for _, label := range mr.Labels {
+		if env, ok := pr.ParsePromotionLabel(label); ok {
+			return env, nil
+		}
+	}
+	return "", nil
+}
+
+func (p *PullRequestProvider) SetPromotionEnvironment(branch, env string) error {
+	mr, err := p.get(branch)
+	if err != nil {
+		return fmt.Errorf("getting merge request for branch %s: %w", branch, err)
+	}
+	if mr == nil {
+		return fmt.Errorf("no merge request found for branch %s", branch)
+	}
+
+	// Typically, there is only one or no promotion label, but we cannot guarantee there are not many
+	var removedLabels []string
+	for _, label := range mr.Labels {
+		if _, ok := pr.ParsePromotionLabel(label); ok {
+			removedLabels = append(removedLabels, label)
+		}
+	}
+
+	body := map[string]string{"remove_labels": strings.Join(removedLabels, ",")}
+	if env != "" {
+		body["add_labels"] = pr.PromotionLabel(env)
+	}
+
+	if err := p.client.Do(http.MethodPut, p.projectPath("merge_requests/"+strconv.Itoa(mr.IID)), body, nil); err != nil {
+		return fmt.Errorf("updating labels of merge request for branch %s: %w", branch, err)
+	}
+	return nil
+}
+
+func (p *PullRequestProvider) get(branch string) (*mergeRequest, error) {
+	mrs, err := p.list(url.Values{"source_branch": {branch}})
+	if err != nil {
+		return nil, err
+	}
+
+	// We can safely assume that there is either none or only one MR for a given branch
+	if len(mrs) == 0 {
+		return nil, nil
+	}
+	return &mrs[0], nil
+}
+
+// list returns all open merge requests of the project matching given query, across all pages.
+func (p *PullRequestProvider) list(query url.Values) ([]mergeRequest, error) {
+	query.Set("state", "opened")
+	query.Set("per_page", strconv.Itoa(pageSize))
+
+	var result []mergeRequest
+	for page := 1; ; page++ {
+		query.Set("page", strconv.Itoa(page))
+
+		var mrs []mergeRequest
+		if err := p.client.Do(http.MethodGet, p.projectPath("merge_requests?"+query.Encode()), nil, &mrs); err != nil {
+			return nil, err
+		}
+		result = append(result, mrs...)
+
+		if len(mrs) < pageSize {
+			return result, nil
+		}
+	}
+}
+
+// getUserIDs returns the IDs of given usernames, skipping those unknown to GitLab.
+func (p *PullRequestProvider) getUserIDs(usernames []string) ([]int, error) {
+	ids := []int{}
+	for _, username := range usernames {
+		var users []user
+		if err := p.client.Do(http.MethodGet, "users?"+url.Values{"username": {username}}.Encode(), nil, &users); err != nil {
+			return nil, fmt.Errorf("getting user %s: %w", username, err)
+		}
+		if len(users) > 0 {
+			ids = append(ids, users[0].ID)
+		}
+	}
+	return ids, nil
+}
+
+func (p *PullRequestProvider) projectPath(subPath string) string {
+	path := "projects/" + url.PathEscape(p.repository)
+	if subPath != "" {
+		path += "/" + subPath
+	}
+	return path
+}
@@ -0,0 +1,341 @@
+package gitlab
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/nestoca/joy/internal/git/pr"
+)
+
+const testRepository = "group/catalog"
+
+// fakeGitLab is a minimal in-memory stand-in for the GitLab merge requests API.
+type fakeGitLab struct {
+	mergeRequests   []*mergeRequest
+	notes           map[int][]string
+	deletedBranches []string
+	created         map[string]any
+	approved        map[int]bool
+}
+
+func newFakeGitLab(t *testing.T) (*fakeGitLab, *PullRequestProvider) {
+	fake := &fakeGitLab{notes: make(map[int][]string), approved: make(map[int]bool)}
+
+	mux := http.NewServeMux()
+	projectPath := "/api/v4/projects/{project}"
+
+	mux.HandleFunc("GET /api/v4/user", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("PRIVATE-TOKEN") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		writeJSON(w, user{ID: 1, Username: "joy"})
+	})
+
+	mux.HandleFunc("GET /api/v4/users", func(w http.ResponseWriter, r *http.Request) {
+		users := map[string]int{"alice": 10, "bob": 11}
+		username := r.URL.Query().Get("username")
+		if id, ok := users[username]; ok {
+			writeJSON(w, []user{{ID: id, Username: username}})
+			return
+		}
+		writeJSON(w, []user{})
+	})
+
+	mux.HandleFunc("GET "+projectPath, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, testRepository, r.PathValue("project"))
+		writeJSON(w, project{DefaultBranch: "master"})
+	})
+
+	mux.HandleFunc("GET "+projectPath+"/merge_requests", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		require.Equal(t, "opened", query.Get("state"))
+
+		var result []*mergeRequest
+		for _, mr := range fake.mergeRequests {
+			if branch := query.Get("source_branch"); branch != "" && mr.SourceBranch != branch {
+				continue
+			}
+			if labels := query.Get("labels"); labels != "" && !hasAll(mr.Labels, strings.Split(labels, ",")) {
+				continue
+			}
+			result = append(result, mr)
+		}
+
+		perPage, _ := strconv.Atoi(query.Get("per_page"))
+		page, _ := strconv.Atoi(query.Get("page"))
+		start := min((page-1)*perPage, len(result))
+		end := min(start+perPage, len(result))
+		writeJSON(w, result[start:end])
+	})
+
+	mux.HandleFunc("POST "+projectPath+"/merge_requests", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		fake.created = body
+
+		mr := &mergeRequest{
+			IID:          len(fake.mergeRequests) + 1,
+			Title:        body["title"].(string),
+			SourceBranch: body["source_branch"].(string),
+			Labels:       strings.Split(body["labels"].(string), ","),
+		}
+		mr.WebURL = fmt.Sprintf("https://gitlab.example.com/%s/-/merge_requests/%d", testRepository, mr.IID)
+		fake.mergeRequests = append(fake.mergeRequests, mr)
+		writeJSON(w, mr)
+	})
+
+	mux.HandleFunc("GET "+projectPath+"/merge_requests/{iid}", func(w http.ResponseWriter, r *http.Request) {
+		mr := fake.find(r.PathValue("iid"))
+		if mr == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		writeJSON(w, mr)
+	})
+
+	mux.HandleFunc("GET "+projectPath+"/merge_requests/{iid}/approvals", func(w http.ResponseWriter, r *http.Request) {
+		iid, _ := strconv.Atoi(r.PathValue("iid"))
+		writeJSON(w, approvals{Approved: fake.approved[iid]})
+	})
+
+	mux.HandleFunc("PUT "+projectPath+"/merge_requests/{iid}", func(w http.ResponseWriter, r *http.Request) {
+		mr := fake.find(r.PathValue("iid"))
+		if mr == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		if body["state_event"] == "close" {
+			fake.remove(mr)
+		}
+
+		var labels []string
+		for _, label := range mr.Labels {
+			if !slices.Contains(strings.Split(body["remove_labels"], ","), label) {
+				labels = append(labels, label)
+			}
+		}
+		if body["add_labels"] != "" {
+			labels = append(labels, strings.Split(body["add_labels"], ",")...)
+		}
+		mr.Labels = labels
+		writeJSON(w, mr)
+	})
+
+	mux.HandleFunc("POST "+projectPath+"/merge_requests/{iid}/notes", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		iid, _ := strconv.Atoi(r.PathValue("iid"))
+		fake.notes[iid] = append(fake.notes[iid], body["body"])
+		writeJSON(w, body)
+	})
+
+	mux.HandleFunc("DELETE "+projectPath+"/repository/branches/{branch}", func(w http.ResponseWriter, r *http.Request) {
+		fake.deletedBranches = append(fake.deletedBranches, r.PathValue("branch"))
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return fake, NewPullRequestProvider(server.URL, testRepository, "secret")
+}
+
+func (fake *fakeGitLab) find(iid string) *mergeRequest {
+	for _, mr := range fake.mergeRequests {
+		if strconv.Itoa(mr.IID) == iid {
+			return mr
+		}
+	}
+	return nil
+}
+
+func (fake *fakeGitLab) remove(target *mergeRequest) {
+	for i, mr := range fake.mergeRequests {
+		if mr == target {
+			fake.mergeRequests = append(fake.mergeRequests[:i], fake.mergeRequests[i+1:]...)
+			return
+		}
+	}
+}
+
+func writeJSON(w http.ResponseWriter, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+func hasAll(values, required []string) bool {
+	for _, value := range required {
+		if !slices.Contains(values, value) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestEnsureInstalledAndAuthenticated(t *testing.T) {
+	_, provider := newFakeGitLab(t)
+	require.NoError(t, provider.EnsureInstalledAndAuthenticated())
+
+	provider.client.Header.Set("PRIVATE-TOKEN", "wrong")
+	require.ErrorContains(t, provider.EnsureInstalledAndAuthenticated(), "401 Unauthorized")
+
+	provider.token = ""
+	require.EqualError(t, provider.EnsureInstalledAndAuthenticated(), "gitlab token not set")
+}
+
+func TestCreate(t *testing.T) {
+	fake, provider := newFakeGitLab(t)
+
+	url, err := provider.Create(pr.CreateParams{
+		Branch:    "promote-release1",
+		Title:     "Promote release1",
+		Body:      "Some description",
+		Labels:    []string{"environment:prod", "release:release1"},
+		Reviewers: []string{"alice", "unknown", "bob"},
+		Draft:     true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "https://gitlab.example.com/group/catalog/-/merge_requests/1", url)
+
+	require.Equal(t, map[string]any{
+		"source_branch":        "promote-release1",
+		"target_branch":        "master",
+		"title":                "Draft: Promote release1",
+		"description":          "Some description",
+		"labels":               "environment:prod,release:release1",
+		"reviewer_ids":         []any{10.0, 11.0},
+		"remove_source_branch": true,
+	}, fake.created)
+
+	exists, err := provider.Exists("promote-release1")
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	exists, err = provider.Exists("other")
+	require.NoError(t, err)
+	require.False(t, exists)
+}
+
+func TestListOpen(t *testing.T) {
+	fake, provider := newFakeGitLab(t)
+	for i := 1; i <= pageSize+5; i++ {
+		labels := []string{"environment:staging"}
+		if i%2 == 0 {
+			labels = []string{"environment:prod", "release:release1"}
+		}
+		fake.mergeRequests = append(fake.mergeRequests, &mergeRequest{IID: i, SourceBranch: fmt.Sprintf("branch%d", i), Labels: labels})
+	}
+
+	all, err := provider.ListOpen()
+	require.NoError(t, err)
+	require.Len(t, all, pageSize+5)
+
+	prod, err := provider.ListOpen("environment:prod", "release:release1")
+	require.NoError(t, err)
+	require.Len(t, prod, (pageSize+5)/2)
+	require.Equal(t, pr.PullRequest{Number: 2, Branch: "branch2", Labels: []string{"environment:prod", "release:release1"}}, prod[0])
+}
+
+func TestClose(t *testing.T) {
+	fake, provider := newFakeGitLab(t)
+	fake.mergeRequests = []*mergeRequest{{IID: 7, SourceBranch: "promote-release1"}}
+
+	require.NoError(t, provider.Close(7, "Superseded by !8"))
+	require.Empty(t, fake.mergeRequests)
+	require.Equal(t, []string{"Superseded by !8"}, fake.notes[7])
+	require.Equal(t, []string{"promote-release1"}, fake.deletedBranches)
+
+	require.ErrorContains(t, provider.Close(9, "Superseded"), "404 Not Found")
+}
+
+func TestPromotionEnvironment(t *testing.T) {
+	fake, provider := newFakeGitLab(t)
+	fake.mergeRequests = []*mergeRequest{{IID: 1, SourceBranch: "feature", Labels: []string{"bug", "promote:staging"}}}
+
+	env, err := provider.GetPromotionEnvironment("feature")
+	require.NoError(t, err)
+	require.Equal(t, "staging", env)
+
+	require.NoError(t, provider.SetPromotionEnvironment("feature", "prod"))
+	require.Equal(t, []string{"bug", "promote:prod"}, fake.mergeRequests[0].Labels)
+
+	branches, err := provider.GetBranchesPromotingToEnvironment("prod")
+	require.NoError(t, err)
+	require.Equal(t, []string{"feature"}, branches)
+
+	require.NoError(t, provider.SetPromotionEnvironment("feature", ""))
+	require.Equal(t, []string{"bug"}, fake.mergeRequests[0].Labels)
+
+	env, err = provider.GetPromotionEnvironment("feature")
+	require.NoError(t, err)
+	require.Empty(t, env)
+
+	require.EqualError(t, provider.SetPromotionEnvironment("missing", "prod"), "no merge request found for branch missing")
+}
+
+func TestGetStatus(t *testing.T) {
+	fake, provider := newFakeGitLab(t)
+	mr := &mergeRequest{
+		IID:          3,
+		WebURL:       "https://gitlab.example.com/group/catalog/-/merge_requests/3",
+		SourceBranch: "promote-release1",
+		Labels:       []string{"environment:prod"},
+		State:        "opened",
+	}
+	fake.mergeRequests = []*mergeRequest{mr}
+
+	status, err := provider.GetStatus(3)
+	require.NoError(t, err)
+	require.Equal(t, &pr.Status{
+		PullRequest: pr.PullRequest{
+			Number: 3,
+			URL:    "https://gitlab.example.com/group/catalog/-/merge_requests/3",
+			Branch: "promote-release1",
+			Labels: []string{"environment:prod"},
+		},
+		State:  pr.StateOpen,
+		Checks: pr.ChecksNone,
+		Review: pr.ReviewRequired,
+	}, status)
+
+	mr.HeadPipeline = &struct {
+		Status string `json:"status"`
+	}{Status: "running"}
+	mr.DetailedMergeStatus = "requested_changes"
+	status, err = provider.GetStatus(3)
+	require.NoError(t, err)
+	require.Equal(t, pr.ChecksPending, status.Checks)
+	require.Equal(t, pr.ReviewChangesRequested, status.Review)
+
+	mr.State = "merged"
+	mr.HeadPipeline.Status = "success"
+	mr.DetailedMergeStatus = ""
+	mr.MergeWhenPipelineSucceeds = true
+	fake.approved[3] = true
+	status, err = provider.GetStatus(3)
+	require.NoError(t, err)
+	require.Equal(t, pr.StateMerged, status.State)
+	require.Equal(t, pr.ChecksSuccess, status.Checks)
+	require.Equal(t, pr.ReviewApproved, status.Review)
+	require.True(t, status.AutoMerge)
+
+	mr.HeadPipeline.Status = "failed"
+	status, err = provider.GetStatus(3)
+	require.NoError(t, err)
+	require.Equal(t, pr.ChecksFailure, status.Checks)
+
+	_, err = provider.GetStatus(4)
+	require.ErrorContains(t, err, "getting merge request !4")
+}
@@ -3,9 +3,10 @@ package info
 import (
 	"bytes"
 	"cmp"
-	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
+	"net/url"
 	"os"
 	"os/exec"
 	"path"
@@ -31,6 +32,7 @@ type CommitMetadata struct {
 	Sha     string
 	Author  string
 	Message string
+	Body    string
 }
 
 type defaultProvider struct {
@@ -38,6 +40,7 @@ type defaultProvider struct {
 	defaultGitTagTemplate string
 	repositoriesCacheDir  string
 	joyCacheDir           string
+	gitHub                *github.Client
 }
 
 func NewProvider(gitHubOrganization, defaultGitTagTemplate, repositoriesCacheDir, joyCacheDir string) Provider {
@@ -46,6 +49,7 @@ func NewProvider(gitHubOrganization, defaultGitTagTemplate, repositoriesCacheDir
 		defaultGitTagTemplate: defaultGitTagTemplate,
 		repositoriesCacheDir:  repositoriesCacheDir,
 		joyCacheDir:           joyCacheDir,
+		gitHub:                github.NewDefaultClient(),
 	}
 }
 
@@ -116,13 +120,8 @@ func (p *defaultProvider) GetReleaseGitTag(release *v1alpha1.Release) (string, e
 	return buffer.String(), nil
 }
 
-type commitInfo struct {
-	Sha   string `json:"sha"`
-	Login string `json:"login"`
-}
-
 func (p *defaultProvider) GetCommitsMetadata(dir, from, to string) ([]*CommitMetadata, error) {
-	gitArgs := []string{"log", "--pretty=format:%H%n%an%n%s%n---END---%n", from + ".." + to}
+	gitArgs := []string{"log", "--pretty=format:%H%n%an%n%s%n%b%n---END---%n", from + ".." + to}
 	cmd := exec.Command("git", gitArgs...)
 	cmd.Dir = dir
 	output, err := cmd.CombinedOutput()
@@ -137,44 +136,60 @@ func (p *defaultProvider) GetCommitsMetadata(dir, from, to string) ([]*CommitMet
 		if commitItem == "" {
 			continue
 		}
-		lines := strings.SplitN(commitItem, "\n", 3)
+		lines := strings.SplitN(commitItem, "\n", 4)
 		if len(lines) < 3 {
 			return nil, fmt.Errorf("malformed commit output: %q", commitItem)
 		}
 		sha := lines[0]
 		author := lines[1]
 		message := lines[2]
+		body := ""
+		if len(lines) > 3 {
+			body = strings.TrimSpace(lines[3])
+		}
 
 		commits = append(commits, &CommitMetadata{
 			Sha:     sha,
 			Author:  author,
 			Message: message,
+			Body:    body,
 		})
 	}
 	return commits, nil
 }
 
+type gitHubUser struct {
+	Login string `json:"login"`
+}
+
+type gitHubComparison struct {
+	Commits []struct {
+		Sha       string      `json:"sha"`
+		Author    *gitHubUser `json:"author"`
+		Committer *gitHubUser `json:"committer"`
+	} `json:"commits"`
+}
+
 func (p *defaultProvider) GetCommitsGitHubAuthors(project *v1alpha1.Project, fromTag, toTag string) (map[string]string, error) {
 	repository := project.Spec.Repository
 	if repository == "" {
 		repository = fmt.Sprintf("%s/%s", p.gitHubOrganization, project.Name)
 	}
 
-	ghPath := fmt.Sprintf("repos/%s/compare/%s...%s", repository, fromTag, toTag)
-	data, err := github.ExecuteAndGetOutput(".", "api", "--jq", "[.commits[] | {sha: .sha, login: (.author.login // .committer.login)}]", ghPath)
-	if err != nil {
+	var comparison gitHubComparison
+	comparePath := fmt.Sprintf("repos/%s/compare/%s...%s", repository, url.PathEscape(fromTag), url.PathEscape(toTag))
+	if err := p.gitHub.Do(http.MethodGet, comparePath, nil, &comparison); err != nil {
 		return nil, fmt.Errorf("getting commits GitHub authors: %w", err)
 	}
 
-	var commits []commitInfo
-	err = json.Unmarshal([]byte(data), &commits)
-	if err != nil {
-		return nil, fmt.Errorf("unmarshalling commits GitHub authors: %w", err)
-	}
-
 	authors := make(map[string]string)
-	for _, commit := range commits {
-		authors[commit.Sha] = commit.Login
+	for _, commit := range comparison.Commits {
+		switch {
+		case commit.Author != nil:
+			authors[commit.Sha] = commit.Author.Login
+		case commit.Committer != nil:
+			authors[commit.Sha] = commit.Committer.Login
+		}
 	}
 	return authors, nil
 }
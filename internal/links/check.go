@@ -0,0 +1,245 @@
+package links
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/nestoca/joy/internal/style"
+	"github.com/nestoca/joy/pkg/catalog"
+)
+
+type CheckOpts struct {
+	Catalog  *catalog.Catalog
+	Provider Provider
+
+	// Client, when set, is used to make a HEAD request to every rendered http(s) link, otherwise links are only
+	// rendered, to catch template errors.
+	Client *http.Client
+
+	// Concurrency is the maximum number of concurrent requests.
+	Concurrency int
+
+	// Timeout is the maximum duration of each request.
+	Timeout time.Duration
+}
+
+// Resource is an environment, project or release whose links were checked.
+type Resource struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+}
+
+func (resource Resource) String() string {
+	return resource.Kind + " " + resource.Name
+}
+
+// CheckFailure is either a failure to render the links of a resource, in which case Link and URL are empty, or a
+// failed request to one of its links.
+type CheckFailure struct {
+	Resource Resource `json:"resource"`
+	Link     string   `json:"link,omitempty"`
+	URL      string   `json:"url,omitempty"`
+	Status   int      `json:"status,omitempty"`
+	Error    string   `json:"error"`
+}
+
+type CheckReport struct {
+	Resources int            `json:"resources"`
+	Links     int            `json:"links"`
+	Requests  int            `json:"requests"`
+	Failures  []CheckFailure `json:"failures"`
+}
+
+type renderedLink struct {
+	resource int
+	name     string
+	url      string
+}
+
+// Check renders the links of all environments, projects and releases of catalog, and optionally requests them,
+// reporting failures in catalog order.
+func Check(ctx context.Context, opts CheckOpts) CheckReport {
+	var (
+		resources    []Resource
+		renderErrors = make(map[int]error)
+		links        []renderedLink
+	)
+
+	add := func(resource Resource, resourceLinks map[string]string, err error) {
+		resources = append(resources, resource)
+		if err != nil {
+			renderErrors[len(resources)-1] = err
+			return
+		}
+		for _, name := range getSortedLinkNames(resourceLinks) {
+			links = append(links, renderedLink{resource: len(resources) - 1, name: name, url: resourceLinks[name]})
+		}
+	}
+
+	for _, env := range opts.Catalog.Environments {
+		envLinks, err := opts.Provider.GetEnvironmentLinks(env)
+		add(Resource{Kind: "environment", Name: env.Name}, envLinks, err)
+	}
+	for _, project := range opts.Catalog.Projects {
+		projectLinks, err := opts.Provider.GetProjectLinks(project)
+		add(Resource{Kind: "project", Name: project.Name}, projectLinks, err)
+	}
+	for _, crossRelease := range opts.Catalog.Releases.Items {
+		for _, release := range crossRelease.Releases {
+			if release == nil {
+				continue
+			}
+			releaseLinks, err := opts.Provider.GetReleaseLinks(release)
+			add(Resource{Kind: "release", Name: release.Environment.Name + "/" + release.Name}, releaseLinks, err)
+		}
+	}
+
+	report := CheckReport{Resources: len(resources), Links: len(links), Failures: []CheckFailure{}}
+
+	var requested map[string]requestResult
+	if opts.Client != nil {
+		requested = requestAll(ctx, opts, links)
+		report.Requests = len(requested)
+	}
+
+	for i, resource := range resources {
+		if err, ok := renderErrors[i]; ok {
+			report.Failures = append(report.Failures, CheckFailure{Resource: resource, Error: err.Error()})
+		}
+	}
+	for _, link := range links {
+		failure := CheckFailure{Resource: resources[link.resource], Link: link.name, URL: link.url}
+		if link.url == "" {
+			failure.Error = "empty url"
+		} else if result, ok := requested[link.url]; ok && result.err != nil {
+			failure.Status = result.status
+			failure.Error = result.err.Error()
+		} else {
+			continue
+		}
+		report.Failures = append(report.Failures, failure)
+	}
+	slices.SortStableFunc(report.Failures, func(a, b CheckFailure) int {
+		return slices.Index(resources, a.Resource) - slices.Index(resources, b.Resource)
+	})
+
+	return report
+}
+
+type requestResult struct {
+	status int
+	err    error
+}
+
+// requestAll requests each distinct http(s) url of given links once, with bounded concurrency.
+func requestAll(ctx context.Context, opts CheckOpts, links []renderedLink) map[string]requestResult {
+	var urls []string
+	for _, link := range links {
+		if isHTTP(link.url) && !slices.Contains(urls, link.url) {
+			urls = append(urls, link.url)
+		}
+	}
+
+	var (
+		mu        sync.Mutex
+		wg        sync.WaitGroup
+		results   = make(map[string]requestResult, len(urls))
+		semaphore = make(chan struct{}, max(opts.Concurrency, 1))
+	)
+	for _, rawURL := range urls {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func() {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+			result := request(ctx, opts, rawURL)
+			mu.Lock()
+			results[rawURL] = result
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	return results
+}
+
+// request makes a HEAD request to given url, falling back to GET for servers that do not allow HEAD.
+func request(ctx context.Context, opts CheckOpts, rawURL string) requestResult {
+	status, err := doRequest(ctx, opts, http.MethodHead, rawURL)
+	if err == nil && status == http.StatusMethodNotAllowed {
+		status, err = doRequest(ctx, opts, http.MethodGet, rawURL)
+	}
+	if err != nil {
+		return requestResult{err: err}
+	}
+	if status >= 400 {
+		return requestResult{status: status, err: fmt.Errorf("%d %s", status, http.StatusText(status))}
+	}
+	return requestResult{status: status}
+}
+
+func doRequest(ctx context.Context, opts CheckOpts, method, rawURL string) (int, error) {
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, nil)
+	if err != nil {
+		return 0, fmt.Errorf("creating request: %w", err)
+	}
+	resp, err := opts.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+// isHTTP returns true for http(s) urls, as other links, such as those opening desktop apps, cannot be checked.
+func isHTTP(rawURL string) bool {
+	uri, err := url.Parse(rawURL)
+	return err == nil && (uri.Scheme == "http" || uri.Scheme == "https")
+}
+
+// FormatCheckReport writes the failures of given report grouped by resource, followed by a summary.
+func FormatCheckReport(w io.Writer, report CheckReport) {
+	var current *Resource
+	for _, failure := range report.Failures {
+		if current == nil || *current != failure.Resource {
+			if current != nil {
+				_, _ = fmt.Fprintln(w)
+			}
+			current = &failure.Resource
+			_, _ = fmt.Fprintf(w, "❌ %s %s\n", failure.Resource.Kind, style.Resource(failure.Resource.Name))
+		}
+		if failure.Link == "" {
+			_, _ = fmt.Fprintf(w, "   %s\n", style.Warning(failure.Error))
+			continue
+		}
+		_, _ = fmt.Fprintf(w, "   %s: %s %s\n", failure.Link, style.Link(failure.URL), style.Warning(failure.Error))
+	}
+	if len(report.Failures) > 0 {
+		_, _ = fmt.Fprintln(w)
+	}
+
+	summary := fmt.Sprintf("%d links across %d resources", report.Links, report.Resources)
+	if report.Requests > 0 {
+		summary += fmt.Sprintf(" (%d distinct urls requested)", report.Requests)
+	}
+	if len(report.Failures) == 0 {
+		_, _ = fmt.Fprintf(w, "✅ Checked %s, no failures\n", summary)
+		return
+	}
+	_, _ = fmt.Fprintf(w, "⚠️ Checked %s, %s\n", summary, style.Warning(fmt.Sprintf("%d failures", len(report.Failures))))
+}
@@ -0,0 +1,121 @@
+package links
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/acarl005/stripansi"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nestoca/joy/api/v1alpha1"
+	"github.com/nestoca/joy/pkg/catalog"
+)
+
+func TestCheck(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok":
+			w.WriteHeader(http.StatusOK)
+		case "/get-only":
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		case "/slow":
+			time.Sleep(200 * time.Millisecond)
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	cat, err := catalog.Load(context.Background(), "testdata/catalog", nil)
+	require.NoError(t, err)
+
+	provider := &ProviderMock{
+		GetEnvironmentLinksFunc: func(environment *v1alpha1.Environment) (map[string]string, error) {
+			return map[string]string{
+				"dashboard": server.URL + "/ok",
+				"slack":     "slack://channel?id=dev",
+			}, nil
+		},
+		GetProjectLinksFunc: func(project *v1alpha1.Project) (map[string]string, error) {
+			return nil, errors.New("executing template: map has no entry for key \"Repo\"")
+		},
+		GetReleaseLinksFunc: func(release *v1alpha1.Release) (map[string]string, error) {
+			if release.Name == "api" {
+				return map[string]string{
+					"dashboard": server.URL + "/ok",
+					"logs":      server.URL + "/get-only",
+					"docs":      server.URL + "/missing",
+				}, nil
+			}
+			return map[string]string{
+				"traces": "",
+				"slow":   server.URL + "/slow",
+			}, nil
+		},
+	}
+
+	t.Run("render only", func(t *testing.T) {
+		report := Check(context.Background(), CheckOpts{Catalog: cat, Provider: provider})
+
+		require.Equal(t, 4, report.Resources)
+		require.Equal(t, 7, report.Links)
+		require.Equal(t, 0, report.Requests)
+		require.Equal(t, []CheckFailure{
+			{
+				Resource: Resource{Kind: "project", Name: "my-project"},
+				Error:    "executing template: map has no entry for key \"Repo\"",
+			},
+			{
+				Resource: Resource{Kind: "release", Name: "dev/worker"},
+				Link:     "traces",
+				Error:    "empty url",
+			},
+		}, report.Failures)
+	})
+
+	t.Run("with requests", func(t *testing.T) {
+		report := Check(context.Background(), CheckOpts{
+			Catalog:     cat,
+			Provider:    provider,
+			Client:      server.Client(),
+			Concurrency: 2,
+			Timeout:     50 * time.Millisecond,
+		})
+
+		require.Equal(t, 4, report.Requests)
+		require.Len(t, report.Failures, 4)
+		require.Equal(t, CheckFailure{
+			Resource: Resource{Kind: "release", Name: "dev/api"},
+			Link:     "docs",
+			URL:      server.URL + "/missing",
+			Status:   http.StatusNotFound,
+			Error:    "404 Not Found",
+		}, report.Failures[1])
+		require.Equal(t, "slow", report.Failures[2].Link)
+		require.Contains(t, report.Failures[2].Error, "context deadline exceeded")
+		require.Equal(t, "traces", report.Failures[3].Link)
+
+		var buffer bytes.Buffer
+		FormatCheckReport(&buffer, report)
+		output := stripansi.Strip(buffer.String())
+		require.Contains(t, output, "❌ project my-project\n   executing template")
+		require.Contains(t, output, "❌ release dev/api\n   docs: "+server.URL+"/missing 404 Not Found\n")
+		require.Contains(t, output, "⚠️ Checked 7 links across 4 resources (4 distinct urls requested), 4 failures\n")
+	})
+
+	t.Run("no failures", func(t *testing.T) {
+		var buffer bytes.Buffer
+		FormatCheckReport(&buffer, CheckReport{Resources: 2, Links: 3, Failures: []CheckFailure{}})
+		require.Equal(t, "✅ Checked 3 links across 2 resources, no failures\n", stripansi.Strip(buffer.String()))
+	})
+}
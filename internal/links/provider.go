@@ -1,9 +1,11 @@
 package links
 
 import (
+	"context"
 	"fmt"
 	"maps"
 	"strings"
+	"sync"
 	"text/template"
 
 	"github.com/Masterminds/sprig/v3"
@@ -11,6 +13,8 @@ import (
 	"github.com/nestoca/joy/api/v1alpha1"
 	"github.com/nestoca/joy/internal/config"
 	"github.com/nestoca/joy/internal/info"
+	"github.com/nestoca/joy/internal/release/render"
+	"github.com/nestoca/joy/pkg/helm"
 )
 
 //go:generate moq -stub -out ./provider_mock.go . Provider
@@ -20,16 +24,20 @@ type Provider interface {
 	GetReleaseLinks(release *v1alpha1.Release) (map[string]string, error)
 }
 
-func NewProvider(infoProvider info.Provider, templates config.Templates) Provider {
+// NewProvider returns a provider rendering links from given templates. Charts are used to hydrate the values of
+// releases, exposed to release link templates as .Values.
+func NewProvider(infoProvider info.Provider, templates config.Templates, charts helm.ChartCache) Provider {
 	return &provider{
 		infoProvider: infoProvider,
 		templates:    templates,
+		charts:       charts,
 	}
 }
 
 type provider struct {
 	infoProvider info.Provider
 	templates    config.Templates
+	charts       helm.ChartCache
 }
 
 func (r *provider) GetEnvironmentLinks(environment *v1alpha1.Environment) (map[string]string, error) {
@@ -63,10 +71,16 @@ func (r *provider) GetReleaseLinks(release *v1alpha1.Release) (map[string]string
 		return nil, nil
 	}
 
+	// Hydrating values requires the release chart, which may need to be pulled, so only do it once and only when
+	// called by some template.
+	values := sync.OnceValues(func() (map[string]any, error) {
+		return r.hydrateValues(release)
+	})
+
 	templates := resolveReleaseTemplates(release, r.templates.Project.Links, r.templates.Release.Links)
 	links := make(map[string]string, len(templates))
 	for name, tmpl := range templates {
-		link, err := r.renderReleaseLink(tmpl, release)
+		link, err := r.renderReleaseLink(tmpl, release, values)
 		if err != nil {
 			return nil, fmt.Errorf("rendering release link %s %q: %w", name, tmpl, err)
 		}
@@ -93,7 +107,7 @@ func (r *provider) renderProjectLink(linkTemplate string, project *v1alpha1.Proj
 	})
 }
 
-func (r *provider) renderReleaseLink(linkTemplate string, release *v1alpha1.Release) (string, error) {
+func (r *provider) renderReleaseLink(linkTemplate string, release *v1alpha1.Release, values func() (map[string]any, error)) (string, error) {
 	if release == nil {
 		return "", nil
 	}
@@ -103,21 +117,47 @@ func (r *provider) renderReleaseLink(linkTemplate string, release *v1alpha1.Rele
 		return "", fmt.Errorf("getting release git tag: %w", err)
 	}
 
-	return renderLink(linkTemplate, struct {
-		Environment *v1alpha1.Environment
-		Project     *v1alpha1.Project
-		Release     *v1alpha1.Release
-		Repository  string
-		GitTag      string
-	}{
+	return renderLink(linkTemplate, releaseLinkData{
 		Environment: release.Environment,
 		Project:     release.Project,
 		Release:     release,
 		Repository:  r.infoProvider.GetProjectRepository(release.Project),
 		GitTag:      gitTag,
+		values:      values,
 	})
 }
 
+// releaseLinkData is the data passed to release link templates.
+type releaseLinkData struct {
+	Environment *v1alpha1.Environment
+	Project     *v1alpha1.Project
+	Release     *v1alpha1.Release
+	Repository  string
+	GitTag      string
+
+	values func() (map[string]any, error)
+}
+
+// Values returns the hydrated values of release. As hydrating values requires the release chart, it is only evaluated
+// when a template actually calls it, however it is referenced.
+func (d releaseLinkData) Values() (map[string]any, error) {
+	return d.values()
+}
+
+// hydrateValues returns the values of release as passed to its chart, including chart mappings.
+func (r *provider) hydrateValues(release *v1alpha1.Release) (map[string]any, error) {
+	// Provider is not context-aware, as links are otherwise rendered without any I/O.
+	chart, err := r.charts.GetReleaseChartFS(context.Background(), release)
+	if err != nil {
+		return nil, fmt.Errorf("getting release chart: %w", err)
+	}
+	values, err := render.HydrateValues(release, chart)
+	if err != nil {
+		return nil, fmt.Errorf("hydrating release values: %w", err)
+	}
+	return values, nil
+}
+
 func renderLink(linkTemplate string, data any) (string, error) {
 	tmpl, err := template.New("message").Funcs(sprig.FuncMap()).Parse(linkTemplate)
 	if err != nil {
@@ -1,6 +1,8 @@
 package links
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -8,6 +10,7 @@ import (
 	"github.com/nestoca/joy/api/v1alpha1"
 	"github.com/nestoca/joy/internal/config"
 	"github.com/nestoca/joy/internal/info"
+	"github.com/nestoca/joy/pkg/helm"
 )
 
 func TestGetEnvironmentLinks(t *testing.T) {
@@ -33,7 +36,7 @@ func TestGetEnvironmentLinks(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			provider := NewProvider(mockedProvider, config.Templates{
 				Environment: config.EnvironmentTemplates{Links: tc.templates},
-			})
+			}, helm.ChartCache{})
 			actual, err := provider.GetEnvironmentLinks(&v1alpha1.Environment{
 				EnvironmentMetadata: v1alpha1.EnvironmentMetadata{
 					Name: "staging",
@@ -87,7 +90,7 @@ func TestGetProjectLinks(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			provider := NewProvider(mockedProvider, config.Templates{
 				Project: config.ProjectTemplates{Links: tc.templates},
-			})
+			}, helm.ChartCache{})
 			actual, err := provider.GetProjectLinks(project)
 			require.NoError(t, err)
 			require.Equal(t, tc.expected, actual)
@@ -179,7 +182,7 @@ func TestGetReleaseLinks(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			provider := NewProvider(mockedProvider, config.Templates{
 				Release: config.ReleaseTemplates{Links: tc.templates},
-			})
+			}, helm.ChartCache{})
 			actual, err := provider.GetReleaseLinks(rel)
 			require.NoError(t, err)
 			require.Equal(t, tc.expected, actual)
@@ -187,6 +190,89 @@ func TestGetReleaseLinks(t *testing.T) {
 	}
 }
 
+func TestGetReleaseLinksWithValues(t *testing.T) {
+	chartDir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(chartDir, "chart"), 0o755))
+
+	mockedProvider := &info.ProviderMock{
+		GetProjectRepositoryFunc: func(project *v1alpha1.Project) string {
+			return "my-project-repo"
+		},
+		GetReleaseGitTagFunc: func(release *v1alpha1.Release) (string, error) {
+			return "v1.2.3", nil
+		},
+	}
+
+	rel := &v1alpha1.Release{
+		ReleaseMetadata: v1alpha1.ReleaseMetadata{
+			Name: "my-release",
+		},
+		Spec: v1alpha1.ReleaseSpec{
+			Chart: v1alpha1.ReleaseChart{
+				RepoUrl: "file://" + chartDir,
+				Name:    "chart",
+				Version: "1.0.0",
+				Mappings: map[string]any{
+					"ingress.class": "{{ .Environment.Name }}-nginx",
+				},
+			},
+			Values: map[string]any{
+				"ingress": map[string]any{
+					"host": "{{ .Release.Name }}.{{ .Environment.Name }}.example.com",
+				},
+			},
+		},
+		Environment: &v1alpha1.Environment{
+			EnvironmentMetadata: v1alpha1.EnvironmentMetadata{
+				Name: "staging",
+			},
+		},
+		Project: &v1alpha1.Project{
+			ProjectMetadata: v1alpha1.ProjectMetadata{
+				Name: "my-project",
+			},
+		},
+	}
+
+	provider := NewProvider(mockedProvider, config.Templates{
+		Release: config.ReleaseTemplates{Links: map[string]string{
+			"site":    "https://{{ .Values.ingress.host }}",
+			"ingress": "https://console.example.com/{{ .Values.ingress.class }}",
+			"index":   "https://{{ index .Values \"ingress\" \"host\" }}",
+			"root":    "{{ with .Release }}https://{{ $.Values.ingress.host }}{{ end }}",
+			"repo":    "https://github.com/{{ .Repository }}",
+		}},
+	}, helm.ChartCache{})
+
+	actual, err := provider.GetReleaseLinks(rel)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"site":    "https://my-release.staging.example.com",
+		"ingress": "https://console.example.com/staging-nginx",
+		"index":   "https://my-release.staging.example.com",
+		"root":    "https://my-release.staging.example.com",
+		"repo":    "https://github.com/my-project-repo",
+	}, actual)
+
+	t.Run("values are only hydrated when referenced", func(t *testing.T) {
+		invalid := *rel
+		invalid.Spec.Values = map[string]any{"host": "{{ .Unknown.Host }}"}
+
+		provider := NewProvider(mockedProvider, config.Templates{
+			Release: config.ReleaseTemplates{Links: map[string]string{"repo": "https://github.com/{{ .Repository }}"}},
+		}, helm.ChartCache{})
+		actual, err := provider.GetReleaseLinks(&invalid)
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"repo": "https://github.com/my-project-repo"}, actual)
+
+		provider = NewProvider(mockedProvider, config.Templates{
+			Release: config.ReleaseTemplates{Links: map[string]string{"site": "https://{{ .Values.ingress.host }}"}},
+		}, helm.ChartCache{})
+		_, err = provider.GetReleaseLinks(&invalid)
+		require.ErrorContains(t, err, "hydrating release values")
+	})
+}
+
 func TestResolveProjectTemplates(t *testing.T) {
 	cases := []struct {
 		name                    string
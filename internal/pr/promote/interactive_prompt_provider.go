@@ -3,7 +3,9 @@ package promote
 import (
 	"fmt"
 	"io"
+	"strings"
 
+	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/nestoca/survey/v2"
 
 	"github.com/nestoca/joy/internal/style"
@@ -61,6 +63,23 @@ func (s *InteractivePromptProvider) ConfirmDisablingPromotionOnOtherPullRequest(
 	return shouldDisable, nil
 }
 
+func (s *InteractivePromptProvider) ConfirmDisablingAllPromotions(branches []string, env string) (bool, error) {
+	s.printf("Branches auto-promoting to %s environment:\n", style.Resource(env))
+	for _, branch := range branches {
+		s.printf("- %s\n", style.Resource(branch))
+	}
+	prompt := &survey.Confirm{
+		Message: fmt.Sprintf("Disable auto-promotion of those %d pull request(s)?", len(branches)),
+		Default: false,
+	}
+	var shouldDisable bool
+	err := survey.AskOne(prompt, &shouldDisable)
+	if err != nil {
+		return false, fmt.Errorf("prompting user to confirm disabling all promotions: %w", err)
+	}
+	return shouldDisable, nil
+}
+
 func (s *InteractivePromptProvider) PrintBranchDoesNotSupportAutoPromotion(branch string) {
 	s.printf("🚫 Cannot auto-promote builds of %s branch, please checkout another branch and try again.\n", style.Resource(branch))
 }
@@ -85,6 +104,28 @@ func (s *InteractivePromptProvider) PrintPromotionDisabled(branch string) {
 	s.printf("🛑 Disabled auto-promotion of branch %s pull request.\n", style.Resource(branch))
 }
 
+func (s *InteractivePromptProvider) PrintNoBranchesPromotingToEnvironment(env string) {
+	s.printf("🤷 No pull requests are auto-promoting to %s environment.\n", style.Resource(env))
+}
+
+func (s *InteractivePromptProvider) PrintPromotions(promotions []EnvironmentPromotions) {
+	t := table.NewWriter()
+	t.SetStyle(table.StyleRounded)
+	t.AppendHeader(table.Row{"ENVIRONMENT", "BRANCHES"})
+	for _, promotion := range promotions {
+		branches := style.SecondaryInfo("-")
+		if len(promotion.Branches) > 0 {
+			var names []string
+			for _, branch := range promotion.Branches {
+				names = append(names, style.Resource(branch))
+			}
+			branches = strings.Join(names, "\n")
+		}
+		t.AppendRow(table.Row{style.Resource(promotion.Environment), branches})
+	}
+	s.println(t.Render())
+}
+
 func (s *InteractivePromptProvider) printf(format string, args ...any) {
 	_, _ = fmt.Fprintf(s.out, format, args...)
 }
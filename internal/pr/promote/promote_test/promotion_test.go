@@ -485,3 +485,113 @@ func TestPromotion(t *testing.T) {
 		})
 	}
 }
+
+func TestDisableAllPromotions(t *testing.T) {
+	cases := []struct {
+		name            string
+		noPrompt        bool
+		branches        []string
+		confirm         bool
+		expectedDisable []string
+		expectedConfirm bool
+		expectedNoneMsg bool
+	}{
+		{
+			name:            "no pull requests promoting to environment",
+			expectedNoneMsg: true,
+		},
+		{
+			name:            "user confirms disabling all",
+			branches:        []string{"branch1", "branch2"},
+			confirm:         true,
+			expectedConfirm: true,
+			expectedDisable: []string{"branch1", "branch2"},
+		},
+		{
+			name:            "user opts out of disabling all",
+			branches:        []string{"branch1", "branch2"},
+			expectedConfirm: true,
+		},
+		{
+			name:            "no prompt",
+			noPrompt:        true,
+			branches:        []string{"branch1"},
+			expectedDisable: []string{"branch1"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			branchProvider := new(promote.BranchProviderMock)
+			prProvider := &pr.PullRequestProviderMock{
+				GetBranchesPromotingToEnvironmentFunc: func(env string) ([]string, error) {
+					return c.branches, nil
+				},
+			}
+			prompt := &promote.PromptProviderMock{
+				ConfirmDisablingAllPromotionsFunc: func(branches []string, env string) (bool, error) {
+					return c.confirm, nil
+				},
+			}
+
+			promotion := promote.NewPromotion(branchProvider, prProvider, prompt)
+			err := promotion.Promote(promote.Params{
+				Environments: newEnvironments(t),
+				TargetEnv:    "staging",
+				DisableAll:   true,
+				NoPrompt:     c.noPrompt,
+			})
+			require.NoError(t, err)
+
+			require.Empty(t, branchProvider.GetCurrentBranchCalls())
+			require.Len(t, prProvider.GetBranchesPromotingToEnvironmentCalls(), 1)
+			require.Equal(t, "staging", prProvider.GetBranchesPromotingToEnvironmentCalls()[0].Env)
+
+			if c.expectedNoneMsg {
+				require.Len(t, prompt.PrintNoBranchesPromotingToEnvironmentCalls(), 1)
+				require.Equal(t, "staging", prompt.PrintNoBranchesPromotingToEnvironmentCalls()[0].Env)
+			}
+
+			if c.expectedConfirm {
+				require.Len(t, prompt.ConfirmDisablingAllPromotionsCalls(), 1)
+				require.Equal(t, c.branches, prompt.ConfirmDisablingAllPromotionsCalls()[0].Branches)
+			} else {
+				require.Empty(t, prompt.ConfirmDisablingAllPromotionsCalls())
+			}
+
+			var disabled []string
+			for _, call := range prProvider.SetPromotionEnvironmentCalls() {
+				require.Empty(t, call.Env)
+				disabled = append(disabled, call.Branch)
+			}
+			require.Equal(t, c.expectedDisable, disabled)
+			require.Len(t, prompt.PrintPromotionDisabledCalls(), len(c.expectedDisable))
+		})
+	}
+
+	t.Run("target environment required", func(t *testing.T) {
+		promotion := promote.NewPromotion(new(promote.BranchProviderMock), new(pr.PullRequestProviderMock), new(promote.PromptProviderMock))
+		err := promotion.Promote(promote.Params{DisableAll: true})
+		require.EqualError(t, err, "target environment is required to disable all auto-promotions")
+	})
+}
+
+func TestListPromotions(t *testing.T) {
+	prProvider := &pr.PullRequestProviderMock{
+		GetBranchesPromotingToEnvironmentFunc: func(env string) ([]string, error) {
+			if env == "staging" {
+				return []string{"branch1", "branch2"}, nil
+			}
+			return nil, nil
+		},
+	}
+	prompt := new(promote.PromptProviderMock)
+
+	promotion := promote.NewPromotion(new(promote.BranchProviderMock), prProvider, prompt)
+	require.NoError(t, promotion.List(newEnvironments(t)))
+
+	require.Len(t, prompt.PrintPromotionsCalls(), 1)
+	require.Equal(t, []promote.EnvironmentPromotions{
+		{Environment: "staging", Branches: []string{"branch1", "branch2"}},
+		{Environment: "demo"},
+	}, prompt.PrintPromotionsCalls()[0].Promotions)
+}
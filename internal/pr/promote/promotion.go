@@ -43,9 +43,16 @@ type Params struct {
 	Environments []*v1alpha1.Environment
 	TargetEnv    string
 	Disable      bool
+	DisableAll   bool
 	NoPrompt     bool
 }
 
+// EnvironmentPromotions lists the branches whose pull requests auto-promote their builds to a given environment.
+type EnvironmentPromotions struct {
+	Environment string
+	Branches    []string
+}
+
 // Promote prompts user to create a pull request for current branch and to select environment to auto-promote builds
 // of pull request to, and then configures the pull request accordingly.
 func (p *Promotion) Promote(params Params) error {
@@ -53,6 +60,10 @@ func (p *Promotion) Promote(params Params) error {
 		return nil
 	}
 
+	if params.DisableAll {
+		return p.disableAll(params)
+	}
+
 	branch, err := p.branchProvider.GetCurrentBranch()
 	if err != nil {
 		return fmt.Errorf("getting current branch: %w", err)
@@ -155,6 +166,60 @@ func (p *Promotion) Promote(params Params) error {
 	return nil
 }
 
+// disableAll disables auto-promotion of all pull requests promoting to the target environment, typically to free up
+// a shared environment.
+func (p *Promotion) disableAll(params Params) error {
+	if params.TargetEnv == "" {
+		return fmt.Errorf("target environment is required to disable all auto-promotions")
+	}
+
+	branches, err := p.pullRequestProvider.GetBranchesPromotingToEnvironment(params.TargetEnv)
+	if err != nil {
+		return fmt.Errorf("getting branches configured for auto-promotion to %q environment: %w", params.TargetEnv, err)
+	}
+	if len(branches) == 0 {
+		p.promptProvider.PrintNoBranchesPromotingToEnvironment(params.TargetEnv)
+		return nil
+	}
+
+	if !params.NoPrompt {
+		shouldDisable, err := p.promptProvider.ConfirmDisablingAllPromotions(branches, params.TargetEnv)
+		if err != nil {
+			return fmt.Errorf("prompting user to confirm disabling all promotions: %w", err)
+		}
+		if !shouldDisable {
+			return nil
+		}
+	}
+
+	for _, branch := range branches {
+		if err := p.pullRequestProvider.SetPromotionEnvironment(branch, ""); err != nil {
+			return fmt.Errorf("disabling promotion for branch %s pull request: %w", branch, err)
+		}
+		p.promptProvider.PrintPromotionDisabled(branch)
+	}
+	return nil
+}
+
+// List prints the branches whose pull requests are auto-promoting, grouped by the environment they promote to.
+func (p *Promotion) List(environments []*v1alpha1.Environment) error {
+	if err := p.pullRequestProvider.EnsureInstalledAndAuthenticated(); err != nil {
+		return err
+	}
+
+	var promotions []EnvironmentPromotions
+	for _, env := range getPromotableEnvironmentNames(environments) {
+		branches, err := p.pullRequestProvider.GetBranchesPromotingToEnvironment(env)
+		if err != nil {
+			return fmt.Errorf("getting branches configured for auto-promotion to %q environment: %w", env, err)
+		}
+		promotions = append(promotions, EnvironmentPromotions{Environment: env, Branches: branches})
+	}
+
+	p.promptProvider.PrintPromotions(promotions)
+	return nil
+}
+
 func getPromotableEnvironmentNames(environments []*v1alpha1.Environment) []string {
 	var names []string
 	for _, env := range environments {
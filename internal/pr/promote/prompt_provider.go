@@ -13,6 +13,10 @@ type PromptProvider interface {
 	// pull request having same target environment.
 	ConfirmDisablingPromotionOnOtherPullRequest(branch, env string) (bool, error)
 
+	// ConfirmDisablingAllPromotions prompts user to confirm it is ok to disable auto-promotion to given environment
+	// of the pull requests of all given branches.
+	ConfirmDisablingAllPromotions(branches []string, env string) (bool, error)
+
 	// PrintBranchDoesNotSupportAutoPromotion prints message that master/main branch cannot be promoted.
 	PrintBranchDoesNotSupportAutoPromotion(branch string)
 
@@ -31,4 +35,11 @@ type PromptProvider interface {
 
 	// PrintPromotionDisabled prints message that promotion was disabled.
 	PrintPromotionDisabled(branch string)
+
+	// PrintNoBranchesPromotingToEnvironment prints message that no pull requests are auto-promoting to given
+	// environment.
+	PrintNoBranchesPromotingToEnvironment(env string)
+
+	// PrintPromotions prints the branches auto-promoting to each environment.
+	PrintPromotions(promotions []EnvironmentPromotions)
 }
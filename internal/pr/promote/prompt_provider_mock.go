@@ -17,12 +17,18 @@ var _ PromptProvider = &PromptProviderMock{}
 //
 //		// make and configure a mocked PromptProvider
 //		mockedPromptProvider := &PromptProviderMock{
+//			ConfirmDisablingAllPromotionsFunc: func(branches []string, env string) (bool, error) {
+//				panic("mock out the ConfirmDisablingAllPromotions method")
+//			},
 //			ConfirmDisablingPromotionOnOtherPullRequestFunc: func(branch string, env string) (bool, error) {
 //				panic("mock out the ConfirmDisablingPromotionOnOtherPullRequest method")
 //			},
 //			PrintBranchDoesNotSupportAutoPromotionFunc: func(branch string)  {
 //				panic("mock out the PrintBranchDoesNotSupportAutoPromotion method")
 //			},
+//			PrintNoBranchesPromotingToEnvironmentFunc: func(env string)  {
+//				panic("mock out the PrintNoBranchesPromotingToEnvironment method")
+//			},
 //			PrintNotCreatingPullRequestFunc: func()  {
 //				panic("mock out the PrintNotCreatingPullRequest method")
 //			},
@@ -38,6 +44,9 @@ var _ PromptProvider = &PromptProviderMock{}
 //			PrintPromotionNotConfiguredFunc: func(branch string, env string)  {
 //				panic("mock out the PrintPromotionNotConfigured method")
 //			},
+//			PrintPromotionsFunc: func(promotions []EnvironmentPromotions)  {
+//				panic("mock out the PrintPromotions method")
+//			},
 //			WhetherToCreateMissingPullRequestFunc: func() (bool, error) {
 //				panic("mock out the WhetherToCreateMissingPullRequest method")
 //			},
@@ -51,12 +60,18 @@ var _ PromptProvider = &PromptProviderMock{}
 //
 //	}
 type PromptProviderMock struct {
+	// ConfirmDisablingAllPromotionsFunc mocks the ConfirmDisablingAllPromotions method.
+	ConfirmDisablingAllPromotionsFunc func(branches []string, env string) (bool, error)
+
 	// ConfirmDisablingPromotionOnOtherPullRequestFunc mocks the ConfirmDisablingPromotionOnOtherPullRequest method.
 	ConfirmDisablingPromotionOnOtherPullRequestFunc func(branch string, env string) (bool, error)
 
 	// PrintBranchDoesNotSupportAutoPromotionFunc mocks the PrintBranchDoesNotSupportAutoPromotion method.
 	PrintBranchDoesNotSupportAutoPromotionFunc func(branch string)
 
+	// PrintNoBranchesPromotingToEnvironmentFunc mocks the PrintNoBranchesPromotingToEnvironment method.
+	PrintNoBranchesPromotingToEnvironmentFunc func(env string)
+
 	// PrintNotCreatingPullRequestFunc mocks the PrintNotCreatingPullRequest method.
 	PrintNotCreatingPullRequestFunc func()
 
@@ -72,6 +87,9 @@ type PromptProviderMock struct {
 	// PrintPromotionNotConfiguredFunc mocks the PrintPromotionNotConfigured method.
 	PrintPromotionNotConfiguredFunc func(branch string, env string)
 
+	// PrintPromotionsFunc mocks the PrintPromotions method.
+	PrintPromotionsFunc func(promotions []EnvironmentPromotions)
+
 	// WhetherToCreateMissingPullRequestFunc mocks the WhetherToCreateMissingPullRequest method.
 	WhetherToCreateMissingPullRequestFunc func() (bool, error)
 
@@ -80,6 +98,13 @@ type PromptProviderMock struct {
 
 	// calls tracks calls to the methods.
 	calls struct {
+		// ConfirmDisablingAllPromotions holds details about calls to the ConfirmDisablingAllPromotions method.
+		ConfirmDisablingAllPromotions []struct {
+			// Branches is the branches argument value.
+			Branches []string
+			// Env is the env argument value.
+			Env string
+		}
 		// ConfirmDisablingPromotionOnOtherPullRequest holds details about calls to the ConfirmDisablingPromotionOnOtherPullRequest method.
 		ConfirmDisablingPromotionOnOtherPullRequest []struct {
 			// Branch is the branch argument value.
@@ -92,6 +117,11 @@ type PromptProviderMock struct {
 			// Branch is the branch argument value.
 			Branch string
 		}
+		// PrintNoBranchesPromotingToEnvironment holds details about calls to the PrintNoBranchesPromotingToEnvironment method.
+		PrintNoBranchesPromotingToEnvironment []struct {
+			// Env is the env argument value.
+			Env string
+		}
 		// PrintNotCreatingPullRequest holds details about calls to the PrintNotCreatingPullRequest method.
 		PrintNotCreatingPullRequest []struct {
 		}
@@ -121,6 +151,11 @@ type PromptProviderMock struct {
 			// Env is the env argument value.
 			Env string
 		}
+		// PrintPromotions holds details about calls to the PrintPromotions method.
+		PrintPromotions []struct {
+			// Promotions is the promotions argument value.
+			Promotions []EnvironmentPromotions
+		}
 		// WhetherToCreateMissingPullRequest holds details about calls to the WhetherToCreateMissingPullRequest method.
 		WhetherToCreateMissingPullRequest []struct {
 		}
@@ -132,17 +167,60 @@ type PromptProviderMock struct {
 			PreSelectedEnv string
 		}
 	}
+	lockConfirmDisablingAllPromotions               sync.RWMutex
 	lockConfirmDisablingPromotionOnOtherPullRequest sync.RWMutex
 	lockPrintBranchDoesNotSupportAutoPromotion      sync.RWMutex
+	lockPrintNoBranchesPromotingToEnvironment       sync.RWMutex
 	lockPrintNotCreatingPullRequest                 sync.RWMutex
 	lockPrintPromotionAlreadyConfigured             sync.RWMutex
 	lockPrintPromotionConfigured                    sync.RWMutex
 	lockPrintPromotionDisabled                      sync.RWMutex
 	lockPrintPromotionNotConfigured                 sync.RWMutex
+	lockPrintPromotions                             sync.RWMutex
 	lockWhetherToCreateMissingPullRequest           sync.RWMutex
 	lockWhichEnvironmentToPromoteTo                 sync.RWMutex
 }
 
+// ConfirmDisablingAllPromotions calls ConfirmDisablingAllPromotionsFunc.
+func (mock *PromptProviderMock) ConfirmDisablingAllPromotions(branches []string, env string) (bool, error) {
+	callInfo := struct {
+		Branches []string
+		Env      string
+	}{
+		Branches: branches,
+		Env:      env,
+	}
+	mock.lockConfirmDisablingAllPromotions.Lock()
+	mock.calls.ConfirmDisablingAllPromotions = append(mock.calls.ConfirmDisablingAllPromotions, callInfo)
+	mock.lockConfirmDisablingAllPromotions.Unlock()
+	if mock.ConfirmDisablingAllPromotionsFunc == nil {
+		var (
+			bOut   bool
+			errOut error
+		)
+		return bOut, errOut
+	}
+	return mock.ConfirmDisablingAllPromotionsFunc(branches, env)
+}
+
+// ConfirmDisablingAllPromotionsCalls gets all the calls that were made to ConfirmDisablingAllPromotions.
+// Check the length with:
+//
+//	len(mockedPromptProvider.ConfirmDisablingAllPromotionsCalls())
+func (mock *PromptProviderMock) ConfirmDisablingAllPromotionsCalls() []struct {
+	Branches []string
+	Env      string
+} {
+	var calls []struct {
+		Branches []string
+		Env      string
+	}
+	mock.lockConfirmDisablingAllPromotions.RLock()
+	calls = mock.calls.ConfirmDisablingAllPromotions
+	mock.lockConfirmDisablingAllPromotions.RUnlock()
+	return calls
+}
+
 // ConfirmDisablingPromotionOnOtherPullRequest calls ConfirmDisablingPromotionOnOtherPullRequestFunc.
 func (mock *PromptProviderMock) ConfirmDisablingPromotionOnOtherPullRequest(branch string, env string) (bool, error) {
 	callInfo := struct {
@@ -215,6 +293,38 @@ func (mock *PromptProviderMock) PrintBranchDoesNotSupportAutoPromotionCalls() []
 	return calls
 }
 
+// PrintNoBranchesPromotingToEnvironment calls PrintNoBranchesPromotingToEnvironmentFunc.
+func (mock *PromptProviderMock) PrintNoBranchesPromotingToEnvironment(env string) {
+	callInfo := struct {
+		Env string
+	}{
+		Env: env,
+	}
+	mock.lockPrintNoBranchesPromotingToEnvironment.Lock()
+	mock.calls.PrintNoBranchesPromotingToEnvironment = append(mock.calls.PrintNoBranchesPromotingToEnvironment, callInfo)
+	mock.lockPrintNoBranchesPromotingToEnvironment.Unlock()
+	if mock.PrintNoBranchesPromotingToEnvironmentFunc == nil {
+		return
+	}
+	mock.PrintNoBranchesPromotingToEnvironmentFunc(env)
+}
+
+// PrintNoBranchesPromotingToEnvironmentCalls gets all the calls that were made to PrintNoBranchesPromotingToEnvironment.
+// Check the length with:
+//
+//	len(mockedPromptProvider.PrintNoBranchesPromotingToEnvironmentCalls())
+func (mock *PromptProviderMock) PrintNoBranchesPromotingToEnvironmentCalls() []struct {
+	Env string
+} {
+	var calls []struct {
+		Env string
+	}
+	mock.lockPrintNoBranchesPromotingToEnvironment.RLock()
+	calls = mock.calls.PrintNoBranchesPromotingToEnvironment
+	mock.lockPrintNoBranchesPromotingToEnvironment.RUnlock()
+	return calls
+}
+
 // PrintNotCreatingPullRequest calls PrintNotCreatingPullRequestFunc.
 func (mock *PromptProviderMock) PrintNotCreatingPullRequest() {
 	callInfo := struct {
@@ -382,6 +492,38 @@ func (mock *PromptProviderMock) PrintPromotionNotConfiguredCalls() []struct {
 	return calls
 }
 
+// PrintPromotions calls PrintPromotionsFunc.
+func (mock *PromptProviderMock) PrintPromotions(promotions []EnvironmentPromotions) {
+	callInfo := struct {
+		Promotions []EnvironmentPromotions
+	}{
+		Promotions: promotions,
+	}
+	mock.lockPrintPromotions.Lock()
+	mock.calls.PrintPromotions = append(mock.calls.PrintPromotions, callInfo)
+	mock.lockPrintPromotions.Unlock()
+	if mock.PrintPromotionsFunc == nil {
+		return
+	}
+	mock.PrintPromotionsFunc(promotions)
+}
+
+// PrintPromotionsCalls gets all the calls that were made to PrintPromotions.
+// Check the length with:
+//
+//	len(mockedPromptProvider.PrintPromotionsCalls())
+func (mock *PromptProviderMock) PrintPromotionsCalls() []struct {
+	Promotions []EnvironmentPromotions
+} {
+	var calls []struct {
+		Promotions []EnvironmentPromotions
+	}
+	mock.lockPrintPromotions.RLock()
+	calls = mock.calls.PrintPromotions
+	mock.lockPrintPromotions.RUnlock()
+	return calls
+}
+
 // WhetherToCreateMissingPullRequest calls WhetherToCreateMissingPullRequestFunc.
 func (mock *PromptProviderMock) WhetherToCreateMissingPullRequest() (bool, error) {
 	callInfo := struct {
@@ -0,0 +1,335 @@
+package status
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+
+	"github.com/nestoca/joy/internal/git/pr"
+	"github.com/nestoca/joy/internal/style"
+)
+
+type Params struct {
+	// Numbers of the pull requests to report on. When empty, all open release promotion pull requests are reported.
+	Numbers []int
+
+	// Environment restricts the reported pull requests to those promoting to given environment.
+	Environment string
+
+	// Releases restricts the reported pull requests to those promoting all given releases.
+	Releases []string
+
+	// Wait blocks until all pull requests are either merged, closed or failing their checks.
+	Wait bool
+
+	// Interval between polls when waiting.
+	Interval time.Duration
+
+	// Timeout after which to give up waiting. Zero means no timeout.
+	Timeout time.Duration
+
+	// JSON outputs the final statuses as JSON instead of a table.
+	JSON bool
+}
+
+// PullRequestStatus is the status of a release promotion pull request, along with the environment and releases
+// it promotes, as determined by its labels.
+type PullRequestStatus struct {
+	pr.Status
+	Environment string   `json:"environment,omitempty"`
+	Releases    []string `json:"releases,omitempty"`
+}
+
+type Tracker struct {
+	provider pr.PullRequestProvider
+	out      io.Writer
+
+	// sleep and now are overridable for testing purposes
+	sleep func(time.Duration)
+	now   func() time.Time
+}
+
+func NewTracker(provider pr.PullRequestProvider, out io.Writer) *Tracker {
+	return &Tracker{
+		provider: provider,
+		out:      out,
+		sleep:    time.Sleep,
+		now:      time.Now,
+	}
+}
+
+// Run reports the status of release promotion pull requests and optionally waits for them to land, returning an
+// error if any of them was closed without being merged or has failing checks.
+func (t *Tracker) Run(params Params) error {
+	if err := t.provider.EnsureInstalledAndAuthenticated(); err != nil {
+		return err
+	}
+
+	numbers := params.Numbers
+	if len(numbers) == 0 {
+		var err error
+		numbers, err = t.listPromotionPullRequests(params.Environment, params.Releases)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(numbers) == 0 {
+		if params.JSON {
+			_, _ = fmt.Fprintln(t.out, "[]")
+			return nil
+		}
+		_, _ = fmt.Fprintln(t.out, "🤷 No open promotion pull requests found.")
+		return nil
+	}
+
+	statuses, err := t.getStatuses(numbers)
+	if err != nil {
+		return err
+	}
+
+	if params.Wait {
+		statuses, err = t.wait(statuses, params)
+		if err != nil {
+			return err
+		}
+	}
+
+	if params.JSON {
+		data, err := json.MarshalIndent(statuses, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshalling statuses: %w", err)
+		}
+		_, _ = fmt.Fprintln(t.out, string(data))
+	} else {
+		_, _ = fmt.Fprint(t.out, FormatStatuses(statuses))
+	}
+
+	if params.Wait {
+		return getFailure(statuses)
+	}
+	return nil
+}
+
+func (t *Tracker) listPromotionPullRequests(env string, releases []string) ([]int, error) {
+	var labels []string
+	if env != "" {
+		labels = append(labels, pr.EnvironmentLabel(env))
+	}
+	for _, release := range releases {
+		labels = append(labels, pr.ReleaseLabel(release))
+	}
+
+	pullRequests, err := t.provider.ListOpen(labels...)
+	if err != nil {
+		return nil, fmt.Errorf("listing open pull requests: %w", err)
+	}
+
+	var numbers []int
+	for _, pullRequest := range pullRequests {
+		if slices.ContainsFunc(pullRequest.Labels, isEnvironmentLabel) {
+			numbers = append(numbers, pullRequest.Number)
+		}
+	}
+	return numbers, nil
+}
+
+func (t *Tracker) getStatuses(numbers []int) ([]PullRequestStatus, error) {
+	statuses := make([]PullRequestStatus, len(numbers))
+	for i, number := range numbers {
+		status, err := t.provider.GetStatus(number)
+		if err != nil {
+			return nil, fmt.Errorf("getting status of pull request #%d: %w", number, err)
+		}
+		statuses[i] = newPullRequestStatus(*status)
+	}
+	return statuses, nil
+}
+
+// wait polls given pull requests until they all reach a final state, reporting their changes along the way.
+func (t *Tracker) wait(statuses []PullRequestStatus, params Params) ([]PullRequestStatus, error) {
+	if !params.JSON {
+		_, _ = fmt.Fprint(t.out, FormatStatuses(statuses))
+	}
+
+	start := t.now()
+	for {
+		pending := getPending(statuses)
+		if len(pending) == 0 {
+			return statuses, nil
+		}
+
+		if params.Timeout > 0 && t.now().Sub(start) >= params.Timeout {
+			return nil, fmt.Errorf("timed out after %s waiting for pull requests %s", params.Timeout, formatNumbers(pending))
+		}
+
+		if !params.JSON {
+			_, _ = fmt.Fprintf(t.out, "⏳ Waiting for pull requests %s...\n", formatNumbers(pending))
+		}
+		t.sleep(params.Interval)
+
+		for i, previous := range statuses {
+			if previous.IsFinal() {
+				continue
+			}
+			status, err := t.provider.GetStatus(previous.Number)
+			if err != nil {
+				return nil, fmt.Errorf("getting status of pull request #%d: %w", previous.Number, err)
+			}
+			statuses[i] = newPullRequestStatus(*status)
+			if !params.JSON {
+				for _, change := range getChanges(previous, statuses[i]) {
+					_, _ = fmt.Fprintf(t.out, "🔄 #%d %s\n", previous.Number, change)
+				}
+			}
+		}
+	}
+}
+
+func newPullRequestStatus(status pr.Status) PullRequestStatus {
+	result := PullRequestStatus{Status: status}
+	for _, label := range status.Labels {
+		if env, ok := pr.ParseEnvironmentLabel(label); ok {
+			result.Environment = env
+		}
+		if release, ok := pr.ParseReleaseLabel(label); ok {
+			result.Releases = append(result.Releases, release)
+		}
+	}
+	return result
+}
+
+func getChanges(previous, current PullRequestStatus) []string {
+	var changes []string
+	if previous.State != current.State {
+		changes = append(changes, fmt.Sprintf("state: %s → %s", previous.State, formatState(current.State)))
+	}
+	if previous.Checks != current.Checks {
+		changes = append(changes, fmt.Sprintf("checks: %s → %s", previous.Checks, formatChecks(current.Checks)))
+	}
+	if previous.Review != current.Review {
+		changes = append(changes, fmt.Sprintf("review: %s → %s", previous.Review, formatReview(current.Review)))
+	}
+	if previous.AutoMerge != current.AutoMerge {
+		changes = append(changes, fmt.Sprintf("auto-merge: %s → %s", formatAutoMerge(previous.AutoMerge), formatAutoMerge(current.AutoMerge)))
+	}
+	return changes
+}
+
+func getPending(statuses []PullRequestStatus) []int {
+	var numbers []int
+	for _, status := range statuses {
+		if !status.IsFinal() {
+			numbers = append(numbers, status.Number)
+		}
+	}
+	return numbers
+}
+
+func getFailure(statuses []PullRequestStatus) error {
+	var errs []error
+	for _, status := range statuses {
+		switch {
+		case status.State == pr.StateClosed:
+			errs = append(errs, fmt.Errorf("pull request #%d was closed without being merged", status.Number))
+		case status.IsFailed():
+			errs = append(errs, fmt.Errorf("pull request #%d has failing checks", status.Number))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// FormatStatuses renders given statuses as a table.
+func FormatStatuses(statuses []PullRequestStatus) string {
+	t := table.NewWriter()
+	t.SetStyle(table.StyleRounded)
+
+	t.AppendHeader(table.Row{"PULL REQUEST", "ENVIRONMENT", "RELEASES", "STATE", "CHECKS", "REVIEW", "AUTO-MERGE"})
+	for _, status := range statuses {
+		t.AppendRow(table.Row{
+			style.Link(status.URL),
+			style.Resource(status.Environment),
+			strings.Join(status.Releases, ", "),
+			formatState(status.State),
+			formatChecks(status.Checks),
+			formatReview(status.Review),
+			formatAutoMerge(status.AutoMerge),
+		})
+	}
+
+	return t.Render() + "\n"
+}
+
+func formatState(state pr.State) string {
+	switch state {
+	case pr.StateMerged:
+		return style.OK(state)
+	case pr.StateClosed:
+		return style.Warning(state)
+	default:
+		return string(state)
+	}
+}
+
+func formatChecks(checks pr.ChecksState) string {
+	switch checks {
+	case pr.ChecksSuccess:
+		return style.OK(checks)
+	case pr.ChecksFailure:
+		return style.Warning(checks)
+	default:
+		return style.SecondaryInfo(checks)
+	}
+}
+
+func formatReview(review pr.ReviewState) string {
+	switch review {
+	case pr.ReviewApproved:
+		return style.OK(review)
+	case pr.ReviewChangesRequested:
+		return style.Warning(review)
+	default:
+		return style.SecondaryInfo(review)
+	}
+}
+
+func formatAutoMerge(autoMerge bool) string {
+	if autoMerge {
+		return "enabled"
+	}
+	return style.SecondaryInfo("disabled")
+}
+
+func formatNumbers(numbers []int) string {
+	var values []string
+	for _, number := range numbers {
+		values = append(values, "#"+strconv.Itoa(number))
+	}
+	return strings.Join(values, ", ")
+}
+
+func isEnvironmentLabel(label string) bool {
+	_, ok := pr.ParseEnvironmentLabel(label)
+	return ok
+}
+
+// ParseNumber parses a pull request number from either a plain number, optionally prefixed with # or !,
+// or a pull request URL ending with the number.
+func ParseNumber(value string) (int, error) {
+	last := strings.TrimSuffix(value, "/")
+	if index := strings.LastIndex(last, "/"); index >= 0 {
+		last = last[index+1:]
+	}
+	number, err := strconv.Atoi(strings.TrimLeft(last, "#!"))
+	if err != nil || number <= 0 {
+		return 0, fmt.Errorf("invalid pull request number or url: %s", value)
+	}
+	return number, nil
+}
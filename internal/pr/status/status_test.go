@@ -0,0 +1,182 @@
+package status
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/nestoca/joy/internal/git/pr"
+)
+
+func TestRun(t *testing.T) {
+	newStatus := func(number int, state pr.State, checks pr.ChecksState, labels ...string) *pr.Status {
+		return &pr.Status{
+			PullRequest: pr.PullRequest{Number: number, URL: fmt.Sprintf("https://example.com/pull/%d", number), Labels: labels},
+			State:       state,
+			Checks:      checks,
+			Review:      pr.ReviewRequired,
+		}
+	}
+
+	cases := []struct {
+		Name string
+
+		Params   Params
+		OpenPRs  []pr.PullRequest
+		Statuses map[int][]*pr.Status
+
+		ExpectedLabels   []string
+		ExpectedStatuses []PullRequestStatus
+		ExpectedError    string
+		ExpectedOutput   []string
+	}{
+		{
+			Name:   "list open promotion pull requests only",
+			Params: Params{Environment: "prod", Releases: []string{"release1"}, JSON: true},
+			OpenPRs: []pr.PullRequest{
+				{Number: 1, Labels: []string{"environment:prod", "release:release1"}},
+				{Number: 2, Labels: []string{"release:release1"}},
+			},
+			Statuses: map[int][]*pr.Status{
+				1: {newStatus(1, pr.StateOpen, pr.ChecksPending, "environment:prod", "release:release1", "release:release2")},
+			},
+			ExpectedLabels: []string{"environment:prod", "release:release1"},
+			ExpectedStatuses: []PullRequestStatus{
+				{
+					Status:      *newStatus(1, pr.StateOpen, pr.ChecksPending, "environment:prod", "release:release1", "release:release2"),
+					Environment: "prod",
+					Releases:    []string{"release1", "release2"},
+				},
+			},
+		},
+		{
+			Name:           "no open promotion pull requests",
+			Params:         Params{},
+			ExpectedOutput: []string{"No open promotion pull requests found"},
+		},
+		{
+			Name:   "wait until merged",
+			Params: Params{Numbers: []int{3}, Wait: true, Interval: time.Second},
+			Statuses: map[int][]*pr.Status{
+				3: {
+					newStatus(3, pr.StateOpen, pr.ChecksPending, "environment:staging"),
+					newStatus(3, pr.StateOpen, pr.ChecksSuccess, "environment:staging"),
+					newStatus(3, pr.StateMerged, pr.ChecksSuccess, "environment:staging"),
+				},
+			},
+			ExpectedOutput: []string{"Waiting for pull requests #3", "#3 checks: pending → ", "#3 state: open → "},
+		},
+		{
+			Name:   "wait until checks fail",
+			Params: Params{Numbers: []int{3, 4}, Wait: true, Interval: time.Second},
+			Statuses: map[int][]*pr.Status{
+				3: {
+					newStatus(3, pr.StateOpen, pr.ChecksPending),
+					newStatus(3, pr.StateMerged, pr.ChecksSuccess),
+				},
+				4: {
+					newStatus(4, pr.StateOpen, pr.ChecksPending),
+					newStatus(4, pr.StateOpen, pr.ChecksFailure),
+				},
+			},
+			ExpectedError: "pull request #4 has failing checks",
+		},
+		{
+			Name:   "wait until closed",
+			Params: Params{Numbers: []int{5}, Wait: true, Interval: time.Second},
+			Statuses: map[int][]*pr.Status{
+				5: {
+					newStatus(5, pr.StateOpen, pr.ChecksPending),
+					newStatus(5, pr.StateClosed, pr.ChecksPending),
+				},
+			},
+			ExpectedError: "pull request #5 was closed without being merged",
+		},
+		{
+			Name:   "wait times out",
+			Params: Params{Numbers: []int{6}, Wait: true, Interval: time.Minute, Timeout: 2 * time.Minute},
+			Statuses: map[int][]*pr.Status{
+				6: {newStatus(6, pr.StateOpen, pr.ChecksPending)},
+			},
+			ExpectedError: "timed out after 2m0s waiting for pull requests #6",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			polls := make(map[int]int)
+			provider := &pr.PullRequestProviderMock{
+				ListOpenFunc: func(labels ...string) ([]pr.PullRequest, error) {
+					return tc.OpenPRs, nil
+				},
+				GetStatusFunc: func(number int) (*pr.Status, error) {
+					statuses := tc.Statuses[number]
+					status := statuses[min(polls[number], len(statuses)-1)]
+					polls[number]++
+					return status, nil
+				},
+			}
+
+			var out bytes.Buffer
+			tracker := NewTracker(provider, &out)
+
+			now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+			tracker.now = func() time.Time { return now }
+			tracker.sleep = func(d time.Duration) { now = now.Add(d) }
+
+			err := tracker.Run(tc.Params)
+			if tc.ExpectedError != "" {
+				require.ErrorContains(t, err, tc.ExpectedError)
+				return
+			}
+			require.NoError(t, err)
+
+			if tc.ExpectedLabels != nil {
+				require.Len(t, provider.ListOpenCalls(), 1)
+				require.Equal(t, tc.ExpectedLabels, provider.ListOpenCalls()[0].Labels)
+			}
+
+			if tc.ExpectedStatuses != nil {
+				var statuses []PullRequestStatus
+				require.NoError(t, json.Unmarshal(out.Bytes(), &statuses))
+				require.Equal(t, tc.ExpectedStatuses, statuses)
+			}
+
+			for _, expected := range tc.ExpectedOutput {
+				require.Contains(t, out.String(), expected)
+			}
+		})
+	}
+}
+
+func TestParseNumber(t *testing.T) {
+	cases := []struct {
+		Value         string
+		Expected      int
+		ExpectedError string
+	}{
+		{Value: "123", Expected: 123},
+		{Value: "#123", Expected: 123},
+		{Value: "!123", Expected: 123},
+		{Value: "https://github.com/acme/catalog/pull/123", Expected: 123},
+		{Value: "https://gitlab.com/acme/catalog/-/merge_requests/123/", Expected: 123},
+		{Value: "https://github.com/acme/catalog", ExpectedError: "invalid pull request number or url: https://github.com/acme/catalog"},
+		{Value: "0", ExpectedError: "invalid pull request number or url: 0"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Value, func(t *testing.T) {
+			number, err := ParseNumber(tc.Value)
+			if tc.ExpectedError != "" {
+				require.EqualError(t, err, tc.ExpectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.Expected, number)
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package project
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/nestoca/joy/internal/info"
+	"github.com/nestoca/joy/internal/style"
+	"github.com/nestoca/joy/pkg/catalog"
+)
+
+// Details describes a project along with its releases across environments.
+type Details struct {
+	Name               string    `json:"name"`
+	Owners             []string  `json:"owners,omitempty"`
+	Reviewers          []string  `json:"reviewers,omitempty"`
+	Repository         string    `json:"repository,omitempty"`
+	RepositorySubpaths []string  `json:"repositorySubpaths,omitempty"`
+	Environments       []string  `json:"environments"`
+	Releases           []Release `json:"releases"`
+}
+
+// Release is a release of project, with its version in each environment, aligned with Details.Environments, or
+// empty where it is not deployed.
+type Release struct {
+	Name     string   `json:"name"`
+	Versions []string `json:"versions"`
+}
+
+// GetDetails returns the details of project with given name, resolving its repository with given info provider, such
+// that it defaults to the one of same name in the GitHub organization when not explicitly set.
+func GetDetails(cat *catalog.Catalog, infoProvider info.Provider, name string) (Details, error) {
+	var details Details
+	for _, proj := range cat.Projects {
+		if proj.Name == name {
+			details = Details{
+				Name:               proj.Name,
+				Owners:             proj.Spec.Owners,
+				Reviewers:          proj.Spec.Reviewers,
+				Repository:         infoProvider.GetProjectRepository(proj),
+				RepositorySubpaths: proj.Spec.RepositorySubpaths,
+			}
+			break
+		}
+	}
+	if details.Name == "" {
+		return Details{}, fmt.Errorf("project %s not found", name)
+	}
+
+	for _, env := range cat.Releases.Environments {
+		details.Environments = append(details.Environments, env.Name)
+	}
+
+	details.Releases = []Release{}
+	for _, crossRelease := range cat.Releases.SortedCrossReleases() {
+		release := Release{Name: crossRelease.Name, Versions: make([]string, len(crossRelease.Releases))}
+		found := false
+		for i, rel := range crossRelease.Releases {
+			if rel != nil && rel.Spec.Project == name {
+				release.Versions[i] = rel.Spec.Version
+				found = true
+			}
+		}
+		if found {
+			details.Releases = append(details.Releases, release)
+		}
+	}
+
+	return details, nil
+}
+
+// FormatDetails writes given project details, followed by a table of its releases across environments.
+func FormatDetails(w io.Writer, details Details) {
+	field := func(label string, value string) {
+		if value == "" {
+			value = style.SecondaryInfo("-")
+		}
+		_, _ = fmt.Fprintf(w, "%-12s %s\n", label+":", value)
+	}
+
+	field("Project", style.Resource(details.Name))
+	field("Owners", strings.Join(details.Owners, ", "))
+	field("Reviewers", strings.Join(details.Reviewers, ", "))
+	field("Repository", details.Repository)
+	if len(details.RepositorySubpaths) > 0 {
+		field("Subpaths", strings.Join(details.RepositorySubpaths, ", "))
+	}
+
+	if len(details.Releases) == 0 {
+		_, _ = fmt.Fprintf(w, "\n🤷 No releases found for project %s\n", details.Name)
+		return
+	}
+	_, _ = fmt.Fprintln(w)
+
+	table := style.NewTable(w)
+
+	table.SetHeader(append([]string{"NAME"}, upper(details.Environments)...))
+	for _, release := range details.Releases {
+		row := []string{release.Name}
+		for _, version := range release.Versions {
+			if version == "" {
+				version = style.SecondaryInfo("-")
+			}
+			row = append(row, version)
+		}
+		table.Append(row)
+	}
+	table.Render()
+}
+
+func upper(values []string) []string {
+	result := make([]string, len(values))
+	for i, value := range values {
+		result[i] = strings.ToUpper(value)
+	}
+	return result
+}
@@ -0,0 +1,60 @@
+package project
+
+import (
+	"bytes"
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/acarl005/stripansi"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nestoca/joy/api/v1alpha1"
+	"github.com/nestoca/joy/internal/info"
+	"github.com/nestoca/joy/pkg/catalog"
+)
+
+func TestDetails(t *testing.T) {
+	cat, err := catalog.Load(context.Background(), "testdata/catalog", nil)
+	require.NoError(t, err)
+
+	infoProvider := &info.ProviderMock{
+		GetProjectRepositoryFunc: func(project *v1alpha1.Project) string {
+			return "acme/" + project.Name
+		},
+	}
+
+	details, err := GetDetails(cat, infoProvider, "my-project")
+	require.NoError(t, err)
+	require.Equal(t, Details{
+		Name:         "my-project",
+		Owners:       []string{"team-a"},
+		Reviewers:    []string{"john"},
+		Repository:   "acme/my-project",
+		Environments: []string{"dev", "staging"},
+		Releases: []Release{
+			{Name: "api", Versions: []string{"1.1.0", "1.0.0"}},
+			{Name: "worker", Versions: []string{"2.0.0", ""}},
+		},
+	}, details)
+
+	_, err = GetDetails(cat, infoProvider, "unknown")
+	require.EqualError(t, err, "project unknown not found")
+
+	var output bytes.Buffer
+	FormatDetails(&output, details)
+	require.Equal(t, `Project:     my-project
+Owners:      team-a
+Reviewers:   john
+Repository:  acme/my-project
+
+NAME    DEV    STAGING
+api     1.1.0  1.0.0
+worker  2.0.0  -
+`, trimLines(stripansi.Strip(output.String())))
+}
+
+// trimLines removes the trailing whitespace tables pad their last column with.
+func trimLines(text string) string {
+	return regexp.MustCompile(`(?m)[ \t]+$`).ReplaceAllString(text, "")
+}
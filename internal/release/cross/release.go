@@ -3,6 +3,7 @@ package cross
 import (
 	"fmt"
 	"path/filepath"
+	"slices"
 
 	"github.com/nestoca/joy/api/v1alpha1"
 	"github.com/nestoca/joy/internal/yml"
@@ -23,12 +24,16 @@ type Release struct {
 
 	VersionInSync bool
 	ValuesInSync  bool
+
+	// NotPromotableReason explains why release cannot be promoted from source to target environment, if that is the
+	// case, in which case PromotedFile is nil. This is computed along with PromotedFile.
+	NotPromotableReason string
 }
 
 // ComputePromotedFile computes the promotion merged file for release from source to target environment,
 // assuming the only environments are respectively source and target. If merged result is same as target,
-// then no promotion is needed and PromotedFile is nil.
-func (r *Release) ComputePromotedFile(sourceEnv, targetEnv *v1alpha1.Environment) error {
+// then no promotion is needed and PromotedFile is nil. The merge is restricted to the given scope.
+func (r *Release) ComputePromotedFile(sourceEnv, targetEnv *v1alpha1.Environment, scope yml.MergeScope) error {
 	sourceRelease := r.Releases[0]
 	targetRelease := r.Releases[1]
 
@@ -39,12 +44,18 @@ func (r *Release) ComputePromotedFile(sourceEnv, targetEnv *v1alpha1.Environment
 		return nil
 	}
 
+	// Environment-scoped tags are resolved against the target environment
+	scope.Environment = targetEnv.Name
+
+	// Image digest pins the version, so they must always be promoted together
+	scope = pinDigestToVersion(scope)
+
 	// Do we have an existing target release?
 	var promotedFile *yml.File
 	var err error
 	if targetRelease != nil && targetRelease.File != nil {
 		// Promote source release to existing target
-		mergedTree := yml.Merge(targetRelease.File.Tree, sourceRelease.File.Tree)
+		mergedTree := yml.MergeWithScope(targetRelease.File.Tree, sourceRelease.File.Tree, scope)
 		promotedFile, err = targetRelease.File.CopyWithNewTree(mergedTree)
 		if err != nil {
 			return fmt.Errorf("creating in-memory copy of target file using merged result: %w", err)
@@ -56,7 +67,7 @@ func (r *Release) ComputePromotedFile(sourceEnv, targetEnv *v1alpha1.Environment
 		}
 
 		// Promote source release to empty target
-		promoted := yml.Merge(nil, sourceRelease.File.Tree)
+		promoted := yml.MergeWithScope(nil, sourceRelease.File.Tree, scope)
 		targetPath := filepath.Join(targetEnv.Dir, relativePath)
 
 		promotedFile, err = yml.NewFileFromTree(targetPath, sourceRelease.File.Indent, promoted)
@@ -65,15 +76,20 @@ func (r *Release) ComputePromotedFile(sourceEnv, targetEnv *v1alpha1.Environment
 		}
 	}
 
+	r.NotPromotableReason = ""
+	if targetRelease != nil && isDigestSplit(sourceRelease, targetRelease, promotedFile) {
+		r.NotPromotableReason = "version and digest would be promoted separately, make sure they are either both locked or both unlocked"
+	}
+
 	// Only consider promotion if the new merged result is different from existing target
 	if targetRelease != nil {
-		r.VersionInSync = targetRelease.Spec.Version == sourceRelease.Spec.Version
-		r.ValuesInSync = yml.EqualWithExclusion(targetRelease.File.Tree, promotedFile.Tree, "spec", "version")
+		r.VersionInSync = (targetRelease.Spec.Version == sourceRelease.Spec.Version && targetRelease.Spec.Digest == sourceRelease.Spec.Digest) || !scope.Includes(v1alpha1.ReleaseVersionPath)
+		r.ValuesInSync = yml.EqualWithExclusions(targetRelease.File.Tree, promotedFile.Tree, v1alpha1.ReleaseVersionPath, v1alpha1.ReleaseDigestPath)
 	} else {
 		r.VersionInSync = false
 		r.ValuesInSync = false
 	}
-	if targetRelease == nil || !r.VersionInSync || !r.ValuesInSync {
+	if r.NotPromotableReason == "" && (targetRelease == nil || !r.VersionInSync || !r.ValuesInSync) {
 		r.PromotedFile = promotedFile
 	} else {
 		r.PromotedFile = nil
@@ -81,6 +97,45 @@ func (r *Release) ComputePromotedFile(sourceEnv, targetEnv *v1alpha1.Environment
 	return nil
 }
 
+// DependsOn returns the sorted names of the releases this release depends on, in any of its environments.
+func (r *Release) DependsOn() []string {
+	var names []string
+	for _, release := range r.Releases {
+		if release != nil {
+			names = append(names, release.Spec.DependsOn...)
+		}
+	}
+	slices.Sort(names)
+	return slices.Compact(names)
+}
+
+// pinDigestToVersion returns given scope, ensuring that whenever it explicitly includes or excludes either of the
+// version or digest, it does so for both of them.
+func pinDigestToVersion(scope yml.MergeScope) yml.MergeScope {
+	pin := func(paths []string) []string {
+		hasVersion, hasDigest := slices.Contains(paths, v1alpha1.ReleaseVersionPath), slices.Contains(paths, v1alpha1.ReleaseDigestPath)
+		switch {
+		case hasVersion && !hasDigest:
+			return append(slices.Clone(paths), v1alpha1.ReleaseDigestPath)
+		case hasDigest && !hasVersion:
+			return append(slices.Clone(paths), v1alpha1.ReleaseVersionPath)
+		default:
+			return paths
+		}
+	}
+	scope.Only = pin(scope.Only)
+	scope.Except = pin(scope.Except)
+	return scope
+}
+
+// isDigestSplit returns true if the promoted file ends up with the version of either source or target but the digest
+// of the other, which can happen when only one of them is locked in target.
+func isDigestSplit(source, target *v1alpha1.Release, promoted *yml.File) bool {
+	version := yml.FindNodeValueOrDefault(promoted.Tree, v1alpha1.ReleaseVersionPath, "")
+	digest := yml.FindNodeValueOrDefault(promoted.Tree, v1alpha1.ReleaseDigestPath, "")
+	return (version != source.Spec.Version || digest != source.Spec.Digest) && (version != target.Spec.Version || digest != target.Spec.Digest)
+}
+
 func NewRelease(name string, environments []*v1alpha1.Environment) *Release {
 	return &Release{
 		Name:     name,
@@ -3,6 +3,7 @@ package cross
 import (
 	"errors"
 	"fmt"
+	"path/filepath"
 	"slices"
 	"sort"
 	"strings"
@@ -74,7 +75,7 @@ func LoadReleaseList(allFiles []*yml.File, environments []*v1alpha1.Environment,
 // Release files are assumed to be within the same directory (or any recursive subdirectory) as the environment file.
 func findEnvironmentForReleaseFile(environments []*v1alpha1.Environment, releaseFile *yml.File) *v1alpha1.Environment {
 	for _, env := range environments {
-		if strings.HasPrefix(releaseFile.Path, env.Dir) {
+		if strings.HasPrefix(releaseFile.Path, env.Dir+string(filepath.Separator)) {
 			return env
 		}
 	}
@@ -188,7 +189,8 @@ func (r *ReleaseList) RemoveReleasesByName(releases []string) (ReleaseList, erro
 
 // GetReleasesForPromotion returns a subset of the releases in this list that are promotable,
 // with only the given source and target environments as first and second environments.
-func (r *ReleaseList) GetReleasesForPromotion(sourceEnv, targetEnv *v1alpha1.Environment) (ReleaseList, error) {
+// Promoted files are computed by merging only the parts of source releases included in given scope.
+func (r *ReleaseList) GetReleasesForPromotion(sourceEnv, targetEnv *v1alpha1.Environment, scope yml.MergeScope) (ReleaseList, error) {
 	sourceEnvIndex := r.GetEnvironmentIndexByName(sourceEnv.Name)
 	targetEnvIndex := r.GetEnvironmentIndexByName(targetEnv.Name)
 	subset := MakeReleaseList([]*v1alpha1.Environment{sourceEnv, targetEnv})
@@ -201,7 +203,7 @@ func (r *ReleaseList) GetReleasesForPromotion(sourceEnv, targetEnv *v1alpha1.Env
 		newItem.Releases = []*v1alpha1.Release{sourceRelease, targetRelease}
 
 		// Compute promoted file
-		err := newItem.ComputePromotedFile(sourceEnv, targetEnv)
+		err := newItem.ComputePromotedFile(sourceEnv, targetEnv, scope)
 		if err != nil {
 			return ReleaseList{}, fmt.Errorf("computing promoted file for release %s: %w", item.Name, err)
 		}
@@ -279,6 +281,25 @@ func (r *ReleaseList) HasAnyPromotableReleases() bool {
 	return false
 }
 
+// GetChangedPaths returns the sorted dot-separated paths of values that would change in existing target releases
+// if their promoted files were written. It assumes the only environments are respectively source and target.
+func (r *ReleaseList) GetChangedPaths() []string {
+	var paths []string
+	for _, crossRelease := range r.Items {
+		targetRelease := crossRelease.Releases[1]
+		if crossRelease.PromotedFile == nil || targetRelease == nil || targetRelease.File == nil {
+			continue
+		}
+		for _, path := range yml.DiffPaths(targetRelease.File.Tree, crossRelease.PromotedFile.Tree) {
+			if !slices.Contains(paths, path) {
+				paths = append(paths, path)
+			}
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
 func (r *ReleaseList) GetEnvironmentRelease(environment *v1alpha1.Environment, releaseName string) (*v1alpha1.Release, error) {
 	releaseIndex := r.getReleaseIndex(releaseName)
 	if releaseIndex == -1 {
@@ -287,3 +308,39 @@ func (r *ReleaseList) GetEnvironmentRelease(environment *v1alpha1.Environment, r
 	envIndex := r.GetEnvironmentIndex(environment)
 	return r.Items[releaseIndex].Releases[envIndex], nil
 }
+
+// SortByDependencies returns a copy of the list where releases come after the releases they depend on, otherwise
+// preserving their original order. Dependencies on releases not in the list are ignored.
+func (r *ReleaseList) SortByDependencies() (ReleaseList, error) {
+	remaining := slices.Clone(r.Items)
+	sorted := MakeReleaseList(r.Environments)
+
+	isSorted := func(name string) bool {
+		return slices.ContainsFunc(sorted.Items, func(item *Release) bool { return item.Name == name })
+	}
+	isListed := func(name string) bool {
+		return slices.ContainsFunc(r.Items, func(item *Release) bool { return item.Name == name })
+	}
+
+	for len(remaining) > 0 {
+		index := slices.IndexFunc(remaining, func(item *Release) bool {
+			for _, dependency := range item.DependsOn() {
+				if dependency != item.Name && isListed(dependency) && !isSorted(dependency) {
+					return false
+				}
+			}
+			return true
+		})
+		if index == -1 {
+			var names []string
+			for _, item := range remaining {
+				names = append(names, item.Name)
+			}
+			return ReleaseList{}, fmt.Errorf("dependency cycle between releases: %s", strings.Join(names, ", "))
+		}
+		sorted.Items = append(sorted.Items, remaining[index])
+		remaining = slices.Delete(remaining, index, index+1)
+	}
+
+	return sorted, nil
+}
@@ -1,6 +1,7 @@
 package filtering
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/nestoca/joy/api/v1alpha1"
@@ -71,3 +72,30 @@ func (f *OwnerFilter) Match(rel *v1alpha1.Release) bool {
 	}
 	return false
 }
+
+// LabelFilter matches releases whose metadata labels match all given key/value pairs.
+type LabelFilter struct {
+	Labels map[string]string
+}
+
+// NewLabelFilter returns a filter for given comma-separated list of key=value label pairs.
+func NewLabelFilter(selector string) (*LabelFilter, error) {
+	labels := make(map[string]string)
+	for _, pair := range strings.Split(selector, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid label selector %q: expected key=value pairs", selector)
+		}
+		labels[key] = value
+	}
+	return &LabelFilter{Labels: labels}, nil
+}
+
+func (f *LabelFilter) Match(rel *v1alpha1.Release) bool {
+	for key, value := range f.Labels {
+		if actual, ok := rel.Labels[key]; !ok || actual != value {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,197 @@
+package list
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/mod/semver"
+	"gopkg.in/yaml.v3"
+
+	"github.com/nestoca/joy/api/v1alpha1"
+	"github.com/nestoca/joy/internal/git"
+	"github.com/nestoca/joy/internal/info"
+)
+
+// Distance is how far a release version is from the version of the reference environment. Only the most significant
+// semver component that differs is set, negative when behind and positive when ahead, ie: 1.2.3 compared to a 1.4.0
+// reference is 2 minor versions behind, with a Minor distance of -2.
+type Distance struct {
+	Major int `json:"major"`
+	Minor int `json:"minor"`
+	Patch int `json:"patch"`
+
+	// Commits is the number of commits between the git tags of both versions, only set when requested.
+	Commits *int `json:"commits,omitempty"`
+}
+
+// String returns a short description of distance, ie: "2 minor behind · 14 commits", or an empty string when in-sync.
+func (d Distance) String() string {
+	var parts []string
+	switch {
+	case d.Major != 0:
+		parts = append(parts, describeSemverDistance(d.Major, "major"))
+	case d.Minor != 0:
+		parts = append(parts, describeSemverDistance(d.Minor, "minor"))
+	case d.Patch != 0:
+		parts = append(parts, describeSemverDistance(d.Patch, "patch"))
+	}
+	if d.Commits != nil && *d.Commits != 0 {
+		parts = append(parts, fmt.Sprintf("%d commits", *d.Commits))
+	}
+	return strings.Join(parts, " · ")
+}
+
+func describeSemverDistance(distance int, component string) string {
+	if distance < 0 {
+		return fmt.Sprintf("%d %s behind", -distance, component)
+	}
+	return fmt.Sprintf("%d %s ahead", distance, component)
+}
+
+// getSemverDistance returns the distance between given version and reference version, or nil if either of them is not
+// a valid semantic version.
+func getSemverDistance(version, referenceVersion string) *Distance {
+	components, ok := parseSemverComponents(version)
+	referenceComponents, referenceOk := parseSemverComponents(referenceVersion)
+	if !ok || !referenceOk {
+		return nil
+	}
+
+	var distance Distance
+	switch {
+	case components[0] != referenceComponents[0]:
+		distance.Major = components[0] - referenceComponents[0]
+	case components[1] != referenceComponents[1]:
+		distance.Minor = components[1] - referenceComponents[1]
+	case components[2] != referenceComponents[2]:
+		distance.Patch = components[2] - referenceComponents[2]
+	}
+	return &distance
+}
+
+func parseSemverComponents(version string) ([3]int, bool) {
+	var components [3]int
+
+	canonical := semver.Canonical("v" + strings.TrimPrefix(version, "v"))
+	if canonical == "" {
+		return components, false
+	}
+	canonical = strings.TrimSuffix(canonical, semver.Prerelease(canonical)+semver.Build(canonical))
+
+	for i, value := range strings.Split(strings.TrimPrefix(canonical, "v"), ".") {
+		component, err := strconv.Atoi(value)
+		if err != nil {
+			return components, false
+		}
+		components[i] = component
+	}
+	return components, true
+}
+
+// getCommitCount returns the number of commits between the git tags of given release and reference release, or nil if
+// it cannot be determined, for instance when the tags do not exist in project repository.
+func getCommitCount(provider info.Provider, rel, reference *v1alpha1.Release) *int {
+	tag, err := provider.GetReleaseGitTag(rel)
+	if err != nil {
+		return nil
+	}
+	referenceTag, err := provider.GetReleaseGitTag(reference)
+	if err != nil {
+		return nil
+	}
+
+	count := 0
+	if tag == referenceTag {
+		return &count
+	}
+
+	olderTag, newerTag := tag, referenceTag
+	if semver.Compare("v"+rel.Spec.Version, "v"+reference.Spec.Version) > 0 {
+		olderTag, newerTag = referenceTag, tag
+	}
+
+	if rel.Project == nil {
+		return nil
+	}
+	projectDir, err := provider.GetProjectSourceDir(rel.Project)
+	if err != nil {
+		return nil
+	}
+	commits, err := provider.GetCommitsMetadata(projectDir, olderTag, newerTag)
+	if err != nil {
+		return nil
+	}
+
+	count = len(commits)
+	return &count
+}
+
+// versionLinePattern matches the lines of release files where a version may be set, which include the version of the
+// chart and any version key in values, such that catalog history can be narrowed down to the commits that may have
+// changed the release version.
+const versionLinePattern = `^[[:space:]]*version:`
+
+// getVersionUpdatedAt returns when the version of given release was last changed according to the history of catalog,
+// or nil if it cannot be determined. Only the spec.version of the release is considered, by comparing it before and
+// after each commit that may have changed it.
+func getVersionUpdatedAt(catalogDir string, rel *v1alpha1.Release) *time.Time {
+	if rel == nil || rel.File == nil {
+		return nil
+	}
+	file, err := filepath.Rel(catalogDir, rel.File.Path)
+	if err != nil {
+		return nil
+	}
+	commits, err := git.GetFileCommits(catalogDir, file, versionLinePattern)
+	if err != nil {
+		return nil
+	}
+
+	for _, commit := range commits {
+		version, ok, err := getReleaseVersionAtRevision(catalogDir, commit.Sha, file)
+		if err != nil || !ok {
+			return nil
+		}
+		previousVersion, _, err := getReleaseVersionAtRevision(catalogDir, commit.Sha+"^", file)
+		if err != nil {
+			return nil
+		}
+		if version != previousVersion {
+			return &commit.Time
+		}
+	}
+	return nil
+}
+
+// getReleaseVersionAtRevision returns the spec.version of given release file at given revision of catalog, or false if
+// the file does not exist at that revision.
+func getReleaseVersionAtRevision(catalogDir, revision, file string) (string, bool, error) {
+	data, ok, err := git.GetFileAtRevision(catalogDir, revision, file)
+	if err != nil || !ok {
+		return "", false, err
+	}
+	var release struct {
+		Spec struct {
+			Version string `yaml:"version"`
+		} `yaml:"spec"`
+	}
+	if err := yaml.Unmarshal(data, &release); err != nil {
+		return "", false, fmt.Errorf("parsing %s at revision %s: %w", file, revision, err)
+	}
+	return release.Spec.Version, true, nil
+}
+
+// formatAge returns a compact description of given duration, such as "3d", "5h" or "<1h".
+func formatAge(age time.Duration) string {
+	switch {
+	case age >= 24*time.Hour:
+		return fmt.Sprintf("%dd", int(age/(24*time.Hour)))
+	case age >= time.Hour:
+		return fmt.Sprintf("%dh", int(age/time.Hour))
+	default:
+		return "<1h"
+	}
+}
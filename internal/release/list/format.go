@@ -0,0 +1,227 @@
+package list
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/Masterminds/sprig/v3"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"gopkg.in/yaml.v3"
+
+	"github.com/nestoca/joy/internal/style"
+)
+
+// Column is a release property that can be displayed for each environment in table, csv and markdown formats.
+type Column string
+
+const (
+	VersionColumn      Column = "version"
+	ChartVersionColumn Column = "chart-version"
+	NamespaceColumn    Column = "namespace"
+	DigestColumn       Column = "digest"
+	ProjectColumn      Column = "project"
+
+	// DistanceColumn describes how far the version is from the reference environment and how long ago it changed.
+	DistanceColumn Column = "distance"
+)
+
+var Columns = []Column{VersionColumn, ChartVersionColumn, NamespaceColumn, DigestColumn, ProjectColumn, DistanceColumn}
+
+// ParseColumns validates given column names, defaulting to the version column only when none are given.
+func ParseColumns(names []string) ([]Column, error) {
+	if len(names) == 0 {
+		return []Column{VersionColumn}, nil
+	}
+	var columns []Column
+	for _, name := range names {
+		column := Column(strings.TrimSpace(name))
+		if !slices.Contains(Columns, column) {
+			return nil, fmt.Errorf("unknown column %q: expected one of %s", name, joinColumns(Columns))
+		}
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
+
+func joinColumns(columns []Column) string {
+	var names []string
+	for _, column := range columns {
+		names = append(names, string(column))
+	}
+	return strings.Join(names, ", ")
+}
+
+const (
+	TableFormat    = "table"
+	JSONFormat     = "json"
+	YAMLFormat     = "yaml"
+	CSVFormat      = "csv"
+	MarkdownFormat = "markdown"
+)
+
+var Formats = []string{TableFormat, JSONFormat, YAMLFormat, CSVFormat, MarkdownFormat}
+
+type FormatOpts struct {
+	// Format is one of the predefined formats, or a Go template rendered once per cross-release.
+	Format string
+
+	// Columns to display for each environment in table, csv and markdown formats.
+	Columns []Column
+
+	// ShowDrift marks the versions of releases whose values drift from the reference environment in table, csv
+	// and markdown formats.
+	ShowDrift bool
+
+	ReferenceEnvironment string
+	MaxColumnWidth       int
+}
+
+// FormatReleaseList renders given release list in the requested format.
+func FormatReleaseList(releaseList ReleaseList, opts FormatOpts) (string, error) {
+	columns := opts.Columns
+	if len(columns) == 0 {
+		columns = []Column{VersionColumn}
+	}
+
+	switch opts.Format {
+	case "", TableFormat:
+		return FormatReleaseListAsTable(releaseList, opts.ReferenceEnvironment, opts.MaxColumnWidth, columns, opts.ShowDrift), nil
+	case JSONFormat:
+		return FormatReleaseListAsJson(releaseList)
+	case YAMLFormat:
+		return formatReleaseListAsYaml(releaseList)
+	case CSVFormat:
+		return newTable(releaseList, columns, 0, false, opts.ShowDrift).RenderCSV(), nil
+	case MarkdownFormat:
+		return newTable(releaseList, columns, 0, false, opts.ShowDrift).RenderMarkdown(), nil
+	}
+
+	if !strings.Contains(opts.Format, "{{") {
+		return "", fmt.Errorf("unknown format %q: expected one of %s, or a Go template", opts.Format, strings.Join(Formats, ", "))
+	}
+	return formatReleaseListWithTemplate(releaseList, opts.Format)
+}
+
+// newTable returns a table with one row per cross-release and, for each environment, one column per given column.
+func newTable(releaseList ReleaseList, columns []Column, maxColumnWidth int, colorize, showDrift bool) table.Writer {
+	t := table.NewWriter()
+
+	headers := table.Row{"NAME"}
+	for _, env := range releaseList.Environments {
+		for _, column := range columns {
+			header := strings.ToUpper(env)
+			if len(columns) > 1 {
+				header += " " + strings.ToUpper(strings.ReplaceAll(string(column), "-", " "))
+			}
+			headers = append(headers, header)
+		}
+	}
+	t.AppendHeader(headers)
+
+	for _, release := range releaseList.CrossReleases {
+		row := table.Row{release.TreePrefix + release.Name}
+		for _, version := range release.Releases {
+			for _, column := range columns {
+				value := version.value(column)
+				if maxColumnWidth != 0 && len(value) > maxColumnWidth {
+					value = value[:maxColumnWidth-3] + "..."
+				}
+				if column == VersionColumn {
+					value = formatVersion(value, version, colorize, showDrift)
+				}
+				row = append(row, value)
+			}
+		}
+		t.AppendRow(row)
+	}
+
+	return t
+}
+
+func formatVersion(value string, release Release, colorize, showDrift bool) string {
+	drift := ""
+	if showDrift && release.ValuesDrift {
+		drift = DriftMarker
+	}
+	if !colorize {
+		return value + drift
+	}
+	if drift != "" {
+		drift = style.Warning(drift)
+	}
+	return colorizeVersion(value, release.Status) + drift
+}
+
+// value returns the value of given column for release.
+func (release Release) value(column Column) string {
+	if release.Release == nil {
+		return NoReleaseVersion
+	}
+	switch column {
+	case ChartVersionColumn:
+		return release.ChartVersion
+	case NamespaceColumn:
+		return release.Namespace
+	case DigestColumn:
+		return release.Spec.Digest
+	case ProjectColumn:
+		return release.Spec.Project
+	case DistanceColumn:
+		return release.describeDistance()
+	default:
+		return release.DisplayVersion
+	}
+}
+
+// describeDistance returns the distance to the reference version, followed by the age of the version if known.
+func (release Release) describeDistance() string {
+	var parts []string
+	if release.Distance != nil {
+		if distance := release.Distance.String(); distance != "" {
+			parts = append(parts, distance)
+		}
+	}
+	if release.VersionUpdatedAt != nil {
+		parts = append(parts, formatAge(time.Since(*release.VersionUpdatedAt)))
+	}
+	return strings.Join(parts, " · ")
+}
+
+func formatReleaseListAsYaml(releaseList ReleaseList) (string, error) {
+	// Go through JSON, such that the structure is the same as in JSON format
+	data, err := json.Marshal(releaseList)
+	if err != nil {
+		return "", fmt.Errorf("marshalling output as JSON: %w", err)
+	}
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return "", fmt.Errorf("unmarshalling JSON output: %w", err)
+	}
+	output, err := yaml.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("marshalling output as YAML: %w", err)
+	}
+	return strings.TrimSuffix(string(output), "\n"), nil
+}
+
+func formatReleaseListWithTemplate(releaseList ReleaseList, text string) (string, error) {
+	tmpl, err := template.New("format").Funcs(sprig.FuncMap()).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("parsing format template: %w", err)
+	}
+
+	var lines []string
+	for _, release := range releaseList.CrossReleases {
+		var buffer bytes.Buffer
+		if err := tmpl.Execute(&buffer, release); err != nil {
+			return "", fmt.Errorf("executing format template for release %s: %w", release.Name, err)
+		}
+		lines = append(lines, strings.TrimSuffix(buffer.String(), "\n"))
+	}
+	return strings.Join(lines, "\n"), nil
+}
@@ -5,18 +5,35 @@ import (
 	"fmt"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/jedib0t/go-pretty/v6/table"
 	"golang.org/x/mod/semver"
+	"gopkg.in/yaml.v3"
 
 	"github.com/nestoca/joy/api/v1alpha1"
+	"github.com/nestoca/joy/internal/info"
 	"github.com/nestoca/joy/internal/style"
+	"github.com/nestoca/joy/internal/yml"
 	"github.com/nestoca/joy/pkg/catalog"
+	"github.com/nestoca/joy/pkg/helm"
 )
 
 type Params struct {
 	SelectedEnvs         []string
 	ReferenceEnvironment string
+
+	// Charts resolves the chart version shown for each release.
+	Charts helm.ChartCache
+
+	// Info, when set, is used to count the commits between the git tags of each release and of the reference one.
+	Info info.Provider
+
+	// CatalogDir, when set, is used to determine from catalog history when the version of each release last changed.
+	CatalogDir string
+
+	// Drift determines whether values of each release drift from those of the reference environment.
+	Drift bool
 }
 
 const (
@@ -32,11 +49,27 @@ type Release struct {
 	DisplayVersion    string `json:"version"`
 	Status            string `json:"status"`
 	Environment       string `json:"environment"`
+	ChartVersion      string `json:"chartVersion,omitempty"`
+	Namespace         string `json:"namespace,omitempty"`
+
+	// ValuesDrift is true when release values diverge from those of the reference environment, disregarding
+	// values that are locked or local to any environment. Only determined when requested.
+	ValuesDrift bool `json:"valuesDrift,omitempty"`
+
+	// Distance to the version of the reference environment, when both versions are valid semantic versions.
+	Distance *Distance `json:"distance,omitempty"`
+
+	// VersionUpdatedAt is when the version last changed according to catalog history, only set when requested.
+	VersionUpdatedAt *time.Time `json:"versionUpdatedAt,omitempty"`
 }
 
 type CrossRelease struct {
-	Name     string    `json:"name"`
-	Releases []Release `json:"releases"`
+	Name      string    `json:"name"`
+	DependsOn []string  `json:"dependsOn,omitempty"`
+	Releases  []Release `json:"releases"`
+
+	// TreePrefix is the indentation to display in front of name when listing releases as a dependency tree.
+	TreePrefix string `json:"-"`
 }
 
 type ReleaseList struct {
@@ -59,30 +92,51 @@ func GetReleaseList(cat *catalog.Catalog, params Params) (ReleaseList, error) {
 		releaseList.Environments = append(releaseList.Environments, env.Name)
 	}
 
+	charts := params.Charts
+
 	for _, crossRelease := range cat.Releases.Items {
 		outputRelease := CrossRelease{
-			Name: crossRelease.Name,
+			Name:      crossRelease.Name,
+			DependsOn: crossRelease.DependsOn(),
 		}
 
-		referenceVersion := NoReleaseVersion
+		var referenceRelease *v1alpha1.Release
 		for _, rel := range crossRelease.Releases {
 			if rel != nil && rel.Environment.Name == params.ReferenceEnvironment {
-				referenceVersion = GetReleaseDisplayVersion(rel)
+				referenceRelease = rel
 			}
 		}
+		referenceVersion := GetReleaseDisplayVersion(referenceRelease)
 
 		for envIndex, rel := range crossRelease.Releases {
 			if !slices.Contains(selectedEnvIndices, envIndex) {
 				continue
 			}
 			displayVersion := GetReleaseDisplayVersion(rel)
+			status := getVersionStatus(displayVersion, referenceVersion)
 
-			outputRelease.Releases = append(outputRelease.Releases, Release{
+			release := Release{
 				Release:        rel,
 				DisplayVersion: displayVersion,
-				Status:         getVersionStatus(displayVersion, referenceVersion),
+				Status:         status,
 				Environment:    cat.Environments[envIndex].Name,
-			})
+				ChartVersion:   getChartVersion(rel, charts),
+				Namespace:      getNamespace(rel),
+			}
+			if status != UnknownStatus {
+				release.Distance = getSemverDistance(displayVersion, referenceVersion)
+			}
+			if release.Distance != nil && params.Info != nil {
+				release.Distance.Commits = getCommitCount(params.Info, rel, referenceRelease)
+			}
+			if params.Drift {
+				release.ValuesDrift = hasValuesDrift(rel, referenceRelease)
+			}
+			if params.CatalogDir != "" {
+				release.VersionUpdatedAt = getVersionUpdatedAt(params.CatalogDir, rel)
+			}
+
+			outputRelease.Releases = append(outputRelease.Releases, release)
 		}
 
 		releaseList.CrossReleases = append(releaseList.CrossReleases, outputRelease)
@@ -91,41 +145,82 @@ func GetReleaseList(cat *catalog.Catalog, params Params) (ReleaseList, error) {
 	return releaseList, nil
 }
 
-func FormatReleaseListAsTable(releaseList ReleaseList, referenceEnvironment string, maxColumnWidth int) string {
-	t := table.NewWriter()
-	t.SetStyle(table.StyleRounded)
-
-	headers := table.Row{"NAME"}
-	for _, env := range releaseList.Environments {
-		headers = append(headers, strings.ToUpper(env))
-	}
-	t.AppendHeader(headers)
-
+func FormatReleaseListAsTable(releaseList ReleaseList, referenceEnvironment string, maxColumnWidth int, columns []Column, showDrift bool) string {
 	legend := table.NewWriter()
 	legend.SetStyle(table.StyleRounded)
-	legend.AppendRow(table.Row{
+	legendRow := table.Row{
 		"Reference Environment: " + referenceEnvironment,
 		style.DirtyVersion("Pre-Release (PR)"),
 		style.BehindVersion("Behind"),
 		style.AheadVersion("Ahead"),
 		style.InSyncVersion("In-Sync"),
-	})
-	fmt.Println(legend.Render())
+	}
+	if showDrift {
+		legendRow = append(legendRow, style.Warning(DriftMarker+" Values Drift"))
+	}
+	legend.AppendRow(legendRow)
+
+	t := newTable(releaseList, columns, maxColumnWidth, true, showDrift)
+	t.SetStyle(table.StyleRounded)
 
+	return legend.Render() + "\n" + t.Render()
+}
+
+// AsDependencyTree returns a copy of given release list where each release is followed by the releases it depends
+// on, indented as a tree. Releases that no other release depends on are the roots of the tree, and releases depending
+// on multiple others appear once under each of them.
+func AsDependencyTree(releaseList ReleaseList) ReleaseList {
+	byName := make(map[string]CrossRelease)
+	dependedOn := make(map[string]bool)
 	for _, release := range releaseList.CrossReleases {
-		row := table.Row{release.Name}
-		for _, version := range release.Releases {
-			displayVersion := version.DisplayVersion
-			if maxColumnWidth != 0 && len(version.DisplayVersion) > maxColumnWidth {
-				displayVersion = displayVersion[:maxColumnWidth-3] + "..."
+		byName[release.Name] = release
+		for _, dependency := range release.DependsOn {
+			if dependency != release.Name {
+				dependedOn[dependency] = true
 			}
-			displayVersion = colorizeVersion(displayVersion, version.Status)
-			row = append(row, displayVersion)
 		}
-		t.AppendRow(row)
 	}
 
-	return t.Render()
+	tree := releaseList
+	tree.CrossReleases = nil
+	visited := make(map[string]bool)
+
+	var walk func(release CrossRelease, prefix, indent string, ancestors []string)
+	walk = func(release CrossRelease, prefix, indent string, ancestors []string) {
+		visited[release.Name] = true
+		release.TreePrefix = prefix
+		tree.CrossReleases = append(tree.CrossReleases, release)
+
+		ancestors = append(ancestors, release.Name)
+		var dependencies []CrossRelease
+		for _, name := range release.DependsOn {
+			if dependency, ok := byName[name]; ok && !slices.Contains(ancestors, name) {
+				dependencies = append(dependencies, dependency)
+			}
+		}
+		for i, dependency := range dependencies {
+			if i == len(dependencies)-1 {
+				walk(dependency, indent+"└─ ", indent+"   ", ancestors)
+			} else {
+				walk(dependency, indent+"├─ ", indent+"│  ", ancestors)
+			}
+		}
+	}
+
+	for _, release := range releaseList.CrossReleases {
+		if !dependedOn[release.Name] {
+			walk(release, "", "", nil)
+		}
+	}
+
+	// Releases only reachable through a dependency cycle have no root, so list them as roots of their own
+	for _, release := range releaseList.CrossReleases {
+		if !visited[release.Name] {
+			walk(release, "", "", nil)
+		}
+	}
+
+	return tree
 }
 
 func FormatReleaseListAsJson(releaseList ReleaseList) (string, error) {
@@ -139,6 +234,9 @@ func FormatReleaseListAsJson(releaseList ReleaseList) (string, error) {
 const (
 	NoReleaseVersion = "-"
 	NoVersion        = "no version"
+
+	// DriftMarker is appended to the version of releases whose values drift from the reference environment.
+	DriftMarker = "*"
 )
 
 func getVersionStatus(version, referenceVersion string) string {
@@ -182,6 +280,49 @@ func colorizeVersion(version, status string) string {
 	}
 }
 
+// hasValuesDrift returns true if values of release differ from those of reference release, once values locked or
+// local to any environment on either side are excluded from both sides.
+func hasValuesDrift(rel, reference *v1alpha1.Release) bool {
+	if rel == nil || reference == nil || rel == reference {
+		return false
+	}
+	values, referenceValues := getValues(rel), getValues(reference)
+	excludedPaths := append(yml.EnvironmentSpecificPaths(values), yml.EnvironmentSpecificPaths(referenceValues)...)
+	return !yml.EquivalentWithExclusions(values, referenceValues, excludedPaths...)
+}
+
+func getValues(rel *v1alpha1.Release) *yaml.Node {
+	if rel.File == nil || rel.File.Tree == nil {
+		return nil
+	}
+	values, err := yml.FindNode(rel.File.Tree, "spec.values")
+	if err != nil {
+		return nil
+	}
+	return values
+}
+
+func getChartVersion(rel *v1alpha1.Release, charts helm.ChartCache) string {
+	if rel == nil {
+		return ""
+	}
+	chart, err := charts.GetReleaseChart(rel)
+	if err != nil {
+		return ""
+	}
+	return chart.Version
+}
+
+func getNamespace(rel *v1alpha1.Release) string {
+	if rel == nil {
+		return ""
+	}
+	if rel.Spec.Namespace != "" || rel.Environment == nil {
+		return rel.Spec.Namespace
+	}
+	return rel.Environment.Spec.Namespace
+}
+
 func GetReleaseDisplayVersion(rel *v1alpha1.Release) string {
 	if rel == nil {
 		return NoReleaseVersion
@@ -0,0 +1,356 @@
+package list
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/nestoca/joy/api/v1alpha1"
+	"github.com/nestoca/joy/internal/info"
+	"github.com/nestoca/joy/internal/yml"
+)
+
+func TestAsDependencyTree(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Releases []CrossRelease
+		Expected []string
+	}{
+		{
+			Name: "releases without dependencies keep their order",
+			Releases: []CrossRelease{
+				{Name: "a"},
+				{Name: "b"},
+			},
+			Expected: []string{"a", "b"},
+		},
+		{
+			Name: "dependencies are nested under their dependents",
+			Releases: []CrossRelease{
+				{Name: "api", DependsOn: []string{"cache", "schema-migrator"}},
+				{Name: "cache"},
+				{Name: "schema-migrator", DependsOn: []string{"database"}},
+				{Name: "database"},
+				{Name: "worker", DependsOn: []string{"schema-migrator", "unlisted"}},
+			},
+			Expected: []string{
+				"api",
+				"├─ cache",
+				"└─ schema-migrator",
+				"   └─ database",
+				"worker",
+				"└─ schema-migrator",
+				"   └─ database",
+			},
+		},
+		{
+			Name: "dependency cycles are listed once",
+			Releases: []CrossRelease{
+				{Name: "a", DependsOn: []string{"b"}},
+				{Name: "b", DependsOn: []string{"a"}},
+			},
+			Expected: []string{"a", "└─ b"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			tree := AsDependencyTree(ReleaseList{CrossReleases: tc.Releases})
+
+			var names []string
+			for _, release := range tree.CrossReleases {
+				names = append(names, release.TreePrefix+release.Name)
+			}
+			require.Equal(t, tc.Expected, names)
+		})
+	}
+}
+
+func TestFormatReleaseList(t *testing.T) {
+	newRelease := func(env, version, chartVersion, namespace string) Release {
+		return Release{
+			Release: &v1alpha1.Release{
+				ReleaseMetadata: v1alpha1.ReleaseMetadata{Name: "api"},
+				Spec:            v1alpha1.ReleaseSpec{Version: version, Project: "backend"},
+			},
+			DisplayVersion: version,
+			Status:         InSyncStatus,
+			Environment:    env,
+			ChartVersion:   chartVersion,
+			Namespace:      namespace,
+		}
+	}
+
+	drifted := func(release Release) Release {
+		release.ValuesDrift = true
+		return release
+	}
+
+	behind := func(release Release, distance Distance) Release {
+		release.Distance = &distance
+		return release
+	}
+
+	releaseList := ReleaseList{
+		Environments: []string{"staging", "prod"},
+		CrossReleases: []CrossRelease{
+			{
+				Name: "api",
+				Releases: []Release{
+					drifted(newRelease("staging", "1.1.0", "2.0.0", "api-staging")),
+					behind(newRelease("prod", "1.0.0", "1.9.0", "api-prod"), Distance{Minor: -1, Commits: ptr(4)}),
+				},
+			},
+			{
+				Name:     "worker",
+				Releases: []Release{{DisplayVersion: NoReleaseVersion}, newRelease("prod", "3.0.0", "1.9.0", "worker")},
+			},
+		},
+	}
+
+	cases := []struct {
+		Name          string
+		Opts          FormatOpts
+		Expected      string
+		ExpectedError string
+	}{
+		{
+			Name:     "csv",
+			Opts:     FormatOpts{Format: CSVFormat},
+			Expected: "NAME,STAGING,PROD\napi,1.1.0,1.0.0\nworker,-,3.0.0",
+		},
+		{
+			Name: "markdown with columns",
+			Opts: FormatOpts{Format: MarkdownFormat, Columns: []Column{VersionColumn, ChartVersionColumn, NamespaceColumn}},
+			Expected: "| NAME | STAGING VERSION | STAGING CHART VERSION | STAGING NAMESPACE | PROD VERSION | PROD CHART VERSION | PROD NAMESPACE |\n" +
+				"| --- | --- | --- | --- | --- | --- | --- |\n" +
+				"| api | 1.1.0 | 2.0.0 | api-staging | 1.0.0 | 1.9.0 | api-prod |\n" +
+				"| worker | - | - | - | 3.0.0 | 1.9.0 | worker |",
+		},
+		{
+			Name:     "csv with drift",
+			Opts:     FormatOpts{Format: CSVFormat, ShowDrift: true},
+			Expected: "NAME,STAGING,PROD\napi,1.1.0*,1.0.0\nworker,-,3.0.0",
+		},
+		{
+			Name:     "csv with distance",
+			Opts:     FormatOpts{Format: CSVFormat, Columns: []Column{DistanceColumn}},
+			Expected: "NAME,STAGING,PROD\napi,,1 minor behind · 4 commits\nworker,-,",
+		},
+		{
+			Name:     "template",
+			Opts:     FormatOpts{Format: "{{.Name}} {{range .Releases}}{{.DisplayVersion}} {{end}}"},
+			Expected: "api 1.1.0 1.0.0 \nworker - 3.0.0 ",
+		},
+		{
+			Name:          "unknown format",
+			Opts:          FormatOpts{Format: "xml"},
+			ExpectedError: `unknown format "xml": expected one of table, json, yaml, csv, markdown, or a Go template`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			output, err := FormatReleaseList(releaseList, tc.Opts)
+			if tc.ExpectedError != "" {
+				require.EqualError(t, err, tc.ExpectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.Expected, output)
+		})
+	}
+
+	t.Run("yaml", func(t *testing.T) {
+		output, err := FormatReleaseList(releaseList, FormatOpts{Format: YAMLFormat})
+		require.NoError(t, err)
+
+		var result ReleaseList
+		require.NoError(t, yaml.Unmarshal([]byte(output), &result))
+		require.Equal(t, []string{"staging", "prod"}, result.Environments)
+		require.Contains(t, output, "chartVersion: 2.0.0")
+	})
+}
+
+func TestParseColumns(t *testing.T) {
+	columns, err := ParseColumns(nil)
+	require.NoError(t, err)
+	require.Equal(t, []Column{VersionColumn}, columns)
+
+	columns, err = ParseColumns([]string{"version", "namespace"})
+	require.NoError(t, err)
+	require.Equal(t, []Column{VersionColumn, NamespaceColumn}, columns)
+
+	_, err = ParseColumns([]string{"size"})
+	require.EqualError(t, err, `unknown column "size": expected one of version, chart-version, namespace, digest, project, distance`)
+}
+
+func TestHasValuesDrift(t *testing.T) {
+	newRelease := func(values string) *v1alpha1.Release {
+		file, err := yml.NewFile("release.yaml", []byte("apiVersion: joy.nesto.ca/v1alpha1\nkind: Release\nmetadata:\n  name: api\nspec:\n  version: 1.0.0\n"+values))
+		require.NoError(t, err)
+		release, err := v1alpha1.LoadRelease(file)
+		require.NoError(t, err)
+		return release
+	}
+
+	reference := newRelease("  values:\n    replicas: 2\n    host: !lock staging.example.com\n    debug: !local true\n")
+
+	cases := []struct {
+		Name     string
+		Values   string
+		Expected bool
+	}{
+		{
+			Name:     "same shared values",
+			Values:   "  values:\n    replicas: 2\n    host: !lock prod.example.com\n",
+			Expected: false,
+		},
+		{
+			Name:     "different shared values",
+			Values:   "  values:\n    replicas: 3\n    host: !lock staging.example.com\n",
+			Expected: true,
+		},
+		{
+			Name:     "environment-scoped lock",
+			Values:   "  values:\n    replicas: 2\n    host: !lock:prod prod.example.com\n    region: !local:prod ca-central-1\n",
+			Expected: false,
+		},
+		{
+			Name:     "value locked in target only",
+			Values:   "  values:\n    replicas: !lock 5\n",
+			Expected: false,
+		},
+		{
+			Name:     "same shared values in different order",
+			Values:   "  values:\n    host: !lock prod.example.com\n    replicas: 2\n",
+			Expected: false,
+		},
+		{
+			Name:     "missing values",
+			Values:   "",
+			Expected: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			require.Equal(t, tc.Expected, hasValuesDrift(newRelease(tc.Values), reference))
+		})
+	}
+
+	require.False(t, hasValuesDrift(reference, reference))
+	require.False(t, hasValuesDrift(reference, nil))
+}
+
+func TestGetSemverDistance(t *testing.T) {
+	cases := []struct {
+		Version          string
+		ReferenceVersion string
+		Expected         *Distance
+		ExpectedString   string
+	}{
+		{Version: "1.2.3", ReferenceVersion: "1.2.3", Expected: &Distance{}, ExpectedString: ""},
+		{Version: "1.2.3", ReferenceVersion: "1.4.0", Expected: &Distance{Minor: -2}, ExpectedString: "2 minor behind"},
+		{Version: "1.9.5", ReferenceVersion: "v2.0.0", Expected: &Distance{Major: -1}, ExpectedString: "1 major behind"},
+		{Version: "1.2.5-pr42", ReferenceVersion: "1.2.3", Expected: &Distance{Patch: 2}, ExpectedString: "2 patch ahead"},
+		{Version: "latest", ReferenceVersion: "1.2.3", Expected: nil},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Version+" vs "+tc.ReferenceVersion, func(t *testing.T) {
+			distance := getSemverDistance(tc.Version, tc.ReferenceVersion)
+			require.Equal(t, tc.Expected, distance)
+			if distance != nil {
+				require.Equal(t, tc.ExpectedString, distance.String())
+			}
+		})
+	}
+}
+
+func TestGetCommitCount(t *testing.T) {
+	newRelease := func(version string) *v1alpha1.Release {
+		return &v1alpha1.Release{
+			Spec:    v1alpha1.ReleaseSpec{Version: version},
+			Project: &v1alpha1.Project{ProjectMetadata: v1alpha1.ProjectMetadata{Name: "backend"}},
+		}
+	}
+
+	provider := &info.ProviderMock{
+		GetReleaseGitTagFunc: func(release *v1alpha1.Release) (string, error) {
+			return "v" + release.Spec.Version, nil
+		},
+		GetProjectSourceDirFunc: func(project *v1alpha1.Project) (string, error) {
+			return "/repos/" + project.Name, nil
+		},
+		GetCommitsMetadataFunc: func(projectDir, fromTag, toTag string) ([]*info.CommitMetadata, error) {
+			return make([]*info.CommitMetadata, 3), nil
+		},
+	}
+
+	count := getCommitCount(provider, newRelease("1.0.0"), newRelease("1.1.0"))
+	require.NotNil(t, count)
+	require.Equal(t, 3, *count)
+
+	calls := provider.GetCommitsMetadataCalls()
+	require.Len(t, calls, 1)
+	require.Equal(t, "/repos/backend", calls[0].ProjectDir)
+	require.Equal(t, "v1.0.0", calls[0].FromTag)
+	require.Equal(t, "v1.1.0", calls[0].ToTag)
+
+	count = getCommitCount(provider, newRelease("1.1.0"), newRelease("1.1.0"))
+	require.NotNil(t, count)
+	require.Equal(t, 0, *count)
+	require.Len(t, provider.GetCommitsMetadataCalls(), 1)
+
+	require.Equal(t, "2 minor behind · 3 commits", Distance{Minor: -2, Commits: ptr(3)}.String())
+}
+
+func TestFormatAge(t *testing.T) {
+	require.Equal(t, "<1h", formatAge(30*time.Minute))
+	require.Equal(t, "5h", formatAge(5*time.Hour+10*time.Minute))
+	require.Equal(t, "3d", formatAge(80*time.Hour))
+}
+
+func TestGetVersionUpdatedAt(t *testing.T) {
+	dir := t.TempDir()
+	run := func(date string, args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=joy", "GIT_AUTHOR_EMAIL=joy@example.com", "GIT_AUTHOR_DATE="+date,
+			"GIT_COMMITTER_NAME=joy", "GIT_COMMITTER_EMAIL=joy@example.com", "GIT_COMMITTER_DATE="+date)
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+	}
+	commit := func(date, version, chartVersion, valuesVersion string) {
+		content := fmt.Sprintf("spec:\n  version: %s\n  chart:\n    version: %s\n  values:\n    version: %s\n", version, chartVersion, valuesVersion)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "release.yaml"), []byte(content), 0o644))
+		run(date, "add", ".")
+		run(date, "commit", "-m", "update")
+	}
+
+	run("2024-01-01T00:00:00Z", "init")
+	commit("2024-01-01T00:00:00Z", "1.0.0", "1.0.0", "a")
+	commit("2024-01-02T00:00:00Z", "1.1.0", "1.0.0", "a")
+	commit("2024-01-03T00:00:00Z", "1.1.0", "2.0.0", "a")
+	commit("2024-01-04T00:00:00Z", "1.1.0", "2.0.0", "b")
+
+	rel := &v1alpha1.Release{File: &yml.File{Path: filepath.Join(dir, "release.yaml")}}
+	updatedAt := getVersionUpdatedAt(dir, rel)
+	require.NotNil(t, updatedAt)
+	require.Equal(t, "2024-01-02T00:00:00Z", updatedAt.UTC().Format(time.RFC3339))
+
+	rel = &v1alpha1.Release{File: &yml.File{Path: filepath.Join(dir, "missing.yaml")}}
+	require.Nil(t, getVersionUpdatedAt(dir, rel))
+}
+
+func ptr[T any](value T) *T {
+	return &value
+}
@@ -0,0 +1,66 @@
+package promote
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"golang.org/x/mod/semver"
+
+	"github.com/nestoca/joy/internal/release/cross"
+)
+
+// getDependencyViolations returns a description of each dependency of the selected releases that is still behind in
+// target environment and is not itself being promoted, such that the selected release would get ahead of it.
+// Both lists are expected to only have source and target environments, and list is expected to include all releases
+// of both environments, such that dependencies outside of selection are still checked. Dependencies absent from the
+// source environment are not considered.
+func getDependencyViolations(list, selectedList cross.ReleaseList) []string {
+	var violations []string
+	for _, item := range selectedList.Items {
+		source := at(item.Releases, sourceEnvIndex)
+		if source == nil || item.PromotedFile == nil || item.VersionInSync || item.NotPromotableReason != "" {
+			continue
+		}
+
+		for _, dependency := range source.Spec.DependsOn {
+			index := slices.IndexFunc(list.Items, func(release *cross.Release) bool { return release.Name == dependency })
+			if index == -1 {
+				continue
+			}
+			dependencyItem := list.Items[index]
+
+			// Dependencies promoted along with release will reach target at the same time, unless skipped
+			if slices.ContainsFunc(selectedList.Items, func(release *cross.Release) bool {
+				return release.Name == dependency && !release.VersionInSync && release.NotPromotableReason == ""
+			}) {
+				continue
+			}
+
+			dependencySource := at(dependencyItem.Releases, sourceEnvIndex)
+			dependencyTarget := at(dependencyItem.Releases, targetEnvIndex)
+			if dependencySource == nil {
+				continue
+			}
+			if dependencyTarget == nil {
+				violations = append(violations, fmt.Sprintf("%s depends on %s, which is missing in target environment", item.Name, dependency))
+				continue
+			}
+			if isVersionBehind(dependencyTarget.Spec.Version, dependencySource.Spec.Version) {
+				violations = append(violations, fmt.Sprintf("%s depends on %s, which is behind in target environment (%s < %s)",
+					item.Name, dependency, dependencyTarget.Spec.Version, dependencySource.Spec.Version))
+			}
+		}
+	}
+	return violations
+}
+
+// isVersionBehind returns true if version is lower than reference version, or simply different from it when either
+// of them is not a valid semantic version.
+func isVersionBehind(version, reference string) bool {
+	version, reference = "v"+strings.TrimPrefix(version, "v"), "v"+strings.TrimPrefix(reference, "v")
+	if !semver.IsValid(version) || !semver.IsValid(reference) {
+		return version != reference
+	}
+	return semver.Compare(version, reference) < 0
+}
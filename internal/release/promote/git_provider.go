@@ -4,4 +4,5 @@ package promote
 type GitProvider interface {
 	CreateAndPushBranchWithFiles(branchName string, files []string, message string) error
 	CheckoutMasterBranch() error
+	DiscardChanges(files []string) error
 }
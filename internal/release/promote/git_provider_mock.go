@@ -23,6 +23,9 @@ var _ GitProvider = &GitProviderMock{}
 //			CreateAndPushBranchWithFilesFunc: func(branchName string, files []string, message string) error {
 //				panic("mock out the CreateAndPushBranchWithFiles method")
 //			},
+//			DiscardChangesFunc: func(files []string) error {
+//				panic("mock out the DiscardChanges method")
+//			},
 //		}
 //
 //		// use mockedGitProvider in code that requires GitProvider
@@ -36,6 +39,9 @@ type GitProviderMock struct {
 	// CreateAndPushBranchWithFilesFunc mocks the CreateAndPushBranchWithFiles method.
 	CreateAndPushBranchWithFilesFunc func(branchName string, files []string, message string) error
 
+	// DiscardChangesFunc mocks the DiscardChanges method.
+	DiscardChangesFunc func(files []string) error
+
 	// calls tracks calls to the methods.
 	calls struct {
 		// CheckoutMasterBranch holds details about calls to the CheckoutMasterBranch method.
@@ -50,9 +56,15 @@ type GitProviderMock struct {
 			// Message is the message argument value.
 			Message string
 		}
+		// DiscardChanges holds details about calls to the DiscardChanges method.
+		DiscardChanges []struct {
+			// Files is the files argument value.
+			Files []string
+		}
 	}
 	lockCheckoutMasterBranch         sync.RWMutex
 	lockCreateAndPushBranchWithFiles sync.RWMutex
+	lockDiscardChanges               sync.RWMutex
 }
 
 // CheckoutMasterBranch calls CheckoutMasterBranchFunc.
@@ -127,3 +139,38 @@ func (mock *GitProviderMock) CreateAndPushBranchWithFilesCalls() []struct {
 	mock.lockCreateAndPushBranchWithFiles.RUnlock()
 	return calls
 }
+
+// DiscardChanges calls DiscardChangesFunc.
+func (mock *GitProviderMock) DiscardChanges(files []string) error {
+	callInfo := struct {
+		Files []string
+	}{
+		Files: files,
+	}
+	mock.lockDiscardChanges.Lock()
+	mock.calls.DiscardChanges = append(mock.calls.DiscardChanges, callInfo)
+	mock.lockDiscardChanges.Unlock()
+	if mock.DiscardChangesFunc == nil {
+		var (
+			errOut error
+		)
+		return errOut
+	}
+	return mock.DiscardChangesFunc(files)
+}
+
+// DiscardChangesCalls gets all the calls that were made to DiscardChanges.
+// Check the length with:
+//
+//	len(mockedGitProvider.DiscardChangesCalls())
+func (mock *GitProviderMock) DiscardChangesCalls() []struct {
+	Files []string
+} {
+	var calls []struct {
+		Files []string
+	}
+	mock.lockDiscardChanges.RLock()
+	calls = mock.calls.DiscardChanges
+	mock.lockDiscardChanges.RUnlock()
+	return calls
+}
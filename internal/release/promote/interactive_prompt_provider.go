@@ -10,10 +10,12 @@ import (
 	"github.com/hexops/gotextdiff"
 	"github.com/hexops/gotextdiff/myers"
 	"github.com/hexops/gotextdiff/span"
+	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/nestoca/survey/v2"
 	"github.com/nestoca/survey/v2/core"
 
 	"github.com/nestoca/joy/api/v1alpha1"
+	"github.com/nestoca/joy/internal/git/pr"
 	"github.com/nestoca/joy/internal/release/cross"
 	"github.com/nestoca/joy/internal/style"
 	"github.com/nestoca/joy/internal/yml"
@@ -197,16 +199,17 @@ func (i *InteractivePromptProvider) ConfirmCreatingPromotionPullRequest(autoMerg
 }
 
 const (
-	CreatePR    = "Create PR"
-	CreateDraft = "Create Draft PR"
-	ViewGitLog  = "View Git Log"
-	Cancel      = "Cancel"
+	CreatePR      = "Create PR"
+	CreateDraft   = "Create Draft PR"
+	SelectChanges = "Select Changes"
+	ViewGitLog    = "View Git Log"
+	Cancel        = "Cancel"
 )
 
 func (i *InteractivePromptProvider) SelectPromotionAction() (string, error) {
 	var selectedAction string
 
-	actions := []string{CreatePR, CreateDraft, ViewGitLog, Cancel}
+	actions := []string{CreatePR, CreateDraft, SelectChanges, ViewGitLog, Cancel}
 	prompt := &survey.Select{
 		Message: "What would you like to do?",
 		Options: actions,
@@ -220,6 +223,50 @@ func (i *InteractivePromptProvider) SelectPromotionAction() (string, error) {
 	return selectedAction, nil
 }
 
+func (i *InteractivePromptProvider) SelectChanges(paths []string) ([]string, error) {
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no changes found in existing target releases")
+	}
+
+	var selectedPaths []string
+	err := survey.AskOne(&survey.MultiSelect{
+		Message: "Select changes to promote",
+		Options: paths,
+		Default: paths,
+	},
+		&selectedPaths,
+		survey.WithPageSize(20),
+		survey.WithKeepFilter(true),
+		survey.WithValidator(survey.Required),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("prompting for changes to promote: %w", err)
+	}
+	return selectedPaths, nil
+}
+
+const (
+	SupersedePRs   = "Supersede existing PR(s)"
+	CreatePRAnyway = "Create PR anyway"
+)
+
+func (i *InteractivePromptProvider) SelectConflictAction() (string, error) {
+	var selectedAction string
+
+	actions := []string{SupersedePRs, CreatePRAnyway, Cancel}
+	prompt := &survey.Select{
+		Message: "What would you like to do?",
+		Options: actions,
+	}
+
+	err := survey.AskOne(prompt, &selectedAction)
+	if err != nil {
+		return "", fmt.Errorf("asking user for conflict action: %w", err)
+	}
+
+	return selectedAction, nil
+}
+
 func (*InteractivePromptProvider) ConfirmAutoMergePullRequest() (answer bool, err error) {
 	err = survey.AskOne(&survey.Confirm{Message: "Do you want to auto-merge the resulting PR?"}, &answer)
 	return
@@ -337,6 +384,34 @@ func (i *InteractivePromptProvider) PrintPullRequestCreated(url string) {
 	i.printf("✅ Created pull request: %s\n", style.Link(url))
 }
 
+func (i *InteractivePromptProvider) PrintConflictingPullRequests(targetEnvName string, pullRequests []pr.PullRequest) {
+	i.printf("⚠️ Found open pull request(s) already promoting some of the same releases to %s:\n", style.Resource(targetEnvName))
+	for _, pullRequest := range pullRequests {
+		i.printf("- %s %s\n", style.Link(pullRequest.URL), style.SecondaryInfo(pullRequest.Title))
+	}
+}
+
+func (i *InteractivePromptProvider) PrintPullRequestSuperseded(url string) {
+	i.printf("✅ Closed superseded pull request: %s\n", style.Link(url))
+}
+
+func (i *InteractivePromptProvider) PrintPromotionSummary(results []PromotionResult) {
+	t := table.NewWriter()
+	t.SetStyle(table.StyleRounded)
+	t.AppendHeader(table.Row{"RELEASE", "PULL REQUEST"})
+	for _, result := range results {
+		outcome := style.Link(result.URL)
+		switch {
+		case result.Error != nil:
+			outcome = style.Warning(result.Error.Error())
+		case result.URL == "":
+			outcome = style.SecondaryInfo("-")
+		}
+		t.AppendRow(table.Row{style.Resource(result.Release), outcome})
+	}
+	i.println(t.Render())
+}
+
 func (i *InteractivePromptProvider) PrintCanceled() {
 	i.println("🛑 Operation cancelled, no harm done! 😅")
 }
@@ -0,0 +1,43 @@
+package promote
+
+import (
+	"fmt"
+
+	"github.com/nestoca/joy/api/v1alpha1"
+	"github.com/nestoca/joy/internal/yml"
+)
+
+// Mode determines which parts of source releases get promoted to target environment.
+type Mode string
+
+const (
+	// ModeFull promotes both version and values. It is the default mode.
+	ModeFull Mode = ""
+
+	// ModeVersionOnly promotes only the release version, leaving values untouched in target.
+	ModeVersionOnly Mode = "version-only"
+
+	// ModeValuesOnly promotes everything but the release version, leaving version untouched in target.
+	ModeValuesOnly Mode = "values-only"
+)
+
+func (mode Mode) Validate() error {
+	switch mode {
+	case ModeFull, ModeVersionOnly, ModeValuesOnly:
+		return nil
+	default:
+		return fmt.Errorf("unknown promotion mode: %s", mode)
+	}
+}
+
+// Scope returns the merge scope used to compute promoted files in this mode.
+func (mode Mode) Scope() yml.MergeScope {
+	switch mode {
+	case ModeVersionOnly:
+		return yml.MergeScope{Only: []string{v1alpha1.ReleaseVersionPath}}
+	case ModeValuesOnly:
+		return yml.MergeScope{Except: []string{v1alpha1.ReleaseVersionPath}}
+	default:
+		return yml.MergeScope{}
+	}
+}
@@ -4,11 +4,14 @@ import (
 	"cmp"
 	"errors"
 	"fmt"
+	"regexp"
+	"slices"
 	"sort"
 	"strings"
 
 	"github.com/google/uuid"
 
+	"github.com/nestoca/joy/api/v1alpha1"
 	"github.com/nestoca/joy/internal/git/pr"
 	"github.com/nestoca/joy/internal/info"
 	"github.com/nestoca/joy/internal/links"
@@ -17,7 +20,10 @@ import (
 )
 
 const (
-	defaultCommitAndPRTemplate = `Promote {{ len .Releases }} releases ({{ .SourceEnvironment.Name }} -> {{ .TargetEnvironment.Name }})`
+	// breakingChangeLabel is added to promotion PRs whose changelog contains breaking changes.
+	breakingChangeLabel = "breaking-change"
+
+	defaultCommitAndPRTemplate = `Promote {{ len .Releases }} releases ({{ .SourceEnvironment.Name }} -> {{ .TargetEnvironment.Name }}){{ with .Mode }} [{{ . }}]{{ end }}{{ with .Paths }} [{{ join ", " . }}]{{ end }}`
 )
 
 type PerformOpts struct {
@@ -26,13 +32,85 @@ type PerformOpts struct {
 	draft               bool
 	dryRun              bool
 	localOnly           bool
+	noPrompt            bool
+	supersede           bool
+	split               bool
+	mode                Mode
+	paths               []string
 	commitTemplate      string
 	pullRequestTemplate string
 	templateVariables   map[string]string
+	issueKeyPattern     *regexp.Regexp
 	infoProvider        info.Provider
 	linksProvider       links.Provider
 }
 
+// PromotionResult describes the outcome of promoting a single release in split mode.
+type PromotionResult struct {
+	Release string
+	URL     string
+	Error   error
+}
+
+// performAll performs the promotion of all releases in given list, either with a single PR or, in split mode,
+// with one PR per release, and returns PR urls if any, one per line.
+func (p *Promotion) performAll(opts PerformOpts) (string, error) {
+	if !opts.split {
+		return p.perform(opts)
+	}
+
+	var (
+		results []PromotionResult
+		urls    []string
+		errs    []error
+	)
+	// List is already sorted such that dependencies get their pull requests first
+	for _, crossRelease := range opts.list.Items {
+		if crossRelease.PromotedFile == nil {
+			continue
+		}
+
+		p.println(style.SecondaryInfo("--- " + crossRelease.Name))
+
+		releaseOpts := opts
+		releaseOpts.list = opts.list.Filter(func(release *cross.Release) bool {
+			return release == crossRelease
+		})
+
+		url, err := p.perform(releaseOpts)
+		if err != nil {
+			err = fmt.Errorf("promoting release %s: %w", crossRelease.Name, err)
+			errs = append(errs, err)
+			p.printf("⚠️ %v\n", err)
+
+			// Make sure the next release does not get promoted on top of this one's branch or uncommitted changes
+			if !opts.dryRun && !opts.localOnly {
+				if err := p.GitProvider.CheckoutMasterBranch(); err != nil {
+					errs = append(errs, fmt.Errorf("checking out master: %w", err))
+					break
+				}
+				if err := p.GitProvider.DiscardChanges([]string{crossRelease.PromotedFile.Path}); err != nil {
+					errs = append(errs, fmt.Errorf("discarding changes to %s: %w", crossRelease.PromotedFile.Path, err))
+					break
+				}
+			}
+		}
+		if url != "" {
+			urls = append(urls, url)
+		}
+
+		results = append(results, PromotionResult{
+			Release: crossRelease.Name,
+			URL:     url,
+			Error:   err,
+		})
+	}
+
+	p.PromptProvider.PrintPromotionSummary(results)
+
+	return strings.Join(urls, "\n"), errors.Join(errs...)
+}
+
 // perform performs the promotion of all releases in given list and returns PR url if any
 func (p *Promotion) perform(opts PerformOpts) (string, error) {
 	if len(opts.list.Environments) != 2 {
@@ -42,9 +120,41 @@ func (p *Promotion) perform(opts PerformOpts) (string, error) {
 	sourceEnv := opts.list.Environments[0]
 	targetEnv := opts.list.Environments[1]
 
+	var supersededPullRequests []pr.PullRequest
+	if !opts.dryRun && !opts.localOnly {
+		conflicts, err := p.getConflictingPullRequests(opts.list, targetEnv)
+		if err != nil {
+			return "", fmt.Errorf("getting conflicting pull requests: %w", err)
+		}
+
+		if len(conflicts) > 0 {
+			p.PromptProvider.PrintConflictingPullRequests(targetEnv.Name, conflicts)
+
+			action := CreatePRAnyway
+			if opts.supersede {
+				action = SupersedePRs
+			} else if !opts.noPrompt {
+				action, err = p.PromptProvider.SelectConflictAction()
+				if err != nil {
+					return "", fmt.Errorf("selecting conflict action: %w", err)
+				}
+			}
+
+			switch action {
+			case SupersedePRs:
+				supersededPullRequests = p.getSupersededPullRequests(opts.list, conflicts)
+			case Cancel:
+				p.PromptProvider.PrintCanceled()
+				return "", nil
+			}
+		}
+	}
+
 	info := &PromotionInfo{
 		SourceEnvironment: sourceEnv,
 		TargetEnvironment: targetEnv,
+		Mode:              opts.mode,
+		Paths:             opts.paths,
 		Variables:         opts.templateVariables,
 	}
 
@@ -119,13 +229,17 @@ func (p *Promotion) perform(opts PerformOpts) (string, error) {
 	}
 
 	var labels []string
-	labels = append(labels, "environment:"+info.TargetEnvironment.Name)
+	labels = append(labels, pr.EnvironmentLabel(info.TargetEnvironment.Name))
 	for _, release := range info.Releases {
-		labels = append(labels, "release:"+release.Name)
+		labels = append(labels, pr.ReleaseLabel(release.Name))
 	}
 
 	if opts.autoMerge {
-		labels = append(labels, "auto-merge")
+		labels = append(labels, pr.AutoMergeLabel)
+	}
+
+	if info.HasBreakingChanges() {
+		labels = append(labels, breakingChangeLabel)
 	}
 
 	pullRequestTemplate := cmp.Or(opts.pullRequestTemplate, defaultCommitAndPRTemplate)
@@ -170,6 +284,14 @@ func (p *Promotion) perform(opts PerformOpts) (string, error) {
 		p.PromptProvider.PrintPullRequestCreated(prURL)
 	}
 
+	for _, superseded := range supersededPullRequests {
+		if err := p.PullRequestProvider.Close(superseded.Number, "Superseded by "+prURL); err != nil {
+			p.printf("⚠️ Failed to close superseded pull request %s: %v\n", style.Link(superseded.URL), err)
+			continue
+		}
+		p.PromptProvider.PrintPullRequestSuperseded(superseded.URL)
+	}
+
 	if err := p.GitProvider.CheckoutMasterBranch(); err != nil {
 		return "", fmt.Errorf("checking out master: %w", err)
 	}
@@ -179,6 +301,55 @@ func (p *Promotion) perform(opts PerformOpts) (string, error) {
 	return prURL, nil
 }
 
+// getConflictingPullRequests returns the open pull requests already promoting any of the releases to promote
+// in given list to given target environment.
+func (p *Promotion) getConflictingPullRequests(list cross.ReleaseList, targetEnv *v1alpha1.Environment) ([]pr.PullRequest, error) {
+	pullRequests, err := p.PullRequestProvider.ListOpen(pr.EnvironmentLabel(targetEnv.Name))
+	if err != nil {
+		return nil, err
+	}
+
+	var conflicts []pr.PullRequest
+	for _, pullRequest := range pullRequests {
+		for _, crossRelease := range list.Items {
+			if crossRelease.PromotedFile != nil && slices.Contains(pullRequest.Labels, pr.ReleaseLabel(crossRelease.Name)) {
+				conflicts = append(conflicts, pullRequest)
+				break
+			}
+		}
+	}
+	return conflicts, nil
+}
+
+// getSupersededPullRequests returns the conflicting pull requests that only promote releases also promoted in given
+// list, and which can therefore be closed in favor of the new one. Others also promote releases that would otherwise
+// be silently dropped, so they are left open with a warning.
+func (p *Promotion) getSupersededPullRequests(list cross.ReleaseList, conflicts []pr.PullRequest) []pr.PullRequest {
+	promoted := make(map[string]bool)
+	for _, crossRelease := range list.Items {
+		if crossRelease.PromotedFile != nil {
+			promoted[crossRelease.Name] = true
+		}
+	}
+
+	var superseded []pr.PullRequest
+	for _, pullRequest := range conflicts {
+		var others []string
+		for _, label := range pullRequest.Labels {
+			if release, ok := pr.ParseReleaseLabel(label); ok && !promoted[release] {
+				others = append(others, release)
+			}
+		}
+		if len(others) > 0 {
+			p.printf("⚠️ Leaving pull request %s open, as it also promotes other releases: %s\n",
+				style.Link(pullRequest.URL), style.Resource(strings.Join(others, ", ")))
+			continue
+		}
+		superseded = append(superseded, pullRequest)
+	}
+	return superseded
+}
+
 func getReviewers(info *PromotionInfo) []string {
 	uniqueAuthors := make(map[string]bool)
 	for _, release := range info.Releases {
@@ -10,6 +10,7 @@ import (
 	"golang.org/x/mod/semver"
 
 	"github.com/nestoca/joy/api/v1alpha1"
+	"github.com/nestoca/joy/internal/changelog"
 	"github.com/nestoca/joy/internal/release/cross"
 )
 
@@ -34,6 +35,7 @@ type ReleaseInfo struct {
 	ValuesChanged bool
 	ChangeType    ChangeType
 	Commits       []*CommitInfo
+	Changelog     *changelog.Changelog
 	Error         error
 }
 
@@ -48,10 +50,22 @@ type PromotionInfo struct {
 	SourceEnvironment *v1alpha1.Environment
 	TargetEnvironment *v1alpha1.Environment
 	Releases          []*ReleaseInfo
+	Mode              Mode
+	Paths             []string
 	Variables         map[string]string
 	Error             error
 }
 
+// HasBreakingChanges returns true if the changelog of any promoted release contains breaking changes.
+func (info *PromotionInfo) HasBreakingChanges() bool {
+	for _, release := range info.Releases {
+		if release.Changelog.HasBreakingChanges() {
+			return true
+		}
+	}
+	return false
+}
+
 type CommitInfo struct {
 	Sha          string
 	ShortSha     string
@@ -93,12 +107,7 @@ func getReleaseInfo(cross *cross.Release, sourceRelease, targetRelease *v1alpha1
 		}
 	}
 
-	olderTag := targetTag
-	newerTag := sourceTag
-	if changeType == ChangeTypeDowngrade {
-		olderTag = sourceTag
-		newerTag = targetTag
-	}
+	commitRange := changelog.NewRange(sourceRelease, targetRelease, sourceTag, targetTag)
 
 	sourceLinks, err := opts.linksProvider.GetReleaseLinks(sourceRelease)
 	if err != nil {
@@ -117,12 +126,13 @@ func getReleaseInfo(cross *cross.Release, sourceRelease, targetRelease *v1alpha1
 		Repository:    repository,
 		Source:        EnvironmentReleaseInfo{Release: sourceRelease, DisplayVersion: sourceRelease.Spec.Version, GitTag: sourceTag, Links: sourceLinks},
 		Target:        EnvironmentReleaseInfo{Release: targetRelease, DisplayVersion: displayTargetVersion, GitTag: targetTag, Links: targetLinks},
-		OlderGitTag:   olderTag,
-		NewerGitTag:   newerTag,
+		OlderGitTag:   commitRange.OlderTag,
+		NewerGitTag:   commitRange.NewerTag,
 		IsPrerelease:  IsPrerelease(sourceRelease) || IsPrerelease(targetRelease),
 		ValuesChanged: cross.PromotedFile != nil && !cross.ValuesInSync,
 		ChangeType:    changeType,
 		Commits:       []*CommitInfo{},
+		Changelog:     &changelog.Changelog{},
 		Error:         nil,
 	}
 
@@ -130,17 +140,12 @@ func getReleaseInfo(cross *cross.Release, sourceRelease, targetRelease *v1alpha1
 		return &releaseInfo, nil
 	}
 
-	projectDir, err := opts.infoProvider.GetProjectSourceDir(project)
+	commitsMetadata, err := commitRange.GetCommitsMetadata(opts.infoProvider, project)
 	if err != nil {
-		return nil, fmt.Errorf("getting project clone: %w", err)
+		return nil, err
 	}
 
-	commitsMetadata, err := opts.infoProvider.GetCommitsMetadata(projectDir, olderTag, newerTag)
-	if err != nil {
-		return nil, fmt.Errorf("getting commits metadata: %w", err)
-	}
-
-	gitHubAuthors, err := opts.infoProvider.GetCommitsGitHubAuthors(project, olderTag, newerTag)
+	gitHubAuthors, err := opts.infoProvider.GetCommitsGitHubAuthors(project, commitRange.OlderTag, commitRange.NewerTag)
 	if err != nil {
 		return nil, fmt.Errorf("getting GitHub authors: %w", err)
 	}
@@ -164,6 +169,8 @@ func getReleaseInfo(cross *cross.Release, sourceRelease, targetRelease *v1alpha1
 		})
 	}
 
+	releaseInfo.Changelog = changelog.New(commitsMetadata, opts.issueKeyPattern)
+
 	return &releaseInfo, nil
 }
 
@@ -3,6 +3,8 @@ package promote
 import (
 	"fmt"
 	"io"
+	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/nestoca/joy/api/v1alpha1"
@@ -23,6 +25,7 @@ type Promotion struct {
 	CommitTemplate      string
 	PullRequestTemplate string
 	TemplateVariables   map[string]string
+	IssueKeyPattern     *regexp.Regexp
 	InfoProvider        info.Provider
 	LinksProvider       links.Provider
 	Out                 io.Writer
@@ -42,6 +45,11 @@ type Opts struct {
 	// If there is more than one, no need to prompt the user to select releases.
 	Releases []string
 
+	// ReleaseSelection limits the releases offered for selection, when neither Releases nor All are specified,
+	// typically to those selected in user config. Unlike filtering the catalog, dependencies of selected releases
+	// are still checked against all releases.
+	ReleaseSelection []string
+
 	ReleasesFiltered bool
 
 	// NoPrompt means that the Promote function should avoid interactive prompts at all costs.
@@ -71,11 +79,30 @@ type Opts struct {
 	// LocalOnly indicates if the promotion should only write the promotion changes to the working tree without creating a branch, commit or pull request.
 	LocalOnly bool
 
+	// Mode determines whether to promote version and values together (default), or only one of them.
+	Mode Mode
+
+	// Supersede indicates that open pull requests already promoting some of the same releases to the same
+	// target environment should be closed in favor of the new one, without prompting. Pull requests also
+	// promoting other releases are left open.
+	Supersede bool
+
+	// Split indicates that one pull request should be created per release, instead of a single one for all releases.
+	Split bool
+
+	// Paths restricts the promotion to the given dot-separated value paths, such as "spec.values.featureFlags".
+	// It cannot be combined with a Mode other than ModeFull.
+	Paths []string
+
+	// IgnoreDependencies only warns about, rather than fails on, releases promoted ahead of dependencies that are
+	// still behind in target environment.
+	IgnoreDependencies bool
+
 	MaxColumnWidth int
 }
 
 // Promote prompts user to select source and target environments and releases to promote and creates a pull request,
-// returning its URL if any.
+// returning its URL if any. In split mode, one pull request is created per release and their URLs are returned one per line.
 func (p *Promotion) Promote(opts Opts) (string, error) {
 	if opts.DryRun {
 		p.println("ℹ️ Dry-run mode enabled: No changes will be made.")
@@ -85,6 +112,25 @@ func (p *Promotion) Promote(opts Opts) (string, error) {
 		p.println("ℹ️ Local-only mode enabled: The local repo will be modified, but not committed. No pull request will be created.")
 	}
 
+	if err := opts.Mode.Validate(); err != nil {
+		return "", err
+	}
+
+	if len(opts.Paths) > 0 && opts.Mode != ModeFull {
+		return "", fmt.Errorf("paths cannot be combined with %s mode", opts.Mode)
+	}
+
+	switch opts.Mode {
+	case ModeVersionOnly:
+		p.println("ℹ️ Version-only mode enabled: Only release versions will be promoted, values will be left untouched.")
+	case ModeValuesOnly:
+		p.println("ℹ️ Values-only mode enabled: Release versions will be left untouched, only values will be promoted.")
+	}
+
+	if len(opts.Paths) > 0 {
+		p.printf("ℹ️ Only the following paths will be promoted: %s\n", strings.Join(opts.Paths, ", "))
+	}
+
 	// Prompt user to select source environment
 	if opts.SourceEnv == nil {
 		sourceEnvs, err := getSourceEnvironments(opts.SelectedEnvironments)
@@ -118,7 +164,7 @@ func (p *Promotion) Promote(opts Opts) (string, error) {
 		return "", fmt.Errorf("environment %s is not promotable to %s", opts.SourceEnv.Name, opts.TargetEnv.Name)
 	}
 
-	list, err := opts.Catalog.Releases.GetReleasesForPromotion(opts.SourceEnv, opts.TargetEnv)
+	list, err := opts.Catalog.Releases.GetReleasesForPromotion(opts.SourceEnv, opts.TargetEnv, opts.scope())
 	if err != nil {
 		return "", fmt.Errorf("getting releases for promotion: %w", err)
 	}
@@ -130,6 +176,11 @@ func (p *Promotion) Promote(opts Opts) (string, error) {
 		if len(opts.Releases) > 0 {
 			return list.OnlySpecificReleases(opts.Releases)
 		}
+		if len(opts.ReleaseSelection) > 0 {
+			return p.PromptProvider.SelectReleases(list.Filter(func(release *cross.Release) bool {
+				return slices.Contains(opts.ReleaseSelection, release.Name)
+			}), opts.MaxColumnWidth)
+		}
 		return p.PromptProvider.SelectReleases(list, opts.MaxColumnWidth)
 	}()
 	if err != nil {
@@ -147,6 +198,18 @@ func (p *Promotion) Promote(opts Opts) (string, error) {
 		})
 	}
 
+	if len(opts.Paths) > 0 {
+		if err := validatePaths(selectedList, opts.Paths); err != nil {
+			return "", err
+		}
+	}
+
+	for _, release := range selectedList.Items {
+		if release.NotPromotableReason != "" {
+			p.printf("⚠️ Skipping release %s: %s\n", style.Resource(release.Name), release.NotPromotableReason)
+		}
+	}
+
 	if !selectedList.HasAnyPromotableReleases() {
 		p.PromptProvider.PrintNoPromotableReleasesFound(opts.ReleasesFiltered, opts.SourceEnv, opts.TargetEnv)
 		return "", nil
@@ -159,6 +222,16 @@ func (p *Promotion) Promote(opts Opts) (string, error) {
 		return "", fmt.Errorf("cannot promote releases with non-standard version to %s environment", opts.TargetEnv.Name)
 	}
 
+	if err := p.checkDependencies(opts, list, selectedList); err != nil {
+		return "", err
+	}
+
+	// Dependencies come first, such that they get merged first when split into multiple pull requests
+	selectedList, err = selectedList.SortByDependencies()
+	if err != nil {
+		return "", err
+	}
+
 	if !opts.NoPrompt {
 		if err := p.preview(selectedList); err != nil {
 			return "", fmt.Errorf("previewing: %w", err)
@@ -172,15 +245,21 @@ func (p *Promotion) Promote(opts Opts) (string, error) {
 		draft:               opts.Draft,
 		dryRun:              opts.DryRun,
 		localOnly:           opts.LocalOnly,
+		noPrompt:            opts.NoPrompt,
+		supersede:           opts.Supersede,
+		split:               opts.Split,
+		mode:                opts.Mode,
+		paths:               opts.Paths,
 		commitTemplate:      p.CommitTemplate,
 		pullRequestTemplate: p.PullRequestTemplate,
 		templateVariables:   p.TemplateVariables,
+		issueKeyPattern:     p.IssueKeyPattern,
 		infoProvider:        p.InfoProvider,
 		linksProvider:       p.LinksProvider,
 	}
 
 	if opts.NoPrompt {
-		return p.perform(performParams)
+		return p.performAll(performParams)
 	}
 
 	if opts.AutoMerge || opts.Draft {
@@ -193,9 +272,13 @@ func (p *Promotion) Promote(opts Opts) (string, error) {
 			return "", nil
 		}
 
-		return p.perform(performParams)
+		return p.performAll(performParams)
 	}
 
+	// Keep track of releases selected prior to any interactive selection of changes,
+	// so that the user can change their mind about which changes to include.
+	releasesBeforeChangeSelection := selectedList
+
 loop:
 	for {
 		// Prompt user to select creating a pull request
@@ -210,6 +293,36 @@ loop:
 		case CreateDraft:
 			performParams.draft = true
 			break loop
+		case SelectChanges:
+			paths, err := p.PromptProvider.SelectChanges(releasesBeforeChangeSelection.GetChangedPaths())
+			if err != nil {
+				return "", fmt.Errorf("selecting changes to promote: %w", err)
+			}
+
+			selectedList, err = restrictToPaths(opts, releasesBeforeChangeSelection, paths)
+			if err != nil {
+				return "", fmt.Errorf("restricting promotion to selected changes: %w", err)
+			}
+			if err := p.checkDependencies(opts, list, selectedList); err != nil {
+				return "", err
+			}
+			selectedList, err = selectedList.SortByDependencies()
+			if err != nil {
+				return "", err
+			}
+
+			if !selectedList.HasAnyPromotableReleases() {
+				p.PromptProvider.PrintNoPromotableReleasesFound(true, opts.SourceEnv, opts.TargetEnv)
+				return "", nil
+			}
+
+			performParams.list = selectedList
+			performParams.paths = paths
+
+			if err := p.preview(selectedList); err != nil {
+				return "", fmt.Errorf("previewing: %w", err)
+			}
+
 		case ViewGitLog:
 			for _, crossRelease := range selectedList.Items {
 				p.println(style.SecondaryInfo("--- " + crossRelease.Name))
@@ -238,7 +351,79 @@ loop:
 		}
 	}
 
-	return p.perform(performParams)
+	return p.performAll(performParams)
+}
+
+// checkDependencies returns an error if any of the selected releases would get ahead of its dependencies still behind
+// in target environment, or only warns about it when ignoring dependencies.
+func (p *Promotion) checkDependencies(opts Opts, list, selectedList cross.ReleaseList) error {
+	violations := getDependencyViolations(list, selectedList)
+	if len(violations) == 0 {
+		return nil
+	}
+	if !opts.IgnoreDependencies {
+		return fmt.Errorf("cannot promote releases ahead of their dependencies (promote dependencies first, along with them, or use --ignore-dependencies):\n  - %s", strings.Join(violations, "\n  - "))
+	}
+	for _, violation := range violations {
+		p.printf("⚠️ %s\n", violation)
+	}
+	return nil
+}
+
+// scope returns the merge scope to use for computing promoted files based on promotion mode or paths.
+func (opts Opts) scope() yml.MergeScope {
+	if len(opts.Paths) > 0 {
+		return yml.MergeScope{Only: opts.Paths}
+	}
+	return opts.Mode.Scope()
+}
+
+// validatePaths returns an error if any of given paths cannot be found in either the source or target environment of
+// any of the releases in given list, as it would otherwise silently promote nothing, or if any of those releases is
+// missing from target environment, as the whole release would then be promoted regardless of paths. Paths only found
+// in target are valid, as promoting them removes them from target.
+func validatePaths(list cross.ReleaseList, paths []string) error {
+	found := make(map[string]bool, len(paths))
+	for _, item := range list.Items {
+		source, target := at(item.Releases, sourceEnvIndex), at(item.Releases, targetEnvIndex)
+		if source == nil || source.File == nil {
+			continue
+		}
+		if target == nil || target.File == nil {
+			return fmt.Errorf("cannot promote only given paths of release %s, as it does not exist in target environment", item.Name)
+		}
+		for _, path := range paths {
+			_, sourceErr := yml.FindNode(source.File.Tree, path)
+			_, targetErr := yml.FindNode(target.File.Tree, path)
+			if sourceErr == nil || targetErr == nil {
+				found[path] = true
+			}
+		}
+	}
+
+	for _, path := range paths {
+		if !found[path] {
+			return fmt.Errorf("path %s not found in any of the selected releases", path)
+		}
+	}
+	return nil
+}
+
+// restrictToPaths recomputes promoted files of releases in given list, merging only the given paths. Releases missing
+// from target environment are left out, as they would otherwise be promoted as a whole regardless of paths.
+func restrictToPaths(opts Opts, list cross.ReleaseList, paths []string) (cross.ReleaseList, error) {
+	restrictedList, err := opts.Catalog.Releases.GetReleasesForPromotion(opts.SourceEnv, opts.TargetEnv, yml.MergeScope{Only: paths})
+	if err != nil {
+		return cross.ReleaseList{}, fmt.Errorf("getting releases for promotion: %w", err)
+	}
+
+	var names []string
+	for _, item := range list.Items {
+		if target := at(item.Releases, targetEnvIndex); target != nil && target.File != nil {
+			names = append(names, item.Name)
+		}
+	}
+	return restrictedList.OnlySpecificReleases(names)
 }
 
 func (p *Promotion) preview(list cross.ReleaseList) error {
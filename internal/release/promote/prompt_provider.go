@@ -2,6 +2,7 @@ package promote
 
 import (
 	"github.com/nestoca/joy/api/v1alpha1"
+	"github.com/nestoca/joy/internal/git/pr"
 	"github.com/nestoca/joy/internal/release/cross"
 	"github.com/nestoca/joy/internal/yml"
 )
@@ -25,6 +26,13 @@ type PromptProvider interface {
 	// or abort.
 	SelectPromotionAction() (string, error)
 
+	// SelectChanges prompts user to select which of the given changed value paths to promote.
+	SelectChanges(paths []string) ([]string, error)
+
+	// SelectConflictAction prompts user to select how to handle open pull requests already promoting
+	// some of the same releases to the same target environment.
+	SelectConflictAction() (string, error)
+
 	// ConfirmAutoMergePullRequest prompts user to confirm whether to auto-merge promotion PR or not
 	ConfirmAutoMergePullRequest() (bool, error)
 
@@ -58,6 +66,16 @@ type PromptProvider interface {
 	// PrintPullRequestCreated prints message that a new promotion pull request was created.
 	PrintPullRequestCreated(url string)
 
+	// PrintConflictingPullRequests prints a warning that given open pull requests already promote some
+	// of the same releases to given target environment.
+	PrintConflictingPullRequests(targetEnvName string, pullRequests []pr.PullRequest)
+
+	// PrintPullRequestSuperseded prints message that given pull request was closed in favor of the new one.
+	PrintPullRequestSuperseded(url string)
+
+	// PrintPromotionSummary prints the outcome of promoting each release with its own pull request in split mode.
+	PrintPromotionSummary(results []PromotionResult)
+
 	// PrintCanceled prints message that promotion was canceled and no pull request was created.
 	PrintCanceled()
 
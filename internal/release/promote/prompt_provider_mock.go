@@ -7,6 +7,7 @@ import (
 	"sync"
 
 	"github.com/nestoca/joy/api/v1alpha1"
+	"github.com/nestoca/joy/internal/git/pr"
 	"github.com/nestoca/joy/internal/release/cross"
 	"github.com/nestoca/joy/internal/yml"
 )
@@ -36,6 +37,9 @@ var _ PromptProvider = &PromptProviderMock{}
 //			PrintCompletedFunc: func()  {
 //				panic("mock out the PrintCompleted method")
 //			},
+//			PrintConflictingPullRequestsFunc: func(targetEnvName string, pullRequests []pr.PullRequest)  {
+//				panic("mock out the PrintConflictingPullRequests method")
+//			},
 //			PrintDraftPullRequestCreatedFunc: func(url string)  {
 //				panic("mock out the PrintDraftPullRequestCreated method")
 //			},
@@ -48,9 +52,15 @@ var _ PromptProvider = &PromptProviderMock{}
 //			PrintNoPromotableReleasesFoundFunc: func(releasesFiltered bool, sourceEnv *v1alpha1.Environment, targetEnv *v1alpha1.Environment)  {
 //				panic("mock out the PrintNoPromotableReleasesFound method")
 //			},
+//			PrintPromotionSummaryFunc: func(results []PromotionResult)  {
+//				panic("mock out the PrintPromotionSummary method")
+//			},
 //			PrintPullRequestCreatedFunc: func(url string)  {
 //				panic("mock out the PrintPullRequestCreated method")
 //			},
+//			PrintPullRequestSupersededFunc: func(url string)  {
+//				panic("mock out the PrintPullRequestSuperseded method")
+//			},
 //			PrintReleasePreviewFunc: func(targetEnvName string, releaseName string, existingTargetFile *yml.File, promotedFile *yml.File) error {
 //				panic("mock out the PrintReleasePreview method")
 //			},
@@ -63,6 +73,12 @@ var _ PromptProvider = &PromptProviderMock{}
 //			PrintUpdatingTargetReleaseFunc: func(targetEnvName string, releaseName string, releaseFilePath string, isCreating bool)  {
 //				panic("mock out the PrintUpdatingTargetRelease method")
 //			},
+//			SelectChangesFunc: func(paths []string) ([]string, error) {
+//				panic("mock out the SelectChanges method")
+//			},
+//			SelectConflictActionFunc: func() (string, error) {
+//				panic("mock out the SelectConflictAction method")
+//			},
 //			SelectPromotionActionFunc: func() (string, error) {
 //				panic("mock out the SelectPromotionAction method")
 //			},
@@ -97,6 +113,9 @@ type PromptProviderMock struct {
 	// PrintCompletedFunc mocks the PrintCompleted method.
 	PrintCompletedFunc func()
 
+	// PrintConflictingPullRequestsFunc mocks the PrintConflictingPullRequests method.
+	PrintConflictingPullRequestsFunc func(targetEnvName string, pullRequests []pr.PullRequest)
+
 	// PrintDraftPullRequestCreatedFunc mocks the PrintDraftPullRequestCreated method.
 	PrintDraftPullRequestCreatedFunc func(url string)
 
@@ -109,9 +128,15 @@ type PromptProviderMock struct {
 	// PrintNoPromotableReleasesFoundFunc mocks the PrintNoPromotableReleasesFound method.
 	PrintNoPromotableReleasesFoundFunc func(releasesFiltered bool, sourceEnv *v1alpha1.Environment, targetEnv *v1alpha1.Environment)
 
+	// PrintPromotionSummaryFunc mocks the PrintPromotionSummary method.
+	PrintPromotionSummaryFunc func(results []PromotionResult)
+
 	// PrintPullRequestCreatedFunc mocks the PrintPullRequestCreated method.
 	PrintPullRequestCreatedFunc func(url string)
 
+	// PrintPullRequestSupersededFunc mocks the PrintPullRequestSuperseded method.
+	PrintPullRequestSupersededFunc func(url string)
+
 	// PrintReleasePreviewFunc mocks the PrintReleasePreview method.
 	PrintReleasePreviewFunc func(targetEnvName string, releaseName string, existingTargetFile *yml.File, promotedFile *yml.File) error
 
@@ -124,6 +149,12 @@ type PromptProviderMock struct {
 	// PrintUpdatingTargetReleaseFunc mocks the PrintUpdatingTargetRelease method.
 	PrintUpdatingTargetReleaseFunc func(targetEnvName string, releaseName string, releaseFilePath string, isCreating bool)
 
+	// SelectChangesFunc mocks the SelectChanges method.
+	SelectChangesFunc func(paths []string) ([]string, error)
+
+	// SelectConflictActionFunc mocks the SelectConflictAction method.
+	SelectConflictActionFunc func() (string, error)
+
 	// SelectPromotionActionFunc mocks the SelectPromotionAction method.
 	SelectPromotionActionFunc func() (string, error)
 
@@ -161,6 +192,13 @@ type PromptProviderMock struct {
 		// PrintCompleted holds details about calls to the PrintCompleted method.
 		PrintCompleted []struct {
 		}
+		// PrintConflictingPullRequests holds details about calls to the PrintConflictingPullRequests method.
+		PrintConflictingPullRequests []struct {
+			// TargetEnvName is the targetEnvName argument value.
+			TargetEnvName string
+			// PullRequests is the pullRequests argument value.
+			PullRequests []pr.PullRequest
+		}
 		// PrintDraftPullRequestCreated holds details about calls to the PrintDraftPullRequestCreated method.
 		PrintDraftPullRequestCreated []struct {
 			// URL is the url argument value.
@@ -183,11 +221,21 @@ type PromptProviderMock struct {
 			// TargetEnv is the targetEnv argument value.
 			TargetEnv *v1alpha1.Environment
 		}
+		// PrintPromotionSummary holds details about calls to the PrintPromotionSummary method.
+		PrintPromotionSummary []struct {
+			// Results is the results argument value.
+			Results []PromotionResult
+		}
 		// PrintPullRequestCreated holds details about calls to the PrintPullRequestCreated method.
 		PrintPullRequestCreated []struct {
 			// URL is the url argument value.
 			URL string
 		}
+		// PrintPullRequestSuperseded holds details about calls to the PrintPullRequestSuperseded method.
+		PrintPullRequestSuperseded []struct {
+			// URL is the url argument value.
+			URL string
+		}
 		// PrintReleasePreview holds details about calls to the PrintReleasePreview method.
 		PrintReleasePreview []struct {
 			// TargetEnvName is the targetEnvName argument value.
@@ -220,6 +268,14 @@ type PromptProviderMock struct {
 			// IsCreating is the isCreating argument value.
 			IsCreating bool
 		}
+		// SelectChanges holds details about calls to the SelectChanges method.
+		SelectChanges []struct {
+			// Paths is the paths argument value.
+			Paths []string
+		}
+		// SelectConflictAction holds details about calls to the SelectConflictAction method.
+		SelectConflictAction []struct {
+		}
 		// SelectPromotionAction holds details about calls to the SelectPromotionAction method.
 		SelectPromotionAction []struct {
 		}
@@ -246,15 +302,20 @@ type PromptProviderMock struct {
 	lockPrintBranchCreated                  sync.RWMutex
 	lockPrintCanceled                       sync.RWMutex
 	lockPrintCompleted                      sync.RWMutex
+	lockPrintConflictingPullRequests        sync.RWMutex
 	lockPrintDraftPullRequestCreated        sync.RWMutex
 	lockPrintEndPreview                     sync.RWMutex
 	lockPrintNoPromotableEnvironmentFound   sync.RWMutex
 	lockPrintNoPromotableReleasesFound      sync.RWMutex
+	lockPrintPromotionSummary               sync.RWMutex
 	lockPrintPullRequestCreated             sync.RWMutex
+	lockPrintPullRequestSuperseded          sync.RWMutex
 	lockPrintReleasePreview                 sync.RWMutex
 	lockPrintSelectedNonPromotableReleases  sync.RWMutex
 	lockPrintStartPreview                   sync.RWMutex
 	lockPrintUpdatingTargetRelease          sync.RWMutex
+	lockSelectChanges                       sync.RWMutex
+	lockSelectConflictAction                sync.RWMutex
 	lockSelectPromotionAction               sync.RWMutex
 	lockSelectReleases                      sync.RWMutex
 	lockSelectSourceEnvironment             sync.RWMutex
@@ -422,6 +483,42 @@ func (mock *PromptProviderMock) PrintCompletedCalls() []struct {
 	return calls
 }
 
+// PrintConflictingPullRequests calls PrintConflictingPullRequestsFunc.
+func (mock *PromptProviderMock) PrintConflictingPullRequests(targetEnvName string, pullRequests []pr.PullRequest) {
+	callInfo := struct {
+		TargetEnvName string
+		PullRequests  []pr.PullRequest
+	}{
+		TargetEnvName: targetEnvName,
+		PullRequests:  pullRequests,
+	}
+	mock.lockPrintConflictingPullRequests.Lock()
+	mock.calls.PrintConflictingPullRequests = append(mock.calls.PrintConflictingPullRequests, callInfo)
+	mock.lockPrintConflictingPullRequests.Unlock()
+	if mock.PrintConflictingPullRequestsFunc == nil {
+		return
+	}
+	mock.PrintConflictingPullRequestsFunc(targetEnvName, pullRequests)
+}
+
+// PrintConflictingPullRequestsCalls gets all the calls that were made to PrintConflictingPullRequests.
+// Check the length with:
+//
+//	len(mockedPromptProvider.PrintConflictingPullRequestsCalls())
+func (mock *PromptProviderMock) PrintConflictingPullRequestsCalls() []struct {
+	TargetEnvName string
+	PullRequests  []pr.PullRequest
+} {
+	var calls []struct {
+		TargetEnvName string
+		PullRequests  []pr.PullRequest
+	}
+	mock.lockPrintConflictingPullRequests.RLock()
+	calls = mock.calls.PrintConflictingPullRequests
+	mock.lockPrintConflictingPullRequests.RUnlock()
+	return calls
+}
+
 // PrintDraftPullRequestCreated calls PrintDraftPullRequestCreatedFunc.
 func (mock *PromptProviderMock) PrintDraftPullRequestCreated(url string) {
 	callInfo := struct {
@@ -553,6 +650,38 @@ func (mock *PromptProviderMock) PrintNoPromotableReleasesFoundCalls() []struct {
 	return calls
 }
 
+// PrintPromotionSummary calls PrintPromotionSummaryFunc.
+func (mock *PromptProviderMock) PrintPromotionSummary(results []PromotionResult) {
+	callInfo := struct {
+		Results []PromotionResult
+	}{
+		Results: results,
+	}
+	mock.lockPrintPromotionSummary.Lock()
+	mock.calls.PrintPromotionSummary = append(mock.calls.PrintPromotionSummary, callInfo)
+	mock.lockPrintPromotionSummary.Unlock()
+	if mock.PrintPromotionSummaryFunc == nil {
+		return
+	}
+	mock.PrintPromotionSummaryFunc(results)
+}
+
+// PrintPromotionSummaryCalls gets all the calls that were made to PrintPromotionSummary.
+// Check the length with:
+//
+//	len(mockedPromptProvider.PrintPromotionSummaryCalls())
+func (mock *PromptProviderMock) PrintPromotionSummaryCalls() []struct {
+	Results []PromotionResult
+} {
+	var calls []struct {
+		Results []PromotionResult
+	}
+	mock.lockPrintPromotionSummary.RLock()
+	calls = mock.calls.PrintPromotionSummary
+	mock.lockPrintPromotionSummary.RUnlock()
+	return calls
+}
+
 // PrintPullRequestCreated calls PrintPullRequestCreatedFunc.
 func (mock *PromptProviderMock) PrintPullRequestCreated(url string) {
 	callInfo := struct {
@@ -585,6 +714,38 @@ func (mock *PromptProviderMock) PrintPullRequestCreatedCalls() []struct {
 	return calls
 }
 
+// PrintPullRequestSuperseded calls PrintPullRequestSupersededFunc.
+func (mock *PromptProviderMock) PrintPullRequestSuperseded(url string) {
+	callInfo := struct {
+		URL string
+	}{
+		URL: url,
+	}
+	mock.lockPrintPullRequestSuperseded.Lock()
+	mock.calls.PrintPullRequestSuperseded = append(mock.calls.PrintPullRequestSuperseded, callInfo)
+	mock.lockPrintPullRequestSuperseded.Unlock()
+	if mock.PrintPullRequestSupersededFunc == nil {
+		return
+	}
+	mock.PrintPullRequestSupersededFunc(url)
+}
+
+// PrintPullRequestSupersededCalls gets all the calls that were made to PrintPullRequestSuperseded.
+// Check the length with:
+//
+//	len(mockedPromptProvider.PrintPullRequestSupersededCalls())
+func (mock *PromptProviderMock) PrintPullRequestSupersededCalls() []struct {
+	URL string
+} {
+	var calls []struct {
+		URL string
+	}
+	mock.lockPrintPullRequestSuperseded.RLock()
+	calls = mock.calls.PrintPullRequestSuperseded
+	mock.lockPrintPullRequestSuperseded.RUnlock()
+	return calls
+}
+
 // PrintReleasePreview calls PrintReleasePreviewFunc.
 func (mock *PromptProviderMock) PrintReleasePreview(targetEnvName string, releaseName string, existingTargetFile *yml.File, promotedFile *yml.File) error {
 	callInfo := struct {
@@ -739,6 +900,73 @@ func (mock *PromptProviderMock) PrintUpdatingTargetReleaseCalls() []struct {
 	return calls
 }
 
+// SelectChanges calls SelectChangesFunc.
+func (mock *PromptProviderMock) SelectChanges(paths []string) ([]string, error) {
+	callInfo := struct {
+		Paths []string
+	}{
+		Paths: paths,
+	}
+	mock.lockSelectChanges.Lock()
+	mock.calls.SelectChanges = append(mock.calls.SelectChanges, callInfo)
+	mock.lockSelectChanges.Unlock()
+	if mock.SelectChangesFunc == nil {
+		var (
+			stringsOut []string
+			errOut     error
+		)
+		return stringsOut, errOut
+	}
+	return mock.SelectChangesFunc(paths)
+}
+
+// SelectChangesCalls gets all the calls that were made to SelectChanges.
+// Check the length with:
+//
+//	len(mockedPromptProvider.SelectChangesCalls())
+func (mock *PromptProviderMock) SelectChangesCalls() []struct {
+	Paths []string
+} {
+	var calls []struct {
+		Paths []string
+	}
+	mock.lockSelectChanges.RLock()
+	calls = mock.calls.SelectChanges
+	mock.lockSelectChanges.RUnlock()
+	return calls
+}
+
+// SelectConflictAction calls SelectConflictActionFunc.
+func (mock *PromptProviderMock) SelectConflictAction() (string, error) {
+	callInfo := struct {
+	}{}
+	mock.lockSelectConflictAction.Lock()
+	mock.calls.SelectConflictAction = append(mock.calls.SelectConflictAction, callInfo)
+	mock.lockSelectConflictAction.Unlock()
+	if mock.SelectConflictActionFunc == nil {
+		var (
+			sOut   string
+			errOut error
+		)
+		return sOut, errOut
+	}
+	return mock.SelectConflictActionFunc()
+}
+
+// SelectConflictActionCalls gets all the calls that were made to SelectConflictAction.
+// Check the length with:
+//
+//	len(mockedPromptProvider.SelectConflictActionCalls())
+func (mock *PromptProviderMock) SelectConflictActionCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockSelectConflictAction.RLock()
+	calls = mock.calls.SelectConflictAction
+	mock.lockSelectConflictAction.RUnlock()
+	return calls
+}
+
 // SelectPromotionAction calls SelectPromotionActionFunc.
 func (mock *PromptProviderMock) SelectPromotionAction() (string, error) {
 	callInfo := struct {
@@ -39,3 +39,7 @@ func (g *ShellGitProvider) CreateAndPushBranchWithFiles(branchName string, files
 func (g *ShellGitProvider) CheckoutMasterBranch() error {
 	return git.Checkout(g.dir, "master")
 }
+
+func (g *ShellGitProvider) DiscardChanges(files []string) error {
+	return git.Discard(g.dir, files)
+}
@@ -1,8 +1,11 @@
 package promote_test
 
 import (
+	"cmp"
+	"errors"
 	"fmt"
 	"io"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -195,6 +198,852 @@ func TestPromotion(t *testing.T) {
 			pullRequestTemplate: simplePullRequestTemplate,
 			expectedPromoted:    true,
 		},
+		{
+			name: "Promote only version of release1 from staging to prod",
+			opts: newOpts(),
+			setup: func(args setupArgs) func(t *testing.T) {
+				opts := args.opts
+				opts.Mode = promote.ModeVersionOnly
+				crossRel0 := opts.Catalog.Releases.Items[0]
+				sourceRelease := newRelease("release1", `spec:
+  version: 2.0.0
+  values:
+    env:
+      ENV_VAR: value1`, sourceEnvName)
+				sourceRelease.Spec.Version = "2.0.0"
+				targetRelease := newRelease("release1", `spec:
+  version: 1.0.0
+  values:
+    env:
+      ENV_VAR: value2`, targetEnvName)
+				targetRelease.Spec.Version = "1.0.0"
+				expectedPromotedFile := newYamlFile("release1", `spec:
+  version: 2.0.0
+  values:
+    env:
+      ENV_VAR: value2
+`, targetEnvName)
+
+				crossRel0.Releases[sourceEnvIndex] = sourceRelease
+				crossRel0.Releases[targetEnvIndex] = targetRelease
+
+				args.promptProvider.SelectReleasesFunc = func(list cross.ReleaseList, maxColumnWidth int) (cross.ReleaseList, error) {
+					return list, nil
+				}
+				args.promptProvider.SelectPromotionActionFunc = func() (string, error) {
+					return promote.CreatePR, nil
+				}
+
+				args.yamlWriter.WriteFileFunc = func(file *yml.File) error {
+					return nil
+				}
+
+				args.prProvider.CreateFunc = func(createParams pr.CreateParams) (string, error) {
+					return "https://github.com/owner/repo/pull/123", nil
+				}
+
+				setupDefaultMockInfoProvider(args.infoProvider)
+
+				return func(t *testing.T) {
+					require.Len(t, args.promptProvider.PrintReleasePreviewCalls(), 1)
+					printReleasePreviewCall := args.promptProvider.PrintReleasePreviewCalls()[0]
+					require.Equal(t, expectedPromotedFile, printReleasePreviewCall.PromotedFile)
+
+					require.Len(t, args.prProvider.CreateCalls(), 1)
+					require.Equal(t, "PR: Promote 1 releases (staging -> prod) [version-only]", args.prProvider.CreateCalls()[0].CreateParams.Title)
+				}
+			},
+			pullRequestTemplate: simplePullRequestTemplate + "{{ with .Mode }} [{{ . }}]{{ end }}",
+			expectedPromoted:    true,
+		},
+		{
+			name: "Promote only version of release1 carries its digest along",
+			opts: newOpts(),
+			setup: func(args setupArgs) func(t *testing.T) {
+				opts := args.opts
+				opts.Mode = promote.ModeVersionOnly
+				crossRel0 := opts.Catalog.Releases.Items[0]
+				sourceRelease := newRelease("release1", `spec:
+  version: 2.0.0
+  digest: sha256:bbbb
+  values:
+    env:
+      ENV_VAR: value1`, sourceEnvName)
+				sourceRelease.Spec.Version = "2.0.0"
+				sourceRelease.Spec.Digest = "sha256:bbbb"
+				targetRelease := newRelease("release1", `spec:
+  version: 1.0.0
+  values:
+    env:
+      ENV_VAR: value2`, targetEnvName)
+				targetRelease.Spec.Version = "1.0.0"
+				expectedPromotedFile := newYamlFile("release1", `spec:
+  version: 2.0.0
+  values:
+    env:
+      ENV_VAR: value2
+  digest: sha256:bbbb
+`, targetEnvName)
+
+				crossRel0.Releases[sourceEnvIndex] = sourceRelease
+				crossRel0.Releases[targetEnvIndex] = targetRelease
+
+				args.promptProvider.SelectReleasesFunc = func(list cross.ReleaseList, maxColumnWidth int) (cross.ReleaseList, error) {
+					return list, nil
+				}
+				args.promptProvider.SelectPromotionActionFunc = func() (string, error) {
+					return promote.CreatePR, nil
+				}
+				args.prProvider.CreateFunc = func(createParams pr.CreateParams) (string, error) {
+					return "https://github.com/owner/repo/pull/123", nil
+				}
+
+				setupDefaultMockInfoProvider(args.infoProvider)
+
+				return func(t *testing.T) {
+					require.Len(t, args.promptProvider.PrintReleasePreviewCalls(), 1)
+					require.Equal(t, string(expectedPromotedFile.Yaml), string(args.promptProvider.PrintReleasePreviewCalls()[0].PromotedFile.Yaml))
+				}
+			},
+			expectedPromoted: true,
+		},
+		{
+			name: "Promote only values of release1 leaves digest untouched and in sync",
+			opts: newOpts(),
+			setup: func(args setupArgs) func(t *testing.T) {
+				opts := args.opts
+				opts.Mode = promote.ModeValuesOnly
+				crossRel0 := opts.Catalog.Releases.Items[0]
+				sourceRelease := newRelease("release1", `spec:
+  version: 2.0.0
+  digest: sha256:bbbb`, sourceEnvName)
+				sourceRelease.Spec.Version = "2.0.0"
+				sourceRelease.Spec.Digest = "sha256:bbbb"
+				targetRelease := newRelease("release1", `spec:
+  version: 1.0.0
+  digest: sha256:aaaa`, targetEnvName)
+				targetRelease.Spec.Version = "1.0.0"
+				targetRelease.Spec.Digest = "sha256:aaaa"
+
+				crossRel0.Releases[sourceEnvIndex] = sourceRelease
+				crossRel0.Releases[targetEnvIndex] = targetRelease
+
+				return func(t *testing.T) {
+					require.Len(t, args.promptProvider.PrintNoPromotableReleasesFoundCalls(), 1)
+				}
+			},
+			expectedPromoted: false,
+		},
+		{
+			name: "Digest cannot be promoted without its locked version",
+			opts: newOpts(),
+			setup: func(args setupArgs) func(t *testing.T) {
+				crossRel0 := args.opts.Catalog.Releases.Items[0]
+				sourceRelease := newRelease("release1", `spec:
+  version: 2.0.0
+  digest: sha256:bbbb`, sourceEnvName)
+				sourceRelease.Spec.Version = "2.0.0"
+				sourceRelease.Spec.Digest = "sha256:bbbb"
+				targetRelease := newRelease("release1", `spec:
+  version: !lock 1.0.0
+  digest: sha256:aaaa`, targetEnvName)
+				targetRelease.Spec.Version = "1.0.0"
+				targetRelease.Spec.Digest = "sha256:aaaa"
+
+				crossRel0.Releases[sourceEnvIndex] = sourceRelease
+				crossRel0.Releases[targetEnvIndex] = targetRelease
+				return func(t *testing.T) {
+					require.Len(t, args.promptProvider.PrintNoPromotableReleasesFoundCalls(), 1)
+					require.Empty(t, args.yamlWriter.WriteFileCalls())
+				}
+			},
+			expectedPromoted: false,
+		},
+		{
+			name: "Release whose digest cannot be promoted without its locked version is skipped",
+			opts: func() promote.Opts {
+				opts := newOpts()
+				opts.All = true
+				return opts
+			}(),
+			setup: func(args setupArgs) func(t *testing.T) {
+				opts := args.opts
+				sourceRelease := newRelease("release1", `spec:
+  version: 2.0.0
+  digest: sha256:bbbb`, sourceEnvName)
+				sourceRelease.Spec.Version = "2.0.0"
+				sourceRelease.Spec.Digest = "sha256:bbbb"
+				targetRelease := newRelease("release1", `spec:
+  version: !lock 1.0.0
+  digest: sha256:aaaa`, targetEnvName)
+				targetRelease.Spec.Version = "1.0.0"
+				targetRelease.Spec.Digest = "sha256:aaaa"
+
+				opts.Catalog.Releases.Items[0].Releases[sourceEnvIndex] = sourceRelease
+				opts.Catalog.Releases.Items[0].Releases[targetEnvIndex] = targetRelease
+				opts.Catalog.Releases.Items[1].Releases[sourceEnvIndex] = newRelease("release2", `spec:
+  values:
+    key: value2`, sourceEnvName)
+
+				args.promptProvider.SelectPromotionActionFunc = func() (string, error) {
+					return promote.CreatePR, nil
+				}
+				args.prProvider.CreateFunc = func(createParams pr.CreateParams) (string, error) {
+					return "https://github.com/owner/repo/pull/123", nil
+				}
+
+				setupDefaultMockInfoProvider(args.infoProvider)
+
+				return func(t *testing.T) {
+					require.Len(t, args.yamlWriter.WriteFileCalls(), 1)
+					require.Len(t, args.prProvider.CreateCalls(), 1)
+					require.Equal(t, []string{"environment:prod", "release:release2"}, args.prProvider.CreateCalls()[0].CreateParams.Labels)
+				}
+			},
+			expectedPromoted: true,
+		},
+		{
+			name: "Promote only values of release1 with same values is considered already in sync",
+			opts: newOpts(),
+			setup: func(args setupArgs) func(t *testing.T) {
+				opts := args.opts
+				opts.Mode = promote.ModeValuesOnly
+				crossRel0 := opts.Catalog.Releases.Items[0]
+				sourceRelease := newRelease("release1", `spec:
+  version: 2.0.0`, sourceEnvName)
+				sourceRelease.Spec.Version = "2.0.0"
+				targetRelease := newRelease("release1", `spec:
+  version: 1.0.0`, targetEnvName)
+				targetRelease.Spec.Version = "1.0.0"
+
+				crossRel0.Releases[sourceEnvIndex] = sourceRelease
+				crossRel0.Releases[targetEnvIndex] = targetRelease
+
+				return func(t *testing.T) {
+					require.Len(t, args.promptProvider.PrintNoPromotableReleasesFoundCalls(), 1)
+				}
+			},
+			expectedPromoted: false,
+		},
+		{
+			name: "Promote only selected paths of release1 from staging to prod",
+			opts: newOpts(),
+			setup: func(args setupArgs) func(t *testing.T) {
+				opts := args.opts
+				opts.Paths = []string{"spec.values.featureFlags"}
+				crossRel0 := opts.Catalog.Releases.Items[0]
+				crossRel0.Releases[sourceEnvIndex] = newRelease("release1", `spec:
+  values:
+    featureFlags:
+      flag1: true
+    resources:
+      cpu: 2`, sourceEnvName)
+				crossRel0.Releases[targetEnvIndex] = newRelease("release1", `spec:
+  values:
+    featureFlags:
+      flag1: false
+    resources:
+      cpu: 1`, targetEnvName)
+				expectedPromotedFile := newYamlFile("release1", `spec:
+  values:
+    featureFlags:
+      flag1: true
+    resources:
+      cpu: 1
+`, targetEnvName)
+
+				args.promptProvider.SelectReleasesFunc = func(list cross.ReleaseList, maxColumnWidth int) (cross.ReleaseList, error) {
+					return list, nil
+				}
+				args.promptProvider.SelectPromotionActionFunc = func() (string, error) {
+					return promote.CreatePR, nil
+				}
+				args.prProvider.CreateFunc = func(createParams pr.CreateParams) (string, error) {
+					return "https://github.com/owner/repo/pull/123", nil
+				}
+
+				setupDefaultMockInfoProvider(args.infoProvider)
+
+				return func(t *testing.T) {
+					require.Len(t, args.promptProvider.PrintReleasePreviewCalls(), 1)
+					require.Equal(t, expectedPromotedFile, args.promptProvider.PrintReleasePreviewCalls()[0].PromotedFile)
+
+					require.Len(t, args.prProvider.CreateCalls(), 1)
+					require.Equal(t, "PR: Promote 1 releases (staging -> prod) [spec.values.featureFlags]", args.prProvider.CreateCalls()[0].CreateParams.Title)
+				}
+			},
+			pullRequestTemplate: simplePullRequestTemplate + `{{ with .Paths }} [{{ join ", " . }}]{{ end }}`,
+			expectedPromoted:    true,
+		},
+		{
+			name: "Paths cannot be combined with version-only mode",
+			opts: newOpts(),
+			setup: func(args setupArgs) func(t *testing.T) {
+				args.opts.Mode = promote.ModeVersionOnly
+				args.opts.Paths = []string{"spec.values.featureFlags"}
+				return func(t *testing.T) {}
+			},
+			expectedErrorMessage: "paths cannot be combined with version-only mode",
+			expectedPromoted:     false,
+		},
+		{
+			name: "Paths not found in source release cannot be promoted",
+			opts: func() promote.Opts {
+				opts := newOpts()
+				opts.Releases = []string{"release1"}
+				opts.Paths = []string{"spec.values.unknown"}
+				return opts
+			}(),
+			setup: func(args setupArgs) func(t *testing.T) {
+				return func(t *testing.T) {
+					require.Empty(t, args.prProvider.CreateCalls())
+				}
+			},
+			expectedErrorMessage: "path spec.values.unknown not found in any of the selected releases",
+			expectedPromoted:     false,
+		},
+		{
+			name: "Paths of release missing in target cannot be promoted",
+			opts: func() promote.Opts {
+				opts := newOpts()
+				opts.Releases = []string{"release1"}
+				opts.Paths = []string{"spec.values.key"}
+				return opts
+			}(),
+			setup: func(args setupArgs) func(t *testing.T) {
+				args.opts.Catalog.Releases.Items[0].Releases[targetEnvIndex] = nil
+				return func(t *testing.T) {
+					require.Empty(t, args.prProvider.CreateCalls())
+				}
+			},
+			expectedErrorMessage: "cannot promote only given paths of release release1, as it does not exist in target environment",
+			expectedPromoted:     false,
+		},
+		{
+			name: "Interactively select changes of release1 to promote from staging to prod",
+			opts: newOpts(),
+			setup: func(args setupArgs) func(t *testing.T) {
+				opts := args.opts
+				crossRel0 := opts.Catalog.Releases.Items[0]
+				crossRel0.Releases[sourceEnvIndex] = newRelease("release1", `spec:
+  values:
+    featureFlags:
+      flag1: true
+      flag2: true
+    resources:
+      cpu: 2`, sourceEnvName)
+				crossRel0.Releases[targetEnvIndex] = newRelease("release1", `spec:
+  values:
+    featureFlags:
+      flag1: false
+      flag2: false
+    resources:
+      cpu: 1`, targetEnvName)
+				expectedPromotedFile := newYamlFile("release1", `spec:
+  values:
+    featureFlags:
+      flag1: false
+      flag2: true
+    resources:
+      cpu: 1
+`, targetEnvName)
+
+				args.promptProvider.SelectReleasesFunc = func(list cross.ReleaseList, maxColumnWidth int) (cross.ReleaseList, error) {
+					return list, nil
+				}
+				actions := []string{promote.SelectChanges, promote.CreatePR}
+				args.promptProvider.SelectPromotionActionFunc = func() (string, error) {
+					action := actions[0]
+					actions = actions[1:]
+					return action, nil
+				}
+				args.promptProvider.SelectChangesFunc = func(paths []string) ([]string, error) {
+					return []string{"spec.values.featureFlags.flag2"}, nil
+				}
+				args.prProvider.CreateFunc = func(createParams pr.CreateParams) (string, error) {
+					return "https://github.com/owner/repo/pull/123", nil
+				}
+
+				setupDefaultMockInfoProvider(args.infoProvider)
+
+				return func(t *testing.T) {
+					require.Len(t, args.promptProvider.SelectChangesCalls(), 1)
+					require.Equal(t,
+						[]string{"spec.values.featureFlags.flag1", "spec.values.featureFlags.flag2", "spec.values.resources.cpu"},
+						args.promptProvider.SelectChangesCalls()[0].Paths)
+
+					require.Len(t, args.promptProvider.PrintReleasePreviewCalls(), 2)
+					require.Equal(t, expectedPromotedFile, args.promptProvider.PrintReleasePreviewCalls()[1].PromotedFile)
+
+					require.Len(t, args.yamlWriter.WriteFileCalls(), 1)
+					require.Equal(t, expectedPromotedFile, args.yamlWriter.WriteFileCalls()[0].File)
+				}
+			},
+			expectedPromoted: true,
+		},
+		{
+			name: "Promote removal of selected path of release1 from staging to prod",
+			opts: newOpts(),
+			setup: func(args setupArgs) func(t *testing.T) {
+				opts := args.opts
+				opts.Paths = []string{"spec.values.featureFlags.flag2"}
+				crossRel0 := opts.Catalog.Releases.Items[0]
+				crossRel0.Releases[sourceEnvIndex] = newRelease("release1", `spec:
+  values:
+    featureFlags:
+      flag1: true`, sourceEnvName)
+				crossRel0.Releases[targetEnvIndex] = newRelease("release1", `spec:
+  values:
+    featureFlags:
+      flag1: false
+      flag2: false`, targetEnvName)
+				expectedPromotedFile := newYamlFile("release1", `spec:
+  values:
+    featureFlags:
+      flag1: false
+`, targetEnvName)
+
+				args.promptProvider.SelectReleasesFunc = func(list cross.ReleaseList, maxColumnWidth int) (cross.ReleaseList, error) {
+					return list, nil
+				}
+				args.promptProvider.SelectPromotionActionFunc = func() (string, error) {
+					return promote.CreatePR, nil
+				}
+				args.prProvider.CreateFunc = func(createParams pr.CreateParams) (string, error) {
+					return "https://github.com/owner/repo/pull/123", nil
+				}
+
+				setupDefaultMockInfoProvider(args.infoProvider)
+
+				return func(t *testing.T) {
+					require.Len(t, args.yamlWriter.WriteFileCalls(), 1)
+					require.Equal(t, expectedPromotedFile, args.yamlWriter.WriteFileCalls()[0].File)
+				}
+			},
+			expectedPromoted: true,
+		},
+		{
+			name: "Interactively selected changes of release1 leave out release2 missing in prod",
+			opts: newOpts(),
+			setup: func(args setupArgs) func(t *testing.T) {
+				opts := args.opts
+				crossRel0 := opts.Catalog.Releases.Items[0]
+				crossRel0.Releases[sourceEnvIndex] = newRelease("release1", `spec:
+  values:
+    key: value1`, sourceEnvName)
+				opts.Catalog.Releases.Items[1].Releases[targetEnvIndex] = nil
+
+				args.promptProvider.SelectReleasesFunc = func(list cross.ReleaseList, maxColumnWidth int) (cross.ReleaseList, error) {
+					return list, nil
+				}
+				actions := []string{promote.SelectChanges, promote.CreatePR}
+				args.promptProvider.SelectPromotionActionFunc = func() (string, error) {
+					action := actions[0]
+					actions = actions[1:]
+					return action, nil
+				}
+				args.promptProvider.SelectChangesFunc = func(paths []string) ([]string, error) {
+					return paths, nil
+				}
+				args.prProvider.CreateFunc = func(createParams pr.CreateParams) (string, error) {
+					return "https://github.com/owner/repo/pull/123", nil
+				}
+
+				setupDefaultMockInfoProvider(args.infoProvider)
+
+				return func(t *testing.T) {
+					require.Equal(t, []string{"spec.values.key"}, args.promptProvider.SelectChangesCalls()[0].Paths)
+					require.Len(t, args.yamlWriter.WriteFileCalls(), 1)
+					require.Contains(t, string(args.yamlWriter.WriteFileCalls()[0].File.Yaml), "name: release1")
+				}
+			},
+			expectedPromoted: true,
+		},
+		{
+			name: "Supersede conflicting pull request promoting release1 to prod, leaving bundled one open",
+			opts: newOpts(),
+			setup: func(args setupArgs) func(t *testing.T) {
+				opts := args.opts
+				crossRel0 := opts.Catalog.Releases.Items[0]
+				crossRel0.Releases[sourceEnvIndex] = newRelease("release1", `spec:
+  values:
+    key: value1`, sourceEnvName)
+
+				args.promptProvider.SelectReleasesFunc = func(list cross.ReleaseList, maxColumnWidth int) (cross.ReleaseList, error) {
+					return list, nil
+				}
+				args.promptProvider.SelectPromotionActionFunc = func() (string, error) {
+					return promote.CreatePR, nil
+				}
+				args.promptProvider.SelectConflictActionFunc = func() (string, error) {
+					return promote.SupersedePRs, nil
+				}
+
+				conflict := pr.PullRequest{Number: 42, URL: "https://github.com/owner/repo/pull/42", Labels: []string{"environment:prod", "release:release1"}}
+				bundle := pr.PullRequest{Number: 44, URL: "https://github.com/owner/repo/pull/44", Labels: []string{"environment:prod", "release:release1", "release:release3"}}
+				args.prProvider.ListOpenFunc = func(labels ...string) ([]pr.PullRequest, error) {
+					return []pr.PullRequest{
+						conflict,
+						{Number: 43, URL: "https://github.com/owner/repo/pull/43", Labels: []string{"environment:prod", "release:release2"}},
+						bundle,
+					}, nil
+				}
+				args.prProvider.CreateFunc = func(createParams pr.CreateParams) (string, error) {
+					return "https://github.com/owner/repo/pull/123", nil
+				}
+
+				setupDefaultMockInfoProvider(args.infoProvider)
+
+				return func(t *testing.T) {
+					require.Len(t, args.prProvider.ListOpenCalls(), 1)
+					require.Equal(t, []string{"environment:prod"}, args.prProvider.ListOpenCalls()[0].Labels)
+
+					require.Len(t, args.promptProvider.PrintConflictingPullRequestsCalls(), 1)
+					require.Equal(t, []pr.PullRequest{conflict, bundle}, args.promptProvider.PrintConflictingPullRequestsCalls()[0].PullRequests)
+
+					// The bundled pull request also promotes release3, which would be dropped if it were closed
+					require.Len(t, args.prProvider.CloseCalls(), 1)
+					require.Equal(t, 42, args.prProvider.CloseCalls()[0].Number)
+					require.Equal(t, "Superseded by https://github.com/owner/repo/pull/123", args.prProvider.CloseCalls()[0].Comment)
+					require.Len(t, args.promptProvider.PrintPullRequestSupersededCalls(), 1)
+				}
+			},
+			expectedPromoted: true,
+		},
+		{
+			name: "Cancel promotion of release1 to prod because of conflicting pull request",
+			opts: newOpts(),
+			setup: func(args setupArgs) func(t *testing.T) {
+				opts := args.opts
+				crossRel0 := opts.Catalog.Releases.Items[0]
+				crossRel0.Releases[sourceEnvIndex] = newRelease("release1", `spec:
+  values:
+    key: value1`, sourceEnvName)
+
+				args.promptProvider.SelectReleasesFunc = func(list cross.ReleaseList, maxColumnWidth int) (cross.ReleaseList, error) {
+					return list, nil
+				}
+				args.promptProvider.SelectPromotionActionFunc = func() (string, error) {
+					return promote.CreatePR, nil
+				}
+				args.promptProvider.SelectConflictActionFunc = func() (string, error) {
+					return promote.Cancel, nil
+				}
+				args.prProvider.ListOpenFunc = func(labels ...string) ([]pr.PullRequest, error) {
+					return []pr.PullRequest{{Number: 42, Labels: []string{"environment:prod", "release:release1"}}}, nil
+				}
+
+				return func(t *testing.T) {
+					require.Len(t, args.promptProvider.PrintCanceledCalls(), 1)
+					require.Empty(t, args.yamlWriter.WriteFileCalls())
+					require.Empty(t, args.gitProvider.CreateAndPushBranchWithFilesCalls())
+					require.Empty(t, args.prProvider.CreateCalls())
+				}
+			},
+			expectedPromoted: false,
+		},
+		{
+			name: "Promote release1 and release2 from staging to prod with one pull request per release",
+			opts: func() promote.Opts {
+				opts := newOpts()
+				opts.Split = true
+				return opts
+			}(),
+			setup: func(args setupArgs) func(t *testing.T) {
+				opts := args.opts
+				opts.Catalog.Releases.Items[0].Releases[sourceEnvIndex] = newRelease("release1", `spec:
+  values:
+    key: value1`, sourceEnvName)
+				opts.Catalog.Releases.Items[1].Releases[sourceEnvIndex] = newRelease("release2", `spec:
+  values:
+    key: value2`, sourceEnvName)
+
+				args.promptProvider.SelectReleasesFunc = func(list cross.ReleaseList, maxColumnWidth int) (cross.ReleaseList, error) {
+					return list, nil
+				}
+				args.promptProvider.SelectPromotionActionFunc = func() (string, error) {
+					return promote.CreatePR, nil
+				}
+
+				var count int
+				args.prProvider.CreateFunc = func(createParams pr.CreateParams) (string, error) {
+					count++
+					return fmt.Sprintf("https://github.com/owner/repo/pull/%d", count), nil
+				}
+
+				setupDefaultMockInfoProvider(args.infoProvider)
+
+				return func(t *testing.T) {
+					require.Len(t, args.gitProvider.CreateAndPushBranchWithFilesCalls(), 2)
+					require.Len(t, args.gitProvider.CheckoutMasterBranchCalls(), 2)
+
+					createCalls := args.prProvider.CreateCalls()
+					require.Len(t, createCalls, 2)
+					require.NotEqual(t, createCalls[0].CreateParams.Branch, createCalls[1].CreateParams.Branch)
+					require.Equal(t, "PR: Promote 1 releases (staging -> prod)", createCalls[0].CreateParams.Title)
+					require.Equal(t, []string{"environment:prod", "release:release1"}, createCalls[0].CreateParams.Labels)
+					require.Equal(t, []string{"environment:prod", "release:release2"}, createCalls[1].CreateParams.Labels)
+
+					require.Len(t, args.promptProvider.PrintPromotionSummaryCalls(), 1)
+					require.Equal(t, []promote.PromotionResult{
+						{Release: "release1", URL: "https://github.com/owner/repo/pull/1"},
+						{Release: "release2", URL: "https://github.com/owner/repo/pull/2"},
+					}, args.promptProvider.PrintPromotionSummaryCalls()[0].Results)
+				}
+			},
+			expectedPromoted: true,
+		},
+		{
+			name: "Discard promoted file of release whose branch creation fails before promoting next release in split mode",
+			opts: func() promote.Opts {
+				opts := newOpts()
+				opts.Split = true
+				return opts
+			}(),
+			setup: func(args setupArgs) func(t *testing.T) {
+				opts := args.opts
+				opts.Catalog.Releases.Items[0].Releases[sourceEnvIndex] = newRelease("release1", `spec:
+  values:
+    key: value1`, sourceEnvName)
+				opts.Catalog.Releases.Items[1].Releases[sourceEnvIndex] = newRelease("release2", `spec:
+  values:
+    key: value2`, sourceEnvName)
+
+				args.promptProvider.SelectReleasesFunc = func(list cross.ReleaseList, maxColumnWidth int) (cross.ReleaseList, error) {
+					return list, nil
+				}
+				args.promptProvider.SelectPromotionActionFunc = func() (string, error) {
+					return promote.CreatePR, nil
+				}
+				args.gitProvider.CreateAndPushBranchWithFilesFunc = func(branchName string, files []string, message string) error {
+					if strings.Contains(branchName, "release1") {
+						return errors.New("branch already exists")
+					}
+					return nil
+				}
+				args.prProvider.CreateFunc = func(createParams pr.CreateParams) (string, error) {
+					return "https://github.com/owner/repo/pull/2", nil
+				}
+
+				setupDefaultMockInfoProvider(args.infoProvider)
+
+				return func(t *testing.T) {
+					require.Len(t, args.yamlWriter.WriteFileCalls(), 2)
+					require.Len(t, args.gitProvider.DiscardChangesCalls(), 1)
+					require.Equal(t, []string{args.yamlWriter.WriteFileCalls()[0].File.Path}, args.gitProvider.DiscardChangesCalls()[0].Files)
+					require.Len(t, args.prProvider.CreateCalls(), 1)
+					require.Equal(t, []string{"environment:prod", "release:release2"}, args.prProvider.CreateCalls()[0].CreateParams.Labels)
+				}
+			},
+			expectedErrorMessage: "promoting release release1: branch already exists",
+			expectedPromoted:     true,
+		},
+		{
+			name: "Promoting release1 ahead of its release2 dependency still behind in prod fails",
+			opts: func() promote.Opts {
+				opts := newOpts()
+				opts.Releases = []string{"release1"}
+				return opts
+			}(),
+			setup: func(args setupArgs) func(t *testing.T) {
+				setupDependentReleases(args.opts.Catalog, "2.0.0", "1.1.0")
+				return func(t *testing.T) {
+					require.Empty(t, args.prProvider.CreateCalls())
+				}
+			},
+			expectedErrorMessage: "cannot promote releases ahead of their dependencies (promote dependencies first, along with them, or use --ignore-dependencies):\n  - release1 depends on release2, which is behind in target environment (1.0.0 < 1.1.0)",
+			expectedPromoted:     false,
+		},
+		{
+			name: "Promoting release1 ahead of its release2 dependency outside of release selection fails",
+			opts: func() promote.Opts {
+				opts := newOpts()
+				opts.ReleaseSelection = []string{"release1"}
+				return opts
+			}(),
+			setup: func(args setupArgs) func(t *testing.T) {
+				setupDependentReleases(args.opts.Catalog, "2.0.0", "1.1.0")
+
+				args.promptProvider.SelectReleasesFunc = func(list cross.ReleaseList, maxColumnWidth int) (cross.ReleaseList, error) {
+					return list, nil
+				}
+
+				return func(t *testing.T) {
+					require.Len(t, args.promptProvider.SelectReleasesCalls(), 1)
+					items := args.promptProvider.SelectReleasesCalls()[0].List.Items
+					require.Len(t, items, 1)
+					require.Equal(t, "release1", items[0].Name)
+					require.Empty(t, args.prProvider.CreateCalls())
+				}
+			},
+			expectedErrorMessage: "cannot promote releases ahead of their dependencies (promote dependencies first, along with them, or use --ignore-dependencies):\n  - release1 depends on release2, which is behind in target environment (1.0.0 < 1.1.0)",
+			expectedPromoted:     false,
+		},
+		{
+			name: "Promoting release1 along with its release2 dependency fails when dependency cannot be promoted",
+			opts: func() promote.Opts {
+				opts := newOpts()
+				opts.Releases = []string{"release1", "release2"}
+				return opts
+			}(),
+			setup: func(args setupArgs) func(t *testing.T) {
+				cat := args.opts.Catalog
+				setupDependentReleases(cat, "2.0.0", "1.1.0")
+
+				// Locked digest in target would get promoted separately from version
+				source := newRelease("release2", `spec:
+  version: 1.1.0
+  digest: sha256:new`, sourceEnvName)
+				source.Spec.Version = "1.1.0"
+				source.Spec.Digest = "sha256:new"
+				cat.Releases.Items[1].Releases[sourceEnvIndex] = source
+
+				target := newRelease("release2", `spec:
+  version: 1.0.0
+  digest: !lock sha256:old`, targetEnvName)
+				target.Spec.Version = "1.0.0"
+				target.Spec.Digest = "sha256:old"
+				cat.Releases.Items[1].Releases[targetEnvIndex] = target
+
+				return func(t *testing.T) {
+					require.Empty(t, args.prProvider.CreateCalls())
+				}
+			},
+			expectedErrorMessage: "cannot promote releases ahead of their dependencies (promote dependencies first, along with them, or use --ignore-dependencies):\n  - release1 depends on release2, which is behind in target environment (1.0.0 < 1.1.0)",
+			expectedPromoted:     false,
+		},
+		{
+			name: "Interactively selected changes cannot leave out release2 dependency missing in prod",
+			opts: newOpts(),
+			setup: func(args setupArgs) func(t *testing.T) {
+				setupDependentReleases(args.opts.Catalog, "2.0.0", "1.1.0")
+				args.opts.Catalog.Releases.Items[1].Releases[targetEnvIndex] = nil
+
+				args.promptProvider.SelectReleasesFunc = func(list cross.ReleaseList, maxColumnWidth int) (cross.ReleaseList, error) {
+					return list, nil
+				}
+				args.promptProvider.SelectPromotionActionFunc = func() (string, error) {
+					return promote.SelectChanges, nil
+				}
+				args.promptProvider.SelectChangesFunc = func(paths []string) ([]string, error) {
+					return paths, nil
+				}
+
+				return func(t *testing.T) {
+					require.Len(t, args.promptProvider.SelectChangesCalls(), 1)
+					require.Empty(t, args.prProvider.CreateCalls())
+				}
+			},
+			expectedErrorMessage: "cannot promote releases ahead of their dependencies (promote dependencies first, along with them, or use --ignore-dependencies):\n  - release1 depends on release2, which is missing in target environment",
+			expectedPromoted:     false,
+		},
+		{
+			name: "Promoting release1 ahead of its release2 dependency only warns when ignoring dependencies",
+			opts: func() promote.Opts {
+				opts := newOpts()
+				opts.Releases = []string{"release1"}
+				opts.IgnoreDependencies = true
+				return opts
+			}(),
+			setup: func(args setupArgs) func(t *testing.T) {
+				setupDependentReleases(args.opts.Catalog, "2.0.0", "1.1.0")
+
+				args.promptProvider.SelectPromotionActionFunc = func() (string, error) {
+					return promote.CreatePR, nil
+				}
+				args.prProvider.CreateFunc = func(createParams pr.CreateParams) (string, error) {
+					return "https://github.com/owner/repo/pull/123", nil
+				}
+
+				setupDefaultMockInfoProvider(args.infoProvider)
+
+				return func(t *testing.T) {
+					require.Len(t, args.prProvider.CreateCalls(), 1)
+					require.Equal(t, []string{"environment:prod", "release:release1"}, args.prProvider.CreateCalls()[0].CreateParams.Labels)
+				}
+			},
+			expectedPromoted: true,
+		},
+		{
+			name: "Promote release1 along with its release2 dependency with dependency first",
+			opts: func() promote.Opts {
+				opts := newOpts()
+				opts.Split = true
+				return opts
+			}(),
+			setup: func(args setupArgs) func(t *testing.T) {
+				setupDependentReleases(args.opts.Catalog, "2.0.0", "1.1.0")
+
+				args.promptProvider.SelectReleasesFunc = func(list cross.ReleaseList, maxColumnWidth int) (cross.ReleaseList, error) {
+					return list, nil
+				}
+				args.promptProvider.SelectPromotionActionFunc = func() (string, error) {
+					return promote.CreatePR, nil
+				}
+
+				var count int
+				args.prProvider.CreateFunc = func(createParams pr.CreateParams) (string, error) {
+					count++
+					return fmt.Sprintf("https://github.com/owner/repo/pull/%d", count), nil
+				}
+
+				setupDefaultMockInfoProvider(args.infoProvider)
+
+				return func(t *testing.T) {
+					createCalls := args.prProvider.CreateCalls()
+					require.Len(t, createCalls, 2)
+					require.Equal(t, []string{"environment:prod", "release:release2"}, createCalls[0].CreateParams.Labels)
+					require.Equal(t, []string{"environment:prod", "release:release1"}, createCalls[1].CreateParams.Labels)
+				}
+			},
+			expectedPromoted: true,
+		},
+		{
+			name: "Label pull request promoting release1 with breaking changes",
+			opts: newOpts(),
+			setup: func(args setupArgs) func(t *testing.T) {
+				opts := args.opts
+				source := newRelease("release1", `spec:
+  version: 2.0.0
+  values:
+    key: value1`, sourceEnvName)
+				source.Spec.Version = "2.0.0"
+				opts.Catalog.Releases.Items[0].Releases[sourceEnvIndex] = source
+
+				args.promptProvider.SelectReleasesFunc = func(list cross.ReleaseList, maxColumnWidth int) (cross.ReleaseList, error) {
+					return list, nil
+				}
+				args.promptProvider.SelectPromotionActionFunc = func() (string, error) {
+					return promote.CreatePR, nil
+				}
+				args.prProvider.CreateFunc = func(createParams pr.CreateParams) (string, error) {
+					return "https://github.com/owner/repo/pull/123", nil
+				}
+
+				setupDefaultMockInfoProvider(args.infoProvider)
+				args.infoProvider.GetReleaseGitTagFunc = func(release *v1alpha1.Release) (string, error) {
+					return "v" + cmp.Or(release.Spec.Version, "1.0.0"), nil
+				}
+				args.infoProvider.GetCommitsMetadataFunc = func(projectDir string, fromTag string, toTag string) ([]*info.CommitMetadata, error) {
+					return []*info.CommitMetadata{
+						{Sha: "1234567890", Author: "john", Message: "feat(api)!: remove v1 endpoints"},
+						{Sha: "4567890123", Author: "jane", Message: "fix: typo"},
+					}, nil
+				}
+
+				return func(t *testing.T) {
+					require.Len(t, args.prProvider.CreateCalls(), 1)
+					createParams := args.prProvider.CreateCalls()[0].CreateParams
+					require.Equal(t, []string{"environment:prod", "release:release1", "breaking-change"}, createParams.Labels)
+					require.Equal(t, "breaking: remove v1 endpoints\nfix: typo", createParams.Body)
+				}
+			},
+			pullRequestTemplate: `PR title
+{{- range .Releases }}{{ range .Changelog.Breaking }}
+breaking: {{ .Description }}{{ end }}{{ range .Changelog.Fixes }}
+fix: {{ .Description }}{{ end }}{{ end }}`,
+			expectedPromoted: true,
+		},
 		{
 			name: "Promote release1 from staging to missing release in prod",
 			opts: newOpts(),
@@ -297,7 +1146,7 @@ func TestPromotion(t *testing.T) {
 				PullRequestProvider: prProvider,
 				YamlWriter:          yamlWriter,
 				CommitTemplate:      simpleCommitTemplate,
-				PullRequestTemplate: simplePullRequestTemplate,
+				PullRequestTemplate: cmp.Or(c.pullRequestTemplate, simplePullRequestTemplate),
 				InfoProvider:        infoProvider,
 				LinksProvider:       linksProvider,
 				Out:                 io.Discard,
@@ -404,6 +1253,32 @@ func newCatalog() *catalog.Catalog {
 	}
 }
 
+// setupDependentReleases sets up release1 depending on release2, both at version 1.0.0 in prod and at given
+// versions in staging.
+func setupDependentReleases(cat *catalog.Catalog, release1Version, release2Version string) {
+	release1 := newRelease("release1", fmt.Sprintf(`spec:
+  version: %s
+  dependsOn: [release2]`, release1Version), sourceEnvName)
+	release1.Spec.Version = release1Version
+	release1.Spec.DependsOn = []string{"release2"}
+	cat.Releases.Items[0].Releases[sourceEnvIndex] = release1
+
+	release2 := newRelease("release2", fmt.Sprintf(`spec:
+  version: %s`, release2Version), sourceEnvName)
+	release2.Spec.Version = release2Version
+	cat.Releases.Items[1].Releases[sourceEnvIndex] = release2
+
+	for i, name := range []string{"release1", "release2"} {
+		target := newRelease(name, `spec:
+  version: 1.0.0`, targetEnvName)
+		target.Spec.Version = "1.0.0"
+		if name == "release1" {
+			target.Spec.DependsOn = []string{"release2"}
+		}
+		cat.Releases.Items[i].Releases[targetEnvIndex] = target
+	}
+}
+
 func newOpts() promote.Opts {
 	cat := newCatalog()
 	sourceEnv := cat.Environments[stagingEnvIndex]
@@ -151,6 +151,43 @@ func TestRenderRelease(t *testing.T) {
 				},
 			},
 		},
+		{
+			Name: "with image digest mapping",
+			Params: RenderTestParams{
+				Release: func() *v1alpha1.Release {
+					rel := buildRelease("env", "release")
+					rel.Spec.Values = map[string]any{}
+					rel.Spec.Version = "v9.9.9"
+					rel.Spec.Digest = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+					return rel
+				}(),
+				Chart: helm.Chart{
+					Mappings: map[string]any{
+						"image.tag":    "{{ .Release.Spec.Version }}",
+						"image.digest": "{{ .Release.Spec.Digest }}",
+					},
+				},
+				SetupHelmMock: func(mock *helm.PullRendererMock) func(*testing.T) {
+					return func(t *testing.T) {
+						require.Len(t, mock.RenderCalls(), 1)
+						require.Equal(
+							t,
+							helm.RenderOpts{
+								ReleaseName: "release",
+								Values: map[string]any{
+									"image": map[string]any{
+										"tag":    "v9.9.9",
+										"digest": "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+									},
+								},
+								ChartPath: "path/to/chart",
+							},
+							mock.RenderCalls()[0].Opts,
+						)
+					}
+				},
+			},
+		},
 		{
 			Name: "chart mappings take priority over global mappings",
 			Params: RenderTestParams{
@@ -0,0 +1,107 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Client is a minimal JSON client for the REST APIs of git hosting services.
+type Client struct {
+	// BaseURL is the URL that request paths are relative to, ie: https://gitlab.example.com/api/v4
+	BaseURL string
+
+	// Header is added to every request, typically for authentication.
+	Header http.Header
+
+	// HTTPClient is the underlying client used to send requests.
+	HTTPClient *http.Client
+}
+
+func NewClient(baseURL string, header http.Header) *Client {
+	return &Client{
+		BaseURL:    strings.TrimSuffix(baseURL, "/"),
+		Header:     header,
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Error is returned when the API responds with a non-successful status code.
+type Error struct {
+	Method     string
+	URL        string
+	StatusCode int
+	Body       string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s %s: %d %s: %s", e.Method, e.URL, e.StatusCode, http.StatusText(e.StatusCode), e.Body)
+}
+
+// IsNotFound returns true if given error is an API error with a 404 status code.
+func IsNotFound(err error) bool {
+	var restErr *Error
+	return errors.As(err, &restErr) && restErr.StatusCode == http.StatusNotFound
+}
+
+// Do sends a request with given method to given path, which may include a query string, relative to the base URL.
+// Body is encoded as JSON if non-nil, and the JSON response is decoded into out if non-nil.
+func (c *Client) Do(method, path string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("marshalling request body: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	url := c.BaseURL + "/" + strings.TrimPrefix(path, "/")
+	req, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	for key, values := range c.Header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("sending request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("reading response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &Error{
+			Method:     method,
+			URL:        url,
+			StatusCode: resp.StatusCode,
+			Body:       strings.TrimSpace(string(data)),
+		}
+	}
+
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("unmarshalling response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,287 @@
+package server
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nestoca/joy/api/v1alpha1"
+	"github.com/nestoca/joy/internal/git"
+	"github.com/nestoca/joy/internal/links"
+	"github.com/nestoca/joy/internal/release/list"
+	"github.com/nestoca/joy/internal/release/render"
+	"github.com/nestoca/joy/internal/style"
+	"github.com/nestoca/joy/pkg/catalog"
+	"github.com/nestoca/joy/pkg/helm"
+)
+
+//go:embed index.html
+var indexHTML []byte
+
+type Opts struct {
+	// CatalogDir is the directory of the catalog to serve, which gets reloaded whenever its content changes.
+	CatalogDir     string
+	KnownChartRefs []string
+
+	ReferenceEnvironment string
+
+	// Charts is used to hydrate release values.
+	Charts helm.ChartCache
+
+	// Links is used to resolve release links.
+	Links links.Provider
+
+	// Out is where catalog reloads and errors are logged.
+	Out io.Writer
+}
+
+// Server exposes a read-only JSON API and a web view of the catalog.
+type Server struct {
+	opts Opts
+
+	mu          sync.RWMutex
+	catalog     *catalog.Catalog
+	fingerprint string
+	loadedAt    time.Time
+}
+
+// New returns a server for given catalog, already loaded from catalog directory.
+func New(opts Opts, cat *catalog.Catalog) (*Server, error) {
+	fingerprint, err := catalog.Fingerprint(opts.CatalogDir)
+	if err != nil {
+		return nil, fmt.Errorf("fingerprinting catalog: %w", err)
+	}
+	return &Server{
+		opts:        opts,
+		catalog:     cat,
+		fingerprint: fingerprint,
+		loadedAt:    time.Now(),
+	}, nil
+}
+
+// Reload loads catalog again if any of its files changed since last loaded, returning whether it did.
+func (s *Server) Reload(ctx context.Context) (bool, error) {
+	fingerprint, err := catalog.Fingerprint(s.opts.CatalogDir)
+	if err != nil {
+		return false, fmt.Errorf("fingerprinting catalog: %w", err)
+	}
+
+	s.mu.RLock()
+	unchanged := fingerprint == s.fingerprint
+	s.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	cat, err := catalog.Load(ctx, s.opts.CatalogDir, s.opts.KnownChartRefs)
+	if err != nil {
+		return false, fmt.Errorf("loading catalog: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.catalog = cat
+	s.fingerprint = fingerprint
+	s.loadedAt = time.Now()
+	return true, nil
+}
+
+type WatchOpts struct {
+	// Interval between checks for catalog changes.
+	Interval time.Duration
+
+	// Pull indicates to pull catalog from its remote before each check, rather than only watching local changes.
+	Pull bool
+}
+
+// Watch reloads catalog whenever it changes, until context is done.
+func (s *Server) Watch(ctx context.Context, opts WatchOpts) {
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if opts.Pull {
+			if err := git.Pull(s.opts.CatalogDir); err != nil {
+				s.printf("⚠️ %s\n", style.Warning(err))
+				continue
+			}
+		}
+
+		reloaded, err := s.Reload(ctx)
+		if err != nil {
+			s.printf("⚠️ Keeping previous catalog: %s\n", style.Warning(err))
+			continue
+		}
+		if reloaded {
+			s.printf("🔄 Reloaded catalog\n")
+		}
+	}
+}
+
+// Handler returns the handler serving the web view and JSON API.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", s.handleIndex)
+	mux.HandleFunc("GET /api/status", s.handleStatus)
+	mux.HandleFunc("GET /api/environments", s.handleEnvironments)
+	mux.HandleFunc("GET /api/environments/{env}", s.handleEnvironment)
+	mux.HandleFunc("GET /api/environments/{env}/releases/{release}", s.handleRelease)
+	mux.HandleFunc("GET /api/environments/{env}/releases/{release}/links", s.handleReleaseLinks)
+	mux.HandleFunc("GET /api/environments/{env}/releases/{release}/values", s.handleReleaseValues)
+	mux.HandleFunc("GET /api/projects", s.handleProjects)
+	mux.HandleFunc("GET /api/projects/{project}", s.handleProject)
+	mux.HandleFunc("GET /api/releases", s.handleReleases)
+	return mux
+}
+
+// getCatalog returns the currently loaded catalog, which is never mutated once loaded.
+func (s *Server) getCatalog() *catalog.Catalog {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.catalog
+}
+
+func (s *Server) handleIndex(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = w.Write(indexHTML)
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, _ *http.Request) {
+	s.mu.RLock()
+	loadedAt := s.loadedAt
+	s.mu.RUnlock()
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"loadedAt":             loadedAt.UTC().Format(time.RFC3339),
+		"referenceEnvironment": s.opts.ReferenceEnvironment,
+	})
+}
+
+func (s *Server) handleEnvironments(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, s.getCatalog().Environments)
+}
+
+func (s *Server) handleEnvironment(w http.ResponseWriter, r *http.Request) {
+	env, err := v1alpha1.GetEnvironmentByName(s.getCatalog().Environments, r.PathValue("env"))
+	if err != nil || env == nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("environment %s not found", r.PathValue("env")))
+		return
+	}
+	writeJSON(w, http.StatusOK, env)
+}
+
+func (s *Server) handleProjects(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, s.getCatalog().Projects)
+}
+
+func (s *Server) handleProject(w http.ResponseWriter, r *http.Request) {
+	for _, project := range s.getCatalog().Projects {
+		if project.Name == r.PathValue("project") {
+			writeJSON(w, http.StatusOK, project)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, fmt.Errorf("project %s not found", r.PathValue("project")))
+}
+
+// handleReleases returns the release matrix, optionally restricted to the comma-separated environments of the env
+// query parameter and compared against the reference environment of the ref query parameter.
+func (s *Server) handleReleases(w http.ResponseWriter, r *http.Request) {
+	var environments []string
+	if env := r.URL.Query().Get("env"); env != "" {
+		environments = strings.Split(env, ",")
+	}
+
+	reference := s.opts.ReferenceEnvironment
+	if ref := r.URL.Query().Get("ref"); ref != "" {
+		reference = ref
+	}
+
+	releaseList, err := list.GetReleaseList(s.getCatalog(), list.Params{
+		SelectedEnvs:         environments,
+		ReferenceEnvironment: reference,
+		Charts:               s.opts.Charts,
+	})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("getting release list: %w", err))
+		return
+	}
+	writeJSON(w, http.StatusOK, releaseList)
+}
+
+func (s *Server) handleRelease(w http.ResponseWriter, r *http.Request) {
+	if release := s.lookupRelease(w, r); release != nil {
+		writeJSON(w, http.StatusOK, release)
+	}
+}
+
+func (s *Server) handleReleaseLinks(w http.ResponseWriter, r *http.Request) {
+	release := s.lookupRelease(w, r)
+	if release == nil {
+		return
+	}
+	releaseLinks, err := s.opts.Links.GetReleaseLinks(release)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("getting release links: %w", err))
+		return
+	}
+	writeJSON(w, http.StatusOK, releaseLinks)
+}
+
+func (s *Server) handleReleaseValues(w http.ResponseWriter, r *http.Request) {
+	release := s.lookupRelease(w, r)
+	if release == nil {
+		return
+	}
+	chart, err := s.opts.Charts.GetReleaseChartFS(r.Context(), release)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("getting release chart: %w", err))
+		return
+	}
+	values, err := render.HydrateValues(release, chart)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("hydrating values: %w", err))
+		return
+	}
+	writeJSON(w, http.StatusOK, values)
+}
+
+// lookupRelease returns the release of request path, or writes a not found error and returns nil.
+func (s *Server) lookupRelease(w http.ResponseWriter, r *http.Request) *v1alpha1.Release {
+	release, err := s.getCatalog().LookupRelease(r.PathValue("env"), r.PathValue("release"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return nil
+	}
+	return release
+}
+
+func (s *Server) printf(format string, args ...any) {
+	if s.opts.Out != nil {
+		_, _ = fmt.Fprintf(s.opts.Out, format, args...)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(value)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
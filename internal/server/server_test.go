@@ -0,0 +1,201 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/nestoca/joy/api/v1alpha1"
+	"github.com/nestoca/joy/internal/links"
+	"github.com/nestoca/joy/internal/release/list"
+	"github.com/nestoca/joy/internal/testutils"
+	"github.com/nestoca/joy/pkg/catalog"
+	"github.com/nestoca/joy/pkg/helm"
+)
+
+// testCharts resolves releases to an empty local chart by default.
+func testCharts(t *testing.T) helm.ChartCache {
+	return helm.ChartCache{
+		Refs:            map[string]helm.Chart{"generic": {RepoURL: "file://" + t.TempDir(), Name: "chart", Version: "1.0.0"}},
+		DefaultChartRef: "generic",
+	}
+}
+
+func newTestServer(t *testing.T) (*Server, string) {
+	dir := testutils.CopyToTempDir(t, "testdata/catalog")
+
+	cat, err := catalog.Load(context.Background(), dir, nil)
+	require.NoError(t, err)
+
+	server, err := New(Opts{
+		CatalogDir:           dir,
+		ReferenceEnvironment: "staging",
+		Charts:               testCharts(t),
+		Links: &links.ProviderMock{
+			GetReleaseLinksFunc: func(release *v1alpha1.Release) (map[string]string, error) {
+				return map[string]string{"logs": "https://logs.example.com/" + release.Name}, nil
+			},
+		},
+	}, cat)
+	require.NoError(t, err)
+	return server, dir
+}
+
+func get(t *testing.T, handler http.Handler, path string, value any) int {
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+	if value != nil {
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), value), recorder.Body.String())
+	}
+	return recorder.Code
+}
+
+func TestServer(t *testing.T) {
+	server, _ := newTestServer(t)
+	handler := server.Handler()
+
+	t.Run("index", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+		require.Equal(t, http.StatusOK, recorder.Code)
+		require.Contains(t, recorder.Body.String(), "/api/releases")
+	})
+
+	t.Run("environments", func(t *testing.T) {
+		var environments []v1alpha1.Environment
+		require.Equal(t, http.StatusOK, get(t, handler, "/api/environments", &environments))
+		require.Len(t, environments, 2)
+		require.Equal(t, "dev", environments[0].Name)
+
+		var env v1alpha1.Environment
+		require.Equal(t, http.StatusOK, get(t, handler, "/api/environments/staging", &env))
+		require.Equal(t, "staging", env.Name)
+
+		var body map[string]string
+		require.Equal(t, http.StatusNotFound, get(t, handler, "/api/environments/prod", &body))
+		require.Equal(t, "environment prod not found", body["error"])
+	})
+
+	t.Run("projects", func(t *testing.T) {
+		var projects []v1alpha1.Project
+		require.Equal(t, http.StatusOK, get(t, handler, "/api/projects", &projects))
+		require.Len(t, projects, 1)
+
+		var project v1alpha1.Project
+		require.Equal(t, http.StatusOK, get(t, handler, "/api/projects/my-project", &project))
+		require.Equal(t, "my-project", project.Name)
+
+		require.Equal(t, http.StatusNotFound, get(t, handler, "/api/projects/unknown", nil))
+	})
+
+	t.Run("releases", func(t *testing.T) {
+		var releaseList list.ReleaseList
+		require.Equal(t, http.StatusOK, get(t, handler, "/api/releases", &releaseList))
+		require.Equal(t, []string{"dev", "staging"}, releaseList.Environments)
+		require.Equal(t, "staging", releaseList.ReferenceEnvironment)
+		require.Len(t, releaseList.CrossReleases, 1)
+		require.Equal(t, "1.1.0", releaseList.CrossReleases[0].Releases[0].DisplayVersion)
+		require.Equal(t, list.AheadStatus, releaseList.CrossReleases[0].Releases[0].Status)
+
+		require.Equal(t, http.StatusOK, get(t, handler, "/api/releases?env=dev&ref=dev", &releaseList))
+		require.Equal(t, []string{"dev"}, releaseList.Environments)
+		require.Equal(t, "dev", releaseList.ReferenceEnvironment)
+	})
+
+	t.Run("release", func(t *testing.T) {
+		var release v1alpha1.Release
+		require.Equal(t, http.StatusOK, get(t, handler, "/api/environments/dev/releases/api", &release))
+		require.Equal(t, "1.1.0", release.Spec.Version)
+
+		require.Equal(t, http.StatusNotFound, get(t, handler, "/api/environments/dev/releases/unknown", nil))
+
+		var releaseLinks map[string]string
+		require.Equal(t, http.StatusOK, get(t, handler, "/api/environments/dev/releases/api/links", &releaseLinks))
+		require.Equal(t, map[string]string{"logs": "https://logs.example.com/api"}, releaseLinks)
+
+		var values map[string]any
+		require.Equal(t, http.StatusOK, get(t, handler, "/api/environments/staging/releases/api/values", &values))
+		require.Equal(t, "staging.example.com", values["host"])
+	})
+}
+
+func TestServerReload(t *testing.T) {
+	server, dir := newTestServer(t)
+
+	reloaded, err := server.Reload(context.Background())
+	require.NoError(t, err)
+	require.False(t, reloaded)
+
+	releasePath := filepath.Join(dir, "environments/staging/releases/api.yaml")
+	content, err := os.ReadFile(releasePath)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(releasePath, []byte(strings.Replace(string(content), "version: 1.0.0", "version: 1.10.0", 1)), 0o644))
+
+	reloaded, err = server.Reload(context.Background())
+	require.NoError(t, err)
+	require.True(t, reloaded)
+
+	var release v1alpha1.Release
+	require.Equal(t, http.StatusOK, get(t, server.Handler(), "/api/environments/staging/releases/api", &release))
+	require.Equal(t, "1.10.0", release.Spec.Version)
+
+	require.NoError(t, os.WriteFile(releasePath, []byte("invalid: [yaml"), 0o644))
+
+	_, err = server.Reload(context.Background())
+	require.Error(t, err)
+	require.Equal(t, http.StatusOK, get(t, server.Handler(), "/api/environments/staging/releases/api", &release))
+	require.Equal(t, "1.10.0", release.Spec.Version)
+}
+
+func TestServerConcurrentReleaseValues(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	// Simulates helm pull --untar, which fails when the chart directory already exists
+	var pulls atomic.Int32
+	server.opts.Charts = helm.ChartCache{
+		Refs:            map[string]helm.Chart{"generic": {RepoURL: "registry.example.com/charts", Name: "chart", Version: "1.0.0"}},
+		DefaultChartRef: "generic",
+		Root:            t.TempDir(),
+		Puller: &helm.PullRendererMock{
+			PullFunc: func(ctx context.Context, opts helm.PullOptions) error {
+				pulls.Add(1)
+				chartDir := filepath.Join(opts.OutputDir, opts.Chart.Name)
+				if _, err := os.Stat(chartDir); err == nil {
+					return fmt.Errorf("failed to untar: a file or directory with the name %s already exists", chartDir)
+				}
+				time.Sleep(50 * time.Millisecond)
+				return os.MkdirAll(chartDir, 0o755)
+			},
+		},
+	}
+
+	handler := server.Handler()
+	var wg sync.WaitGroup
+	codes := make([]int, 10)
+	for i := range codes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/environments/dev/releases/api/values", nil))
+			codes[i] = recorder.Code
+		}()
+	}
+	wg.Wait()
+
+	for _, code := range codes {
+		require.Equal(t, http.StatusOK, code)
+	}
+	require.Equal(t, int32(1), pulls.Load())
+}
@@ -0,0 +1,25 @@
+package style
+
+import (
+	"io"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// NewTable returns a borderless table writing to w, with left-aligned columns separated by two spaces and headers
+// rendered as given.
+func NewTable(w io.Writer) *tablewriter.Table {
+	table := tablewriter.NewWriter(w)
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetHeaderLine(false)
+	table.SetAutoWrapText(false)
+	table.SetAutoFormatHeaders(false)
+	table.SetBorder(false)
+	table.SetColumnSeparator("")
+	table.SetRowSeparator("")
+	table.SetCenterSeparator("")
+	table.SetTablePadding("  ")
+	table.SetNoWhiteSpace(true)
+	return table
+}
@@ -0,0 +1,16 @@
+package testutils
+
+import (
+	"testing"
+
+	cp "github.com/otiai10/copy"
+	"github.com/stretchr/testify/require"
+)
+
+// CopyToTempDir copies given directory, typically a catalog fixture under testdata, to a temporary directory and
+// returns its path, such that tests can modify it without altering the fixture.
+func CopyToTempDir(t *testing.T, dir string) string {
+	tempDir := t.TempDir()
+	require.NoError(t, cp.Copy(dir, tempDir))
+	return tempDir
+}
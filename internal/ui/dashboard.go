@@ -0,0 +1,527 @@
+package ui
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/nestoca/joy/api/v1alpha1"
+	"github.com/nestoca/joy/internal/links"
+	"github.com/nestoca/joy/internal/release/list"
+	"github.com/nestoca/joy/internal/release/promote"
+	"github.com/nestoca/joy/internal/release/render"
+	"github.com/nestoca/joy/internal/style"
+	"github.com/nestoca/joy/pkg/catalog"
+	"github.com/nestoca/joy/pkg/helm"
+)
+
+type Opts struct {
+	Catalog              *catalog.Catalog
+	Terminal             *Terminal
+	ReferenceEnvironment string
+	SelectedEnvironments []string
+
+	// Charts is used to hydrate release values.
+	Charts helm.ChartCache
+
+	// Links is used to display release links.
+	Links links.Provider
+
+	// Promotion promotes selected releases, with its prompt provider and output replaced by ones drawing within
+	// the dashboard.
+	Promotion promote.Promotion
+
+	MaxColumnWidth int
+}
+
+type view int
+
+const (
+	matrixView view = iota
+	detailView
+	logView
+)
+
+type detailTab int
+
+const (
+	yamlTab detailTab = iota
+	linksTab
+	valuesTab
+)
+
+var detailTabNames = []string{"YAML", "Links", "Values"}
+
+// Dashboard is a full-screen matrix of releases across environments, allowing to filter releases, drill down into
+// their details and promote them.
+type Dashboard struct {
+	opts      Opts
+	promotion promote.Promotion
+	log       *Log
+	context   context.Context
+
+	releaseList list.ReleaseList
+	rows        []list.CrossRelease
+	marked      map[string]bool
+
+	filter        string
+	editingFilter bool
+
+	view          view
+	row, col      int
+	top, left     int
+	tab           detailTab
+	detailRelease *v1alpha1.Release
+	details       map[detailTab][]string
+	scroll        int
+
+	status string
+	quit   bool
+}
+
+func NewDashboard(ctx context.Context, opts Opts) (*Dashboard, error) {
+	releaseList, err := list.GetReleaseList(opts.Catalog, list.Params{
+		SelectedEnvs:         opts.SelectedEnvironments,
+		ReferenceEnvironment: opts.ReferenceEnvironment,
+		Charts:               opts.Charts,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("getting release list: %w", err)
+	}
+
+	log := &Log{}
+	promotion := opts.Promotion
+	promotion.PromptProvider = NewPromptProvider(opts.Terminal, log)
+	promotion.Out = log
+
+	dashboard := &Dashboard{
+		opts:        opts,
+		promotion:   promotion,
+		log:         log,
+		context:     ctx,
+		releaseList: releaseList,
+		marked:      make(map[string]bool),
+	}
+	dashboard.applyFilter()
+	return dashboard, nil
+}
+
+// Run displays the dashboard until user quits.
+func Run(ctx context.Context, opts Opts) error {
+	dashboard, err := NewDashboard(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	if err := opts.Terminal.Start(); err != nil {
+		return err
+	}
+	defer opts.Terminal.Stop()
+
+	for !dashboard.quit {
+		opts.Terminal.Draw(dashboard.Render(opts.Terminal.Size()))
+
+		key, err := opts.Terminal.ReadKey()
+		if err != nil {
+			return fmt.Errorf("reading key: %w", err)
+		}
+		dashboard.HandleKey(key)
+	}
+	return nil
+}
+
+// HandleKey updates the state of dashboard according to given key pressed by user.
+func (d *Dashboard) HandleKey(key Key) {
+	d.status = ""
+
+	if key.Special == KeyCtrlC {
+		d.quit = true
+		return
+	}
+
+	switch d.view {
+	case matrixView:
+		if d.editingFilter {
+			d.handleFilterKey(key)
+		} else {
+			d.handleMatrixKey(key)
+		}
+	case detailView:
+		d.handleDetailKey(key)
+	case logView:
+		d.handleLogKey(key)
+	}
+}
+
+func (d *Dashboard) handleMatrixKey(key Key) {
+	switch {
+	case key.Rune == 'q':
+		d.quit = true
+	case key.Special == KeyUp || key.Rune == 'k':
+		d.row = max(d.row-1, 0)
+	case key.Special == KeyDown || key.Rune == 'j':
+		d.row = max(min(d.row+1, len(d.rows)-1), 0)
+	case key.Special == KeyPageUp:
+		d.row = max(d.row-10, 0)
+	case key.Special == KeyPageDown:
+		d.row = max(min(d.row+10, len(d.rows)-1), 0)
+	case key.Special == KeyLeft || key.Rune == 'h':
+		d.col = max(d.col-1, 0)
+	case key.Special == KeyRight || key.Rune == 'l':
+		d.col = max(min(d.col+1, len(d.releaseList.Environments)-1), 0)
+	case key.Rune == '/':
+		d.editingFilter = true
+	case key.Rune == ' ':
+		if name := d.currentReleaseName(); name != "" {
+			d.marked[name] = !d.marked[name]
+		}
+	case key.Special == KeyEnter:
+		d.openDetails()
+	case key.Rune == 'p':
+		d.promote()
+	}
+}
+
+func (d *Dashboard) handleFilterKey(key Key) {
+	switch {
+	case key.Special == KeyEnter:
+		d.editingFilter = false
+	case key.Special == KeyEscape:
+		d.editingFilter = false
+		d.filter = ""
+	case key.Special == KeyBackspace:
+		runes := []rune(d.filter)
+		d.filter = string(runes[:max(len(runes)-1, 0)])
+	case key.Rune != 0:
+		d.filter += string(key.Rune)
+	}
+	d.applyFilter()
+}
+
+func (d *Dashboard) handleDetailKey(key Key) {
+	switch {
+	case key.Special == KeyEscape || key.Rune == 'q':
+		d.view = matrixView
+	case key.Special == KeyTab || key.Special == KeyRight || key.Rune == 'l':
+		d.tab = (d.tab + 1) % detailTab(len(detailTabNames))
+		d.scroll = 0
+	case key.Special == KeyLeft || key.Rune == 'h':
+		d.tab = (d.tab + detailTab(len(detailTabNames)) - 1) % detailTab(len(detailTabNames))
+		d.scroll = 0
+	default:
+		d.handleScrollKey(key)
+	}
+}
+
+func (d *Dashboard) handleLogKey(key Key) {
+	switch {
+	case key.Special == KeyEscape || key.Special == KeyEnter || key.Rune == 'q':
+		d.view = matrixView
+	default:
+		d.handleScrollKey(key)
+	}
+}
+
+func (d *Dashboard) handleScrollKey(key Key) {
+	switch {
+	case key.Special == KeyUp || key.Rune == 'k':
+		d.scroll = max(d.scroll-1, 0)
+	case key.Special == KeyDown || key.Rune == 'j':
+		d.scroll++
+	case key.Special == KeyPageUp:
+		d.scroll = max(d.scroll-10, 0)
+	case key.Special == KeyPageDown:
+		d.scroll += 10
+	}
+}
+
+// applyFilter keeps only the releases whose name contains the filter, regardless of case.
+func (d *Dashboard) applyFilter() {
+	d.rows = nil
+	for _, release := range d.releaseList.CrossReleases {
+		if strings.Contains(strings.ToLower(release.Name), strings.ToLower(d.filter)) {
+			d.rows = append(d.rows, release)
+		}
+	}
+	d.row = max(min(d.row, len(d.rows)-1), 0)
+}
+
+func (d *Dashboard) currentReleaseName() string {
+	if d.row >= len(d.rows) {
+		return ""
+	}
+	return d.rows[d.row].Name
+}
+
+func (d *Dashboard) currentRelease() *v1alpha1.Release {
+	if d.row >= len(d.rows) || d.col >= len(d.rows[d.row].Releases) {
+		return nil
+	}
+	return d.rows[d.row].Releases[d.col].Release
+}
+
+func (d *Dashboard) currentEnvironment() *v1alpha1.Environment {
+	if d.col >= len(d.releaseList.Environments) {
+		return nil
+	}
+	env, _ := v1alpha1.GetEnvironmentByName(d.opts.Catalog.Environments, d.releaseList.Environments[d.col])
+	return env
+}
+
+func (d *Dashboard) openDetails() {
+	release := d.currentRelease()
+	if release == nil {
+		d.status = fmt.Sprintf("release %s not found in environment %s", d.currentReleaseName(), d.releaseList.Environments[d.col])
+		return
+	}
+	d.detailRelease = release
+	d.details = make(map[detailTab][]string)
+	d.tab = yamlTab
+	d.scroll = 0
+	d.view = detailView
+}
+
+// detailLines returns the lines of current detail tab, computing them only once per release as hydrating values may
+// require pulling the release chart.
+func (d *Dashboard) detailLines() []string {
+	if lines, ok := d.details[d.tab]; ok {
+		return lines
+	}
+
+	var text string
+	var err error
+	switch d.tab {
+	case yamlTab:
+		text = string(d.detailRelease.File.Yaml)
+	case linksTab:
+		text, err = d.formatLinks()
+	case valuesTab:
+		text, err = d.formatHydratedValues()
+	}
+	if err != nil {
+		text = style.Warning(err.Error())
+	}
+
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	d.details[d.tab] = lines
+	return lines
+}
+
+func (d *Dashboard) formatLinks() (string, error) {
+	if d.opts.Links == nil {
+		return "no links available", nil
+	}
+	releaseLinks, err := d.opts.Links.GetReleaseLinks(d.detailRelease)
+	if err != nil {
+		return "", fmt.Errorf("getting release links: %w", err)
+	}
+	if len(releaseLinks) == 0 {
+		return "no links defined for release", nil
+	}
+
+	var names []string
+	width := 0
+	for name := range releaseLinks {
+		names = append(names, name)
+		width = max(width, len(name))
+	}
+	slices.Sort(names)
+
+	var lines []string
+	for _, name := range names {
+		lines = append(lines, pad(name, width)+"  "+style.Link(releaseLinks[name]))
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+func (d *Dashboard) formatHydratedValues() (string, error) {
+	chart, err := d.opts.Charts.GetReleaseChartFS(d.context, d.detailRelease)
+	if err != nil {
+		return "", fmt.Errorf("getting release chart: %w", err)
+	}
+	values, err := render.HydrateValues(d.detailRelease, chart)
+	if err != nil {
+		return "", fmt.Errorf("hydrating values: %w", err)
+	}
+	data, err := yaml.Marshal(values)
+	if err != nil {
+		return "", fmt.Errorf("marshalling values: %w", err)
+	}
+	return string(data), nil
+}
+
+// promote promotes marked releases, or current one if none are marked, from the environment of current column, then
+// displays the promotion log.
+func (d *Dashboard) promote() {
+	sourceEnv := d.currentEnvironment()
+	if sourceEnv == nil {
+		return
+	}
+
+	var releases []string
+	for _, release := range d.releaseList.CrossReleases {
+		if d.marked[release.Name] {
+			releases = append(releases, release.Name)
+		}
+	}
+	if len(releases) == 0 {
+		if name := d.currentReleaseName(); name != "" {
+			releases = []string{name}
+		}
+	}
+	if len(releases) == 0 {
+		return
+	}
+
+	d.log.Reset()
+	_, err := d.promotion.Promote(promote.Opts{
+		Catalog:              d.opts.Catalog,
+		SourceEnv:            sourceEnv,
+		Releases:             releases,
+		ReleasesFiltered:     true,
+		SelectedEnvironments: v1alpha1.GetEnvironmentsByNames(d.opts.Catalog.Environments, d.opts.SelectedEnvironments),
+		MaxColumnWidth:       d.opts.MaxColumnWidth,
+	})
+	if err != nil {
+		_, _ = fmt.Fprintf(d.log, "❌ %v\n", err)
+	} else {
+		d.marked = make(map[string]bool)
+	}
+
+	d.view = logView
+	d.scroll = len(d.log.Lines())
+}
+
+// Render returns the lines to draw for current state of dashboard, fitting in given size.
+func (d *Dashboard) Render(width, height int) []string {
+	switch d.view {
+	case detailView:
+		title := fmt.Sprintf("%s%s", style.ResourceEnvPrefix(d.detailRelease.Environment.Name+"/"), style.Resource(d.detailRelease.Name))
+		var tabs []string
+		for i, name := range detailTabNames {
+			if detailTab(i) == d.tab {
+				tabs = append(tabs, reverseVideo+" "+name+" "+resetStyle)
+			} else {
+				tabs = append(tabs, " "+name+" ")
+			}
+		}
+		header := []string{title, strings.Join(tabs, " "), ""}
+		return d.renderScrollable(header, d.detailLines(), "←/→ switch tab • ↑/↓ scroll • esc back", width, height)
+	case logView:
+		return d.renderScrollable([]string{style.Resource("Promotion"), ""}, d.log.Lines(), "↑/↓ scroll • esc back", width, height)
+	default:
+		return d.renderMatrix(width, height)
+	}
+}
+
+func (d *Dashboard) renderScrollable(header, body []string, help string, width, height int) []string {
+	bodyHeight := max(height-len(header)-2, 1)
+	d.scroll = max(min(d.scroll, len(body)-bodyHeight), 0)
+
+	lines := append([]string{}, header...)
+	for _, line := range body[d.scroll:min(d.scroll+bodyHeight, len(body))] {
+		if !strings.Contains(line, "\x1b") {
+			line = truncate(line, width)
+		}
+		lines = append(lines, line)
+	}
+	for len(lines) < height-1 {
+		lines = append(lines, "")
+	}
+	return append(lines, style.SecondaryInfo(help))
+}
+
+func (d *Dashboard) renderMatrix(width, height int) []string {
+	nameWidth := len("NAME")
+	for _, release := range d.rows {
+		nameWidth = max(nameWidth, len([]rune(release.Name)))
+	}
+
+	columnWidth := 0
+	for _, env := range d.releaseList.Environments {
+		columnWidth = max(columnWidth, len(env))
+	}
+	for _, release := range d.rows {
+		for _, rel := range release.Releases {
+			columnWidth = max(columnWidth, len([]rune(rel.DisplayVersion)))
+		}
+	}
+	columnWidth = min(columnWidth, max(d.opts.MaxColumnWidth, 10)) + 2
+
+	// Scroll environments horizontally and releases vertically to keep current cell visible
+	visibleColumns := max((width-nameWidth-4)/columnWidth, 1)
+	d.left = min(max(d.left, d.col-visibleColumns+1), d.col)
+	bodyHeight := max(height-6, 1)
+	d.top = min(max(d.top, d.row-bodyHeight+1), d.row)
+
+	title := fmt.Sprintf("%s · reference: %s · %d/%d releases",
+		style.Resource("joy"),
+		cmp.Or(d.opts.ReferenceEnvironment, "none"),
+		len(d.rows),
+		len(d.releaseList.CrossReleases))
+	if d.editingFilter || d.filter != "" {
+		cursor := ""
+		if d.editingFilter {
+			cursor = "█"
+		}
+		title += " · filter: " + d.filter + cursor
+	}
+
+	header := "    " + pad("NAME", nameWidth) + "  "
+	for i := d.left; i < min(d.left+visibleColumns, len(d.releaseList.Environments)); i++ {
+		header += pad(strings.ToUpper(d.releaseList.Environments[i]), columnWidth)
+	}
+
+	lines := []string{title, "", style.SecondaryInfo(header)}
+	for i := d.top; i < min(d.top+bodyHeight, len(d.rows)); i++ {
+		release := d.rows[i]
+
+		prefix := "  "
+		if i == d.row {
+			prefix = style.Resource("› ")
+		}
+		if d.marked[release.Name] {
+			prefix += style.OK("● ")
+		} else {
+			prefix += "  "
+		}
+
+		line := prefix + pad(release.Name, nameWidth) + "  "
+		for j := d.left; j < min(d.left+visibleColumns, len(release.Releases)); j++ {
+			rel := release.Releases[j]
+			cell := pad(rel.DisplayVersion, columnWidth-2)
+			if i == d.row && j == d.col {
+				cell = reverseVideo + cell + resetStyle
+			} else {
+				cell = colorizeVersion(cell, rel.Status)
+			}
+			line += cell + "  "
+		}
+		lines = append(lines, line)
+	}
+
+	for len(lines) < height-2 {
+		lines = append(lines, "")
+	}
+	lines = append(lines, style.Warning(d.status))
+	return append(lines, style.SecondaryInfo("↑/↓/←/→ move • / filter • space mark • enter details • p promote • q quit"))
+}
+
+func colorizeVersion(version, status string) string {
+	switch status {
+	case list.PrereleaseStatus:
+		return style.DirtyVersion(version)
+	case list.BehindStatus:
+		return style.BehindVersion(version)
+	case list.AheadStatus:
+		return style.AheadVersion(version)
+	case list.InSyncStatus:
+		return style.InSyncVersion(version)
+	default:
+		return version
+	}
+}
@@ -0,0 +1,134 @@
+package ui
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/acarl005/stripansi"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nestoca/joy/api/v1alpha1"
+	"github.com/nestoca/joy/internal/links"
+	"github.com/nestoca/joy/pkg/catalog"
+	"github.com/nestoca/joy/pkg/helm"
+)
+
+func newTestCatalog(t *testing.T) *catalog.Catalog {
+	cat, err := catalog.Load(context.Background(), "testdata/catalog", nil)
+	require.NoError(t, err)
+	return cat
+}
+
+func newTestDashboard(t *testing.T, input string) (*Dashboard, *Log) {
+	linksProvider := &links.ProviderMock{
+		GetReleaseLinksFunc: func(release *v1alpha1.Release) (map[string]string, error) {
+			return map[string]string{"logs": "https://logs.example.com/" + release.Name}, nil
+		},
+	}
+
+	dashboard, err := NewDashboard(context.Background(), Opts{
+		Catalog:              newTestCatalog(t),
+		Terminal:             NewTerminal(strings.NewReader(input), &bytes.Buffer{}),
+		ReferenceEnvironment: "staging",
+		Links:                linksProvider,
+		Charts: helm.ChartCache{
+			Refs:            map[string]helm.Chart{"generic": {RepoURL: "file://" + t.TempDir(), Name: "chart", Version: "1.0.0"}},
+			DefaultChartRef: "generic",
+		},
+		MaxColumnWidth: 20,
+	})
+	require.NoError(t, err)
+	return dashboard, dashboard.log
+}
+
+func renderText(dashboard *Dashboard) string {
+	return stripansi.Strip(strings.Join(dashboard.Render(100, 20), "\n"))
+}
+
+func press(dashboard *Dashboard, keys ...Key) {
+	for _, key := range keys {
+		dashboard.HandleKey(key)
+	}
+}
+
+func runes(text string) []Key {
+	var keys []Key
+	for _, r := range text {
+		keys = append(keys, Key{Rune: r})
+	}
+	return keys
+}
+
+func TestDashboardMatrix(t *testing.T) {
+	dashboard, _ := newTestDashboard(t, "")
+
+	output := renderText(dashboard)
+	require.Contains(t, output, "reference: staging · 3/3 releases")
+	require.Contains(t, output, "NAME      DEV")
+	require.Contains(t, output, "› ")
+	require.Regexp(t, `api\s+1\.1\.0\s+1\.0\.0`, output)
+	require.Regexp(t, `cron-job\s+-\s+3\.0\.0`, output)
+
+	press(dashboard, Key{Rune: '/'})
+	press(dashboard, runes("WORK")...)
+	output = renderText(dashboard)
+	require.Contains(t, output, "filter: WORK█")
+	require.Contains(t, output, "1/3 releases")
+	require.NotContains(t, output, "cron-job")
+
+	press(dashboard, Key{Special: KeyEscape})
+	require.Len(t, dashboard.rows, 3)
+
+	press(dashboard, Key{Special: KeyDown}, Key{Rune: ' '}, Key{Special: KeyDown}, Key{Special: KeyDown})
+	require.Equal(t, "worker", dashboard.currentReleaseName())
+	require.Equal(t, map[string]bool{"cron-job": true}, dashboard.marked)
+
+	press(dashboard, Key{Special: KeyUp}, Key{Special: KeyEnter})
+	require.Equal(t, matrixView, dashboard.view)
+	require.Contains(t, renderText(dashboard), "release cron-job not found in environment dev")
+
+	press(dashboard, Key{Rune: 'q'})
+	require.True(t, dashboard.quit)
+}
+
+func TestDashboardDetails(t *testing.T) {
+	dashboard, _ := newTestDashboard(t, "")
+
+	press(dashboard, Key{Special: KeyRight}, Key{Special: KeyEnter})
+	require.Equal(t, detailView, dashboard.view)
+
+	output := renderText(dashboard)
+	require.Contains(t, output, "staging/api")
+	require.Contains(t, output, "version: 1.0.0")
+
+	press(dashboard, Key{Special: KeyTab})
+	require.Regexp(t, `logs\s+https://logs.example.com/api`, renderText(dashboard))
+
+	press(dashboard, Key{Special: KeyTab})
+	require.Contains(t, renderText(dashboard), "host: staging.example.com")
+
+	press(dashboard, Key{Special: KeyEscape})
+	require.Equal(t, matrixView, dashboard.view)
+}
+
+func TestDashboardPromote(t *testing.T) {
+	// Select staging as target environment, then cancel when asked what to do after preview
+	dashboard, log := newTestDashboard(t, "\r\x1b")
+
+	press(dashboard, Key{Rune: 'p'})
+	require.Equal(t, logView, dashboard.view)
+
+	output := stripansi.Strip(strings.Join(log.Lines(), "\n"))
+	require.Contains(t, output, "Update release staging/api")
+	require.Contains(t, output, "-  version: 1.0.0\n+  version: 1.1.0")
+	require.Contains(t, output, "Operation cancelled")
+
+	press(dashboard, Key{Special: KeyEscape})
+	require.Equal(t, matrixView, dashboard.view)
+
+	// Promoting from staging fails as no environment promotes from it
+	press(dashboard, Key{Special: KeyRight}, Key{Rune: 'p'})
+	require.Contains(t, strings.Join(log.Lines(), "\n"), "no target environments found to promote from staging")
+}
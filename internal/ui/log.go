@@ -0,0 +1,29 @@
+package ui
+
+import "strings"
+
+// Log collects the output of operations performed from the dashboard, such as promotions, to display it on screen.
+type Log struct {
+	lines   []string
+	partial string
+}
+
+func (l *Log) Write(p []byte) (int, error) {
+	parts := strings.Split(l.partial+string(p), "\n")
+	l.lines = append(l.lines, parts[:len(parts)-1]...)
+	l.partial = parts[len(parts)-1]
+	return len(p), nil
+}
+
+// Lines returns all lines written so far, including the last one even if not terminated by a newline yet.
+func (l *Log) Lines() []string {
+	if l.partial == "" {
+		return l.lines
+	}
+	return append(l.lines[:len(l.lines):len(l.lines)], l.partial)
+}
+
+func (l *Log) Reset() {
+	l.lines = nil
+	l.partial = ""
+}
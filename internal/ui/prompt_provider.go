@@ -0,0 +1,252 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/nestoca/joy/api/v1alpha1"
+	"github.com/nestoca/joy/internal/release/cross"
+	"github.com/nestoca/joy/internal/release/list"
+	"github.com/nestoca/joy/internal/release/promote"
+	"github.com/nestoca/joy/internal/style"
+)
+
+var errCanceled = errors.New("canceled by user")
+
+// PromptProvider is a promote.PromptProvider prompting user within the dashboard, below the tail of its log, where
+// all promotion messages are printed.
+type PromptProvider struct {
+	*promote.InteractivePromptProvider
+	terminal *Terminal
+	log      *Log
+}
+
+func NewPromptProvider(terminal *Terminal, log *Log) *PromptProvider {
+	return &PromptProvider{
+		InteractivePromptProvider: promote.NewInteractivePromptProvider(log),
+		terminal:                  terminal,
+		log:                       log,
+	}
+}
+
+func (p *PromptProvider) SelectSourceEnvironment(environments []*v1alpha1.Environment) (*v1alpha1.Environment, error) {
+	index, err := p.chooseOne("Select source promotion environment", v1alpha1.GetEnvironmentNames(environments))
+	if err != nil {
+		return nil, fmt.Errorf("prompting for source environment: %w", err)
+	}
+	return environments[index], nil
+}
+
+func (p *PromptProvider) SelectTargetEnvironment(environments []*v1alpha1.Environment) (*v1alpha1.Environment, error) {
+	index, err := p.chooseOne("Select target promotion environment", v1alpha1.GetEnvironmentNames(environments))
+	if err != nil {
+		return nil, fmt.Errorf("prompting for target environment: %w", err)
+	}
+	return environments[index], nil
+}
+
+func (p *PromptProvider) SelectReleases(releases cross.ReleaseList, maxColumnWidth int) (cross.ReleaseList, error) {
+	nameWidth := 0
+	for _, item := range releases.Items {
+		nameWidth = max(nameWidth, len(item.Name))
+	}
+
+	var options []string
+	for _, item := range releases.Items {
+		option := fmt.Sprintf("%-*s  %s", nameWidth, item.Name, truncate(list.GetReleaseDisplayVersion(item.Releases[1]), maxColumnWidth))
+		if !item.VersionInSync || !item.ValuesInSync {
+			option += " > " + truncate(list.GetReleaseDisplayVersion(item.Releases[0]), maxColumnWidth)
+		}
+		options = append(options, option)
+	}
+
+	message := fmt.Sprintf("Select releases to promote from %s to %s", releases.Environments[0].Name, releases.Environments[1].Name)
+	indices, err := p.choose(message, options, true, nil)
+	if err != nil {
+		return cross.ReleaseList{}, fmt.Errorf("prompting for releases to promote: %w", err)
+	}
+
+	var names []string
+	for _, index := range indices {
+		names = append(names, releases.Items[index].Name)
+	}
+	return releases.OnlySpecificReleases(names)
+}
+
+func (p *PromptProvider) ConfirmCreatingPromotionPullRequest(autoMerge, draft bool) (bool, error) {
+	message := "Creating a promotion pull request. Do you wish to continue?"
+	if draft {
+		message = "Creating a draft promotion pull request. Do you wish to continue?"
+	} else if autoMerge {
+		message = "Creating and auto-merging a promotion pull request. Do you wish to continue?"
+	}
+	return p.confirm(message)
+}
+
+func (p *PromptProvider) SelectPromotionAction() (string, error) {
+	actions := []string{promote.CreatePR, promote.CreateDraft, promote.SelectChanges, promote.ViewGitLog, promote.Cancel}
+	index, err := p.chooseOne("What would you like to do?", actions)
+	if errors.Is(err, errCanceled) {
+		return promote.Cancel, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("asking user for PR state: %w", err)
+	}
+	return actions[index], nil
+}
+
+func (p *PromptProvider) SelectChanges(paths []string) ([]string, error) {
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no changes found in existing target releases")
+	}
+
+	selected := make([]bool, len(paths))
+	for i := range selected {
+		selected[i] = true
+	}
+
+	indices, err := p.choose("Select changes to promote", paths, true, selected)
+	if err != nil {
+		return nil, fmt.Errorf("prompting for changes to promote: %w", err)
+	}
+
+	var selectedPaths []string
+	for _, index := range indices {
+		selectedPaths = append(selectedPaths, paths[index])
+	}
+	return selectedPaths, nil
+}
+
+func (p *PromptProvider) SelectConflictAction() (string, error) {
+	actions := []string{promote.SupersedePRs, promote.CreatePRAnyway, promote.Cancel}
+	index, err := p.chooseOne("What would you like to do?", actions)
+	if errors.Is(err, errCanceled) {
+		return promote.Cancel, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("asking user for conflict action: %w", err)
+	}
+	return actions[index], nil
+}
+
+func (p *PromptProvider) ConfirmAutoMergePullRequest() (bool, error) {
+	return p.confirm("Do you want to auto-merge the resulting PR?")
+}
+
+func (p *PromptProvider) confirm(message string) (bool, error) {
+	index, err := p.chooseOne(message, []string{"Yes", "No"})
+	if errors.Is(err, errCanceled) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("asking user for confirmation: %w", err)
+	}
+	return index == 0, nil
+}
+
+func (p *PromptProvider) chooseOne(message string, options []string) (int, error) {
+	indices, err := p.choose(message, options, false, nil)
+	if err != nil {
+		return 0, err
+	}
+	return indices[0], nil
+}
+
+// choose prompts user to pick one or, when multi is true, any number of given options, initially selected as per
+// given selection, and returns the indices of picked options.
+func (p *PromptProvider) choose(message string, options []string, multi bool, selected []bool) ([]int, error) {
+	if len(options) == 0 {
+		return nil, fmt.Errorf("no options to choose from")
+	}
+	if selected == nil {
+		selected = make([]bool, len(options))
+	}
+
+	cursor := 0
+	for {
+		p.draw(message, options, multi, cursor, selected)
+
+		key, err := p.terminal.ReadKey()
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case key.Special == KeyUp || key.Rune == 'k':
+			cursor = max(cursor-1, 0)
+		case key.Special == KeyDown || key.Rune == 'j':
+			cursor = min(cursor+1, len(options)-1)
+		case key.Rune == ' ' && multi:
+			selected[cursor] = !selected[cursor]
+		case key.Special == KeyEscape || key.Special == KeyCtrlC:
+			return nil, errCanceled
+		case key.Special == KeyEnter:
+			if !multi {
+				return []int{cursor}, nil
+			}
+			var indices []int
+			for i, ok := range selected {
+				if ok {
+					indices = append(indices, i)
+				}
+			}
+			if len(indices) > 0 {
+				return indices, nil
+			}
+		}
+	}
+}
+
+func (p *PromptProvider) draw(message string, options []string, multi bool, cursor int, selected []bool) {
+	_, height := p.terminal.Size()
+
+	// Keep at least a few lines of log visible above the options, scrolling options to keep cursor visible
+	pageSize := max(min(len(options), height-8), 1)
+	first := min(max(cursor-pageSize+1, 0), len(options)-pageSize)
+
+	var optionLines []string
+	for i := first; i < first+pageSize; i++ {
+		prefix := "  "
+		if i == cursor {
+			prefix = style.Resource("> ")
+		}
+		if multi {
+			if selected[i] {
+				prefix += "[x] "
+			} else {
+				prefix += "[ ] "
+			}
+		}
+		optionLines = append(optionLines, prefix+options[i])
+	}
+
+	help := "↑/↓ move • enter confirm • esc cancel"
+	if multi {
+		help = "↑/↓ move • space toggle • enter confirm • esc cancel"
+	}
+
+	logLines := p.log.Lines()
+	logHeight := max(height-len(optionLines)-4, 0)
+	logLines = logLines[max(len(logLines)-logHeight, 0):]
+
+	lines := append([]string{}, logLines...)
+	lines = append(lines, "", style.Notice("? "+message))
+	lines = append(lines, optionLines...)
+	lines = append(lines, style.SecondaryInfo(help))
+	p.terminal.Draw(lines)
+}
+
+func truncate(text string, width int) string {
+	runes := []rune(text)
+	if width <= 3 || len(runes) <= width {
+		return text
+	}
+	return string(runes[:width-3]) + "..."
+}
+
+// pad truncates or pads given plain text with spaces to exactly given width.
+func pad(text string, width int) string {
+	text = truncate(text, width)
+	return text + strings.Repeat(" ", max(width-len([]rune(text)), 0))
+}
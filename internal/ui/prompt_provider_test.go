@@ -0,0 +1,76 @@
+package ui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/nestoca/joy/internal/release/promote"
+)
+
+func TestReadKey(t *testing.T) {
+	terminal := NewTerminal(strings.NewReader("a\x1b[A\x1b[B\x1b[C\x1b[D\x1b[5~\x1b[6~\r\t\x7f\x03\x1bq\x1b"), &bytes.Buffer{})
+
+	expected := []Key{
+		{Rune: 'a'},
+		{Special: KeyUp},
+		{Special: KeyDown},
+		{Special: KeyRight},
+		{Special: KeyLeft},
+		{Special: KeyPageUp},
+		{Special: KeyPageDown},
+		{Special: KeyEnter},
+		{Special: KeyTab},
+		{Special: KeyBackspace},
+		{Special: KeyCtrlC},
+		{Special: KeyEscape},
+		{Rune: 'q'},
+		{Special: KeyEscape},
+	}
+	for _, key := range expected {
+		actual, err := terminal.ReadKey()
+		require.NoError(t, err)
+		require.Equal(t, key, actual)
+	}
+}
+
+func TestPromptProvider(t *testing.T) {
+	newProvider := func(input string) (*PromptProvider, *bytes.Buffer) {
+		var out bytes.Buffer
+		log := &Log{}
+		_, _ = log.Write([]byte("previous output\n"))
+		return NewPromptProvider(NewTerminal(strings.NewReader(input), &out), log), &out
+	}
+
+	t.Run("select action", func(t *testing.T) {
+		provider, out := newProvider("jj\x1b[A\r")
+		action, err := provider.SelectPromotionAction()
+		require.NoError(t, err)
+		require.Equal(t, promote.CreateDraft, action)
+		require.Contains(t, out.String(), "previous output")
+		require.Contains(t, out.String(), "What would you like to do?")
+	})
+
+	t.Run("escape cancels action", func(t *testing.T) {
+		provider, _ := newProvider("\x1b")
+		action, err := provider.SelectPromotionAction()
+		require.NoError(t, err)
+		require.Equal(t, promote.Cancel, action)
+	})
+
+	t.Run("select changes", func(t *testing.T) {
+		provider, _ := newProvider(" j \r")
+		paths, err := provider.SelectChanges([]string{"spec.version", "spec.values.a", "spec.values.b"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"spec.values.b"}, paths)
+	})
+
+	t.Run("confirm", func(t *testing.T) {
+		provider, _ := newProvider("j\r")
+		ok, err := provider.ConfirmAutoMergePullRequest()
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+}
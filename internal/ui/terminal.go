@@ -0,0 +1,172 @@
+package ui
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// SpecialKey identifies a non-printable key.
+type SpecialKey int
+
+const (
+	NoSpecialKey SpecialKey = iota
+	KeyUp
+	KeyDown
+	KeyLeft
+	KeyRight
+	KeyPageUp
+	KeyPageDown
+	KeyEnter
+	KeyEscape
+	KeyBackspace
+	KeyTab
+	KeyCtrlC
+)
+
+// Key is a key pressed by user, either a special key or a printable rune.
+type Key struct {
+	Special SpecialKey
+	Rune    rune
+}
+
+const (
+	enterAltScreen = "\x1b[?1049h"
+	exitAltScreen  = "\x1b[?1049l"
+	hideCursor     = "\x1b[?25l"
+	showCursor     = "\x1b[?25h"
+	clearScreen    = "\x1b[H\x1b[2J"
+	reverseVideo   = "\x1b[7m"
+	resetStyle     = "\x1b[0m"
+)
+
+const (
+	defaultWidth  = 120
+	defaultHeight = 40
+)
+
+// Terminal reads keys from and draws full screens to a terminal. When input is not a terminal, such as in tests,
+// keys are read as is and screens are drawn with a default size.
+type Terminal struct {
+	in    *bufio.Reader
+	out   io.Writer
+	fd    int
+	state *term.State
+}
+
+func NewTerminal(in io.Reader, out io.Writer) *Terminal {
+	fd := -1
+	if file, ok := in.(*os.File); ok && term.IsTerminal(int(file.Fd())) {
+		fd = int(file.Fd())
+	}
+	return &Terminal{in: bufio.NewReader(in), out: out, fd: fd}
+}
+
+// Start switches terminal to raw mode and to the alternate screen, such that the user's shell is restored on Stop.
+func (t *Terminal) Start() error {
+	if t.fd != -1 {
+		state, err := term.MakeRaw(t.fd)
+		if err != nil {
+			return fmt.Errorf("switching terminal to raw mode: %w", err)
+		}
+		t.state = state
+	}
+	_, _ = fmt.Fprint(t.out, enterAltScreen+hideCursor)
+	return nil
+}
+
+// Stop restores terminal to the state it was in before Start.
+func (t *Terminal) Stop() {
+	_, _ = fmt.Fprint(t.out, showCursor+exitAltScreen)
+	if t.state != nil {
+		_ = term.Restore(t.fd, t.state)
+		t.state = nil
+	}
+}
+
+// Size returns the width and height of terminal.
+func (t *Terminal) Size() (width, height int) {
+	if t.fd != -1 {
+		if width, height, err := term.GetSize(t.fd); err == nil {
+			return width, height
+		}
+	}
+	return defaultWidth, defaultHeight
+}
+
+// Draw replaces the content of the screen with given lines.
+func (t *Terminal) Draw(lines []string) {
+	_, _ = fmt.Fprint(t.out, clearScreen+strings.Join(lines, "\r\n"))
+}
+
+// ReadKey blocks until user presses a key.
+func (t *Terminal) ReadKey() (Key, error) {
+	r, _, err := t.in.ReadRune()
+	if err != nil {
+		return Key{}, err
+	}
+
+	switch r {
+	case '\r', '\n':
+		return Key{Special: KeyEnter}, nil
+	case '\t':
+		return Key{Special: KeyTab}, nil
+	case 0x7f, '\b':
+		return Key{Special: KeyBackspace}, nil
+	case 0x03:
+		return Key{Special: KeyCtrlC}, nil
+	case 0x1b:
+		return t.readEscapeSequence(), nil
+	}
+	return Key{Rune: r}, nil
+}
+
+// readEscapeSequence reads the rest of an escape sequence, such as those sent by arrow keys, considering a lone
+// escape character as the escape key itself.
+func (t *Terminal) readEscapeSequence() Key {
+	if t.in.Buffered() == 0 {
+		return Key{Special: KeyEscape}
+	}
+	if next, _ := t.in.Peek(1); next[0] != '[' && next[0] != 'O' {
+		return Key{Special: KeyEscape}
+	}
+	_, _ = t.in.ReadByte()
+
+	var sequence []byte
+	for t.in.Buffered() > 0 {
+		b, err := t.in.ReadByte()
+		if err != nil {
+			break
+		}
+		sequence = append(sequence, b)
+		if b >= 0x40 && b <= 0x7e {
+			break
+		}
+	}
+
+	switch string(sequence) {
+	case "A":
+		return Key{Special: KeyUp}
+	case "B":
+		return Key{Special: KeyDown}
+	case "C":
+		return Key{Special: KeyRight}
+	case "D":
+		return Key{Special: KeyLeft}
+	case "5~":
+		return Key{Special: KeyPageUp}
+	case "6~":
+		return Key{Special: KeyPageDown}
+	default:
+		return Key{Special: KeyEscape}
+	}
+}
+
+// IsInteractive returns true if keys are read from an actual terminal.
+func (t *Terminal) IsInteractive() bool {
+	return t.fd != -1
+}
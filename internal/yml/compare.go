@@ -2,6 +2,7 @@ package yml
 
 import (
 	"slices"
+	"sort"
 
 	"gopkg.in/yaml.v3"
 )
@@ -47,6 +48,17 @@ func EqualWithExclusion(a, b *yaml.Node, excludedPath ...string) bool {
 	return equalWithExclusion(a, b, excludedPath, nil)
 }
 
+// EqualWithExclusions returns true if a and b are equal once the values at all given dot-separated paths are
+// removed from both sides. Unlike EqualWithExclusion, an excluded value may be present on one side only.
+func EqualWithExclusions(a, b *yaml.Node, excludedPaths ...string) bool {
+	a, b = Clone(a), Clone(b)
+	for _, path := range excludedPaths {
+		setNodeAt(unwrapDocument(a), segmentPath(path), nil)
+		setNodeAt(unwrapDocument(b), segmentPath(path), nil)
+	}
+	return equalWithExclusion(a, b, nil, nil)
+}
+
 func equalWithExclusion(a, b *yaml.Node, excludedPath, currentPath []string) bool {
 	// Check if current path is excluded
 	if len(excludedPath) > 0 && slices.Equal(currentPath, excludedPath) {
@@ -91,3 +103,105 @@ func equalWithExclusion(a, b *yaml.Node, excludedPath, currentPath []string) boo
 
 	return true
 }
+
+// DiffPaths returns the sorted dot-separated paths of values that differ between a and b, descending into mappings
+// present on both sides. Dots within keys are escaped with a backslash, as expected by MergeScope. Values of other
+// kinds, such as sequences, are compared as a whole.
+func DiffPaths(a, b *yaml.Node) []string {
+	var paths []string
+	diffPaths(unwrapDocument(a), unwrapDocument(b), nil, &paths)
+	sort.Strings(paths)
+	return paths
+}
+
+func diffPaths(a, b *yaml.Node, currentPath []string, paths *[]string) {
+	if a != nil && b != nil && a.Kind == yaml.MappingNode && b.Kind == yaml.MappingNode {
+		aMap, bMap := asMap(a), asMap(b)
+		for _, key := range dedup(append(keysOf(a), keysOf(b)...)) {
+			childPath := append(slices.Clone(currentPath), key)
+			diffPaths(aMap[key].Value, bMap[key].Value, childPath, paths)
+		}
+		return
+	}
+
+	if len(currentPath) > 0 && !equalWithExclusion(a, b, nil, nil) {
+		*paths = append(*paths, joinPath(currentPath))
+	}
+}
+
+// EnvironmentSpecificPaths returns the dot-separated paths of the values of node tagged as !lock or !local, whichever
+// environments they are scoped to. Such values within sequences are reported as the path of the whole sequence, as
+// sequence items cannot be addressed by path.
+func EnvironmentSpecificPaths(node *yaml.Node) []string {
+	var paths []string
+	environmentSpecificPaths(unwrapDocument(node), nil, &paths)
+	return paths
+}
+
+func environmentSpecificPaths(node *yaml.Node, currentPath []string, paths *[]string) {
+	if node == nil {
+		return
+	}
+	if isEnvironmentSpecific(node) {
+		*paths = append(*paths, joinPath(currentPath))
+		return
+	}
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			environmentSpecificPaths(node.Content[i+1], append(slices.Clone(currentPath), node.Content[i].Value), paths)
+		}
+	case yaml.SequenceNode:
+		if slices.ContainsFunc(node.Content, containsEnvironmentSpecific) {
+			*paths = append(*paths, joinPath(currentPath))
+		}
+	}
+}
+
+func containsEnvironmentSpecific(node *yaml.Node) bool {
+	return node != nil && (isEnvironmentSpecific(node) || slices.ContainsFunc(node.Content, containsEnvironmentSpecific))
+}
+
+// EquivalentWithExclusions returns true if a and b are equal once the values at all given dot-separated paths are
+// removed from both sides, like EqualWithExclusions, but comparing mappings by key, regardless of their order.
+func EquivalentWithExclusions(a, b *yaml.Node, excludedPaths ...string) bool {
+	a, b = unwrapDocument(Clone(a)), unwrapDocument(Clone(b))
+	for _, path := range excludedPaths {
+		setNodeAt(a, segmentPath(path), nil)
+		setNodeAt(b, segmentPath(path), nil)
+	}
+	return equivalent(a, b)
+}
+
+func equivalent(a, b *yaml.Node) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if a.Kind != b.Kind || a.Value != b.Value || len(a.Content) != len(b.Content) {
+		return false
+	}
+
+	if a.Kind == yaml.MappingNode {
+		bMap := asMap(b)
+		for i := 0; i+1 < len(a.Content); i += 2 {
+			pair, ok := bMap[a.Content[i].Value]
+			if !ok || !equivalent(a.Content[i+1], pair.Value) {
+				return false
+			}
+		}
+		return true
+	}
+
+	for i := range a.Content {
+		if !equivalent(a.Content[i], b.Content[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func isEnvironmentSpecific(node *yaml.Node) bool {
+	name, _ := ParseTag(node.Tag)
+	return name == LockTag || name == LocalTag
+}
@@ -120,11 +120,50 @@ func findNode(node *yaml.Node, pathSegments []string) (*yaml.Node, error) {
 	return nil, fmt.Errorf("key '%s' does not exist", pathSegments[0])
 }
 
+// segmentPath splits given dot-separated path into its keys, where dots and backslashes within keys are escaped with
+// a backslash, as done by joinPath.
 func segmentPath(path string) []string {
-	segments := strings.Split(path, ".")
+	var (
+		segments []string
+		segment  strings.Builder
+		escaped  bool
+	)
+	for _, char := range path {
+		switch {
+		case escaped:
+			segment.WriteRune(char)
+			escaped = false
+		case char == '\\':
+			escaped = true
+		case char == '.':
+			segments = append(segments, segment.String())
+			segment.Reset()
+		default:
+			segment.WriteRune(char)
+		}
+	}
+	segments = append(segments, segment.String())
+
 	if len(segments) > 0 && segments[0] == "" {
 		segments = segments[1:]
 	}
 
 	return segments
 }
+
+// joinPath returns the dot-separated path of given keys, escaping dots and backslashes within keys, such that keys
+// like "nginx.ingress.kubernetes.io/rewrite-target" are not mistaken for nested ones.
+func joinPath(keys []string) string {
+	escaped := make([]string, len(keys))
+	for i, key := range keys {
+		escaped[i] = pathEscaper.Replace(key)
+	}
+	return strings.Join(escaped, ".")
+}
+
+var pathEscaper = strings.NewReplacer(`\`, `\\`, ".", `\.`)
+
+// RemoveNode removes the key and value at given path, relative to given node, doing nothing if not found.
+func RemoveNode(node *yaml.Node, path string) {
+	setNodeAt(unwrapDocument(node), segmentPath(path), nil)
+}
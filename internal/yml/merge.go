@@ -6,7 +6,57 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
+// MergeScope restricts which parts of the source tree get merged into the destination.
+// Paths are dot-separated keys relative to the document root, such as "spec.version", where dots within keys are
+// escaped with a backslash, such as "spec.values.annotations.nginx\.ingress\.kubernetes\.io/rewrite-target".
+// The zero value merges the whole tree.
+type MergeScope struct {
+	// Only restricts the merge to the given paths, keeping everything else from destination as is.
+	Only []string
+
+	// Except excludes the given paths from the merge, keeping their destination values as is.
+	Except []string
+
+	// Environment is the name of the target environment of the merge, against which environment-scoped
+	// tags such as "!lock:prod" are matched. Such tags never match when no environment is specified.
+	Environment string
+}
+
+// IsZero returns true if scope does not restrict the merge to any paths.
+func (scope MergeScope) IsZero() bool {
+	return len(scope.Only) == 0 && len(scope.Except) == 0
+}
+
+// Includes returns true if the value at given path is merged, at least partially, under this scope.
+func (scope MergeScope) Includes(path string) bool {
+	segments := segmentPath(path)
+	for _, except := range scope.Except {
+		if hasPathPrefix(segments, segmentPath(except)) {
+			return false
+		}
+	}
+	if len(scope.Only) == 0 {
+		return true
+	}
+	for _, only := range scope.Only {
+		if onlySegments := segmentPath(only); hasPathPrefix(segments, onlySegments) || hasPathPrefix(onlySegments, segments) {
+			return true
+		}
+	}
+	return false
+}
+
+// merger merges yaml trees for a given target environment.
+type merger struct {
+	// environment is the name of the target environment, used to resolve environment-scoped tags.
+	environment string
+}
+
 func Merge(dst, src *yaml.Node) *yaml.Node {
+	return merger{}.mergeDocuments(dst, src)
+}
+
+func (m merger) mergeDocuments(dst, src *yaml.Node) *yaml.Node {
 	dst, src = Clone(dst), Clone(src)
 
 	doc := func() *yaml.Node {
@@ -22,10 +72,10 @@ func Merge(dst, src *yaml.Node) *yaml.Node {
 	dst = unwrapDocument(dst)
 
 	src = unwrapDocument(src)
-	src = markLockedValuesAsTodo(src, false)
-	src = purgeLocalContent(src)
+	src = m.markLockedValuesAsTodo(src, false)
+	src = m.purgeLocalContent(src)
 
-	result := merge(dst, src)
+	result := m.merge(dst, src)
 	if result == nil {
 		result = &yaml.Node{Kind: yaml.MappingNode}
 	}
@@ -34,32 +84,76 @@ func Merge(dst, src *yaml.Node) *yaml.Node {
 	return doc
 }
 
-func merge(dst, src *yaml.Node) *yaml.Node {
+// MergeWithScope merges src into dst like Merge, but only for the parts of the tree included in given scope.
+// All other values are kept as they are in dst. When dst is nil, there is nothing to preserve and the whole
+// source tree is merged, regardless of scope.
+func MergeWithScope(dst, src *yaml.Node, scope MergeScope) *yaml.Node {
+	merged := merger{environment: scope.Environment}.mergeDocuments(dst, src)
+	if scope.IsZero() || unwrapDocument(dst) == nil {
+		return merged
+	}
+
+	result := merged
+	if len(scope.Only) > 0 {
+		result = Clone(dst)
+		if result.Kind != yaml.DocumentNode {
+			result = &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{result}}
+		}
+		for _, path := range scope.Only {
+			setNodeAt(result.Content[0], segmentPath(path), Clone(nodeAt(unwrapDocument(merged), segmentPath(path))))
+		}
+	}
+
+	for _, path := range scope.Except {
+		setNodeAt(result.Content[0], segmentPath(path), Clone(nodeAt(unwrapDocument(dst), segmentPath(path))))
+	}
+
+	return result
+}
+
+func (m merger) merge(dst, src *yaml.Node) *yaml.Node {
 	// If destination is locked, it does not matter what source is.
 	// If destination exists but source is locked, disregard source.
-	if isLocked(dst) || (dst != nil && isLocked(src)) || isLocal(dst) || isLocal(src) {
+	if m.isLocked(dst) || (dst != nil && m.isLocked(src)) || m.isLocal(dst) || m.isLocal(src) {
 		return dst
 	}
 
 	// If destination is nil, create the node from source.
 	// If source is nil we can set the return to nil which will remove the node
 	// in map and sequence merges. This is fine because we know dst is not locked.
+	if dst == nil || src == nil {
+		return src
+	}
+
+	// Merge strategy tags can be specified on either side, but the source takes precedence.
+	// The tag is carried over to the result, such that the strategy remains in effect for subsequent merges.
+	tag := firstNonEmpty(strategyTag(src), strategyTag(dst))
+	strategy, param := ParseTag(tag)
+	switch {
+	case strategy == ReplaceTag:
+		src.Tag = tag
+		return src
+	case strategy == MergeByTag && dst.Kind == yaml.SequenceNode && src.Kind == yaml.SequenceNode:
+		dst.Tag = tag
+		return m.mergeSeqByKey(dst, src, param)
+	}
+
 	// If the kind is different we simply go with the updating source.
-	if dst == nil || src == nil || dst.Kind != src.Kind {
+	if dst.Kind != src.Kind {
 		return src
 	}
 
 	switch src.Kind {
 	case yaml.MappingNode:
-		return mergeMap(dst, src)
+		return m.mergeMap(dst, src)
 	case yaml.SequenceNode:
-		return mergeSeq(dst, src)
+		return m.mergeSeq(dst, src)
 	default: // Scalar and Alias nodes
 		return src
 	}
 }
 
-func mergeMap(dst, src *yaml.Node) *yaml.Node {
+func (m merger) mergeMap(dst, src *yaml.Node) *yaml.Node {
 	var (
 		dstMap  = asMap(dst)
 		srcMap  = asMap(src)
@@ -69,7 +163,7 @@ func mergeMap(dst, src *yaml.Node) *yaml.Node {
 
 	for _, key := range keys {
 		dstKV, srcKV := dstMap[key], srcMap[key]
-		if value := merge(dstKV.Value, srcKV.Value); value != nil {
+		if value := m.merge(dstKV.Value, srcKV.Value); value != nil {
 			content = append(content, firstNonNil(dstKV.Key, srcKV.Key), value)
 		}
 	}
@@ -80,14 +174,14 @@ func mergeMap(dst, src *yaml.Node) *yaml.Node {
 	return dst
 }
 
-func mergeSeq(dst, src *yaml.Node) *yaml.Node {
+func (m merger) mergeSeq(dst, src *yaml.Node) *yaml.Node {
 	var (
 		maxLen  = max(len(dst.Content), len(src.Content))
 		content []*yaml.Node
 	)
 
 	for i := 0; i < maxLen; i++ {
-		if value := merge(at(dst, i), at(src, i)); value != nil {
+		if value := m.merge(at(dst, i), at(src, i)); value != nil {
 			content = append(content, value)
 		}
 	}
@@ -98,6 +192,81 @@ func mergeSeq(dst, src *yaml.Node) *yaml.Node {
 	return dst
 }
 
+// mergeSeqByKey merges sequences of mappings by matching their items on the value of given key field,
+// instead of by index. Items are ordered as in source, followed by destination items that must be preserved.
+func (m merger) mergeSeqByKey(dst, src *yaml.Node, key string) *yaml.Node {
+	var (
+		matched = make(map[*yaml.Node]bool)
+		content []*yaml.Node
+	)
+
+	for _, srcItem := range src.Content {
+		dstItem := findItemByKey(dst, key, srcItem)
+		if dstItem != nil {
+			matched[dstItem] = true
+		}
+		if value := m.merge(dstItem, srcItem); value != nil {
+			content = append(content, value)
+		}
+	}
+
+	for _, dstItem := range dst.Content {
+		if matched[dstItem] {
+			continue
+		}
+		if value := m.merge(dstItem, nil); value != nil {
+			content = append(content, value)
+		}
+	}
+
+	dst.Content = content
+	dst.Style = mergeStyle(dst.Style, src.Style)
+
+	return dst
+}
+
+// findItemByKey returns the item of given sequence whose key field has the same value as that of given item,
+// or nil if item has no such key or no match is found.
+func findItemByKey(seq *yaml.Node, key string, item *yaml.Node) *yaml.Node {
+	value := keyValueOf(item, key)
+	if value == "" {
+		return nil
+	}
+	for _, candidate := range seq.Content {
+		if keyValueOf(candidate, key) == value {
+			return candidate
+		}
+	}
+	return nil
+}
+
+func keyValueOf(item *yaml.Node, key string) string {
+	if item == nil || item.Kind != yaml.MappingNode {
+		return ""
+	}
+	if value := asMap(item)[key].Value; value != nil && value.Kind == yaml.ScalarNode {
+		return value.Value
+	}
+	return ""
+}
+
+// strategyTag returns the node's tag if it is a merge strategy tag, or an empty string otherwise.
+func strategyTag(node *yaml.Node) string {
+	if name, _ := ParseTag(node.Tag); name == ReplaceTag || name == MergeByTag {
+		return node.Tag
+	}
+	return ""
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
 func at(node *yaml.Node, i int) *yaml.Node {
 	if node == nil || i >= len(node.Content) {
 		return nil
@@ -131,12 +300,12 @@ func Clone(node *yaml.Node) *yaml.Node {
 	return &copy
 }
 
-func markLockedValuesAsTodo(node *yaml.Node, locked bool) *yaml.Node {
+func (m merger) markLockedValuesAsTodo(node *yaml.Node, locked bool) *yaml.Node {
 	if node == nil {
 		return nil
 	}
 
-	locked = locked || isLocked(node)
+	locked = locked || m.isLocked(node)
 
 	switch node.Kind {
 	case yaml.ScalarNode:
@@ -145,23 +314,33 @@ func markLockedValuesAsTodo(node *yaml.Node, locked bool) *yaml.Node {
 		}
 	case yaml.SequenceNode:
 		for i, n := range node.Content {
-			node.Content[i] = markLockedValuesAsTodo(n, locked)
+			node.Content[i] = m.markLockedValuesAsTodo(n, locked)
 		}
 	case yaml.MappingNode:
 		for i := 1; i < len(node.Content); i += 2 {
-			node.Content[i] = markLockedValuesAsTodo(node.Content[i], locked)
+			node.Content[i] = m.markLockedValuesAsTodo(node.Content[i], locked)
 		}
 	}
 
 	return node
 }
 
-func isLocked(node *yaml.Node) bool {
-	return node != nil && node.Tag == "!lock"
+func (m merger) isLocked(node *yaml.Node) bool {
+	return node != nil && m.matchesTag(node.Tag, LockTag)
+}
+
+func (m merger) isLocal(node *yaml.Node) bool {
+	return node != nil && m.matchesTag(node.Tag, LocalTag)
 }
 
-func isLocal(node *yaml.Node) bool {
-	return node != nil && node.Tag == "!local"
+// matchesTag returns true if given tag has the given name and is either not scoped to any environments,
+// or scoped to environments including the target environment.
+func (m merger) matchesTag(tag, name string) bool {
+	if tagName, _ := ParseTag(tag); tagName != name {
+		return false
+	}
+	environments := GetTagEnvironments(tag)
+	return len(environments) == 0 || slices.Contains(environments, m.environment)
 }
 
 type KeyValuePair struct {
@@ -215,8 +394,8 @@ func firstNonNil(nodes ...*yaml.Node) *yaml.Node {
 	return nil
 }
 
-func purgeLocalContent(node *yaml.Node) *yaml.Node {
-	if node == nil || isLocal(node) {
+func (m merger) purgeLocalContent(node *yaml.Node) *yaml.Node {
+	if node == nil || m.isLocal(node) {
 		return nil
 	}
 
@@ -226,19 +405,70 @@ func purgeLocalContent(node *yaml.Node) *yaml.Node {
 	switch node.Kind {
 	case yaml.MappingNode:
 		for i := 0; i < len(node.Content); i += 2 {
-			if isLocal(node.Content[i+1]) {
+			if m.isLocal(node.Content[i+1]) {
 				continue
 			}
-			copy.Content = append(copy.Content, node.Content[i], purgeLocalContent(node.Content[i+1]))
+			copy.Content = append(copy.Content, node.Content[i], m.purgeLocalContent(node.Content[i+1]))
 		}
 	case yaml.SequenceNode:
 		for _, item := range node.Content {
-			if isLocal(item) {
+			if m.isLocal(item) {
 				continue
 			}
-			copy.Content = append(copy.Content, purgeLocalContent(item))
+			copy.Content = append(copy.Content, m.purgeLocalContent(item))
 		}
 	}
 
 	return &copy
 }
+
+// nodeAt returns the value node at given path within mapping node, or nil if not found.
+func nodeAt(node *yaml.Node, segments []string) *yaml.Node {
+	for _, segment := range segments {
+		if node == nil || node.Kind != yaml.MappingNode {
+			return nil
+		}
+		node = asMap(node)[segment].Value
+	}
+	return node
+}
+
+// setNodeAt sets the value node at given path within mapping node, creating intermediate mappings as needed.
+// A nil value removes the key at given path.
+func setNodeAt(node *yaml.Node, segments []string, value *yaml.Node) {
+	if node == nil || node.Kind != yaml.MappingNode || len(segments) == 0 {
+		return
+	}
+
+	key, rest := segments[0], segments[1:]
+	for i := 0; i < len(node.Content); i += 2 {
+		if node.Content[i].Value != key {
+			continue
+		}
+		if len(rest) > 0 {
+			setNodeAt(node.Content[i+1], rest, value)
+			return
+		}
+		if value == nil {
+			node.Content = slices.Delete(node.Content, i, i+2)
+			return
+		}
+		node.Content[i+1] = value
+		return
+	}
+
+	if value == nil {
+		return
+	}
+
+	child := value
+	if len(rest) > 0 {
+		child = &yaml.Node{Kind: yaml.MappingNode}
+		setNodeAt(child, rest, value)
+	}
+	node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, child)
+}
+
+func hasPathPrefix(path, prefix []string) bool {
+	return len(path) >= len(prefix) && slices.Equal(path[:len(prefix)], prefix)
+}
@@ -769,3 +769,275 @@ func TestYmlMerge(t *testing.T) {
 		})
 	}
 }
+
+func TestMergeWithScope(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Scope    MergeScope
+		Src      string
+		Dst      string
+		Expected string
+	}{
+		{
+			Name:     "zero scope merges everything",
+			Src:      "{spec: {version: 2.0.0, values: {a: 1}}}",
+			Dst:      "{spec: {version: 1.0.0, values: {a: 2}}}",
+			Expected: "{spec: {version: 2.0.0, values: {a: 1}}}",
+		},
+		{
+			Name:     "only version",
+			Scope:    MergeScope{Only: []string{"spec.version"}},
+			Src:      "{spec: {version: 2.0.0, values: {a: 1}}}",
+			Dst:      "{spec: {version: 1.0.0, values: {a: 2}}}",
+			Expected: "{spec: {version: 2.0.0, values: {a: 2}}}",
+		},
+		{
+			Name:     "only version missing in dst",
+			Scope:    MergeScope{Only: []string{"spec.version"}},
+			Src:      "{spec: {version: 2.0.0, values: {a: 1}}}",
+			Dst:      "{spec: {values: {a: 2}}}",
+			Expected: "{spec: {values: {a: 2}, version: 2.0.0}}",
+		},
+		{
+			Name:     "only locked version",
+			Scope:    MergeScope{Only: []string{"spec.version"}},
+			Src:      "{spec: {version: 2.0.0, values: {a: 1}}}",
+			Dst:      "{spec: {version: !lock 1.0.0, values: {a: 2}}}",
+			Expected: "{spec: {version: !lock 1.0.0, values: {a: 2}}}",
+		},
+		{
+			Name:     "except version",
+			Scope:    MergeScope{Except: []string{"spec.version"}},
+			Src:      "{spec: {version: 2.0.0, values: {a: 1}}}",
+			Dst:      "{spec: {version: 1.0.0, values: {a: 2}}}",
+			Expected: "{spec: {version: 1.0.0, values: {a: 1}}}",
+		},
+		{
+			Name:     "except version missing in dst",
+			Scope:    MergeScope{Except: []string{"spec.version"}},
+			Src:      "{spec: {version: 2.0.0, values: {a: 1}}}",
+			Dst:      "{spec: {values: {a: 2}}}",
+			Expected: "{spec: {values: {a: 1}}}",
+		},
+		{
+			Name:     "only key containing dots",
+			Scope:    MergeScope{Only: []string{`spec.values.annotations.nginx\.ingress\.kubernetes\.io/rewrite-target`}},
+			Src:      "{spec: {version: 2.0.0, values: {annotations: {nginx.ingress.kubernetes.io/rewrite-target: new, other: new}}}}",
+			Dst:      "{spec: {version: 1.0.0, values: {annotations: {nginx.ingress.kubernetes.io/rewrite-target: old, other: old}}}}",
+			Expected: "{spec: {version: 1.0.0, values: {annotations: {nginx.ingress.kubernetes.io/rewrite-target: new, other: old}}}}",
+		},
+		{
+			Name:     "except version keeps local values",
+			Scope:    MergeScope{Except: []string{"spec.version"}},
+			Src:      "{spec: {version: 2.0.0, values: {a: 1, b: !local 3}}}",
+			Dst:      "{spec: {version: 1.0.0, values: {a: 2, c: !local 4}}}",
+			Expected: "{spec: {version: 1.0.0, values: {a: 1, c: !local 4}}}",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			var src yaml.Node
+			require.NoError(t, yaml.Unmarshal([]byte(tc.Src), &src))
+
+			var dst yaml.Node
+			require.NoError(t, yaml.Unmarshal([]byte(tc.Dst), &dst))
+
+			result, err := yaml.Marshal(MergeWithScope(&dst, &src, tc.Scope).Content[0])
+			require.NoError(t, err)
+
+			require.Equal(t, tc.Expected, string(bytes.TrimSpace(result)))
+		})
+	}
+}
+
+func TestMergeWithScopeWhenDestinationIsNil(t *testing.T) {
+	var src yaml.Node
+	require.NoError(t, yaml.Unmarshal([]byte("{spec: {version: 2.0.0, values: {a: 1}}}"), &src))
+
+	result, err := yaml.Marshal(MergeWithScope(nil, &src, MergeScope{Only: []string{"spec.version"}}).Content[0])
+	require.NoError(t, err)
+
+	require.Equal(t, "{spec: {version: 2.0.0, values: {a: 1}}}", string(bytes.TrimSpace(result)))
+}
+
+func TestMergeWithScopeOfDiffPaths(t *testing.T) {
+	var src, dst yaml.Node
+	require.NoError(t, yaml.Unmarshal([]byte("{spec: {values: {annotations: {a.b/c: new}, other: new}}}"), &src))
+	require.NoError(t, yaml.Unmarshal([]byte("{spec: {values: {annotations: {a.b/c: old}, other: old}}}"), &dst))
+
+	paths := DiffPaths(&dst, &src)
+	require.Equal(t, []string{`spec.values.annotations.a\.b/c`, "spec.values.other"}, paths)
+
+	result, err := yaml.Marshal(MergeWithScope(&dst, &src, MergeScope{Only: paths[:1]}).Content[0])
+	require.NoError(t, err)
+
+	require.Equal(t, "{spec: {values: {annotations: {a.b/c: new}, other: old}}}", string(bytes.TrimSpace(result)))
+}
+
+func TestMergeScopeIncludes(t *testing.T) {
+	require.True(t, MergeScope{}.Includes("spec.version"))
+	require.True(t, MergeScope{Only: []string{"spec.version"}}.Includes("spec.version"))
+	require.True(t, MergeScope{Only: []string{"spec"}}.Includes("spec.version"))
+	require.True(t, MergeScope{Only: []string{"spec.values.a"}}.Includes("spec.values"))
+	require.False(t, MergeScope{Only: []string{"spec.values"}}.Includes("spec.version"))
+	require.False(t, MergeScope{Except: []string{"spec.version"}}.Includes("spec.version"))
+	require.False(t, MergeScope{Except: []string{"spec"}}.Includes("spec.version"))
+	require.True(t, MergeScope{Only: []string{`spec.values.a\.b`}}.Includes(`spec.values.a\.b`))
+	require.False(t, MergeScope{Only: []string{`spec.values.a\.b`}}.Includes("spec.values.a.b"))
+}
+
+func TestMergeStrategies(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Src      string
+		Dst      string
+		Expected string
+	}{
+		{
+			Name:     "replace sequence from source",
+			Src:      "{hosts: !replace [a, b]}",
+			Dst:      "{hosts: [c, d, e]}",
+			Expected: "{hosts: !replace [a, b]}",
+		},
+		{
+			Name:     "replace mapping from destination tag",
+			Src:      "{env: {A: '1'}}",
+			Dst:      "{env: !replace {A: '2', B: '3'}}",
+			Expected: "{env: !replace {A: '1'}}",
+		},
+		{
+			Name:     "replace does not override locked destination",
+			Src:      "{hosts: !replace [a, b]}",
+			Dst:      "{hosts: !lock [c]}",
+			Expected: "{hosts: !lock [c]}",
+		},
+		{
+			Name:     "merge by key",
+			Src:      "{env: !merge-by:name [{name: A, value: '1'}, {name: B, value: '2'}]}",
+			Dst:      "{env: [{name: B, value: '3', extra: x}, {name: A, value: '4'}, {name: C, value: '5'}]}",
+			Expected: "{env: !merge-by:name [{name: A, value: '1'}, {name: B, value: '2'}]}",
+		},
+		{
+			Name:     "merge by key preserves locked destination items",
+			Src:      "{env: [{name: A, value: '1'}, {name: B, value: '2'}]}",
+			Dst:      "{env: !merge-by:name [{name: B, value: !lock '3'}, !lock {name: C, value: '5'}, {name: D, value: '6'}]}",
+			Expected: "{env: !merge-by:name [{name: A, value: '1'}, {name: B, value: !lock '3'}, !lock {name: C, value: '5'}]}",
+		},
+		{
+			Name:     "merge by key preserves local destination items",
+			Src:      "{env: [{name: A, value: '1'}]}",
+			Dst:      "{env: !merge-by:name [!local {name: Z, value: '9'}]}",
+			Expected: "{env: !merge-by:name [{name: A, value: '1'}, !local {name: Z, value: '9'}]}",
+		},
+		{
+			Name:     "merge by key with conflicting kinds",
+			Src:      "{env: !merge-by:name {name: A}}",
+			Dst:      "{env: [{name: A}]}",
+			Expected: "{env: !merge-by:name {name: A}}",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			var src yaml.Node
+			require.NoError(t, yaml.Unmarshal([]byte(tc.Src), &src))
+
+			var dst yaml.Node
+			require.NoError(t, yaml.Unmarshal([]byte(tc.Dst), &dst))
+
+			result, err := yaml.Marshal(Merge(&dst, &src).Content[0])
+			require.NoError(t, err)
+
+			require.Equal(t, tc.Expected, string(bytes.TrimSpace(result)))
+		})
+	}
+}
+
+func TestMergeEnvironmentScopedTags(t *testing.T) {
+	cases := []struct {
+		Name        string
+		Environment string
+		Src         string
+		Dst         string
+		Expected    string
+	}{
+		{
+			Name:        "lock scoped to target environment",
+			Environment: "prod",
+			Src:         "{replicas: 1, debug: true}",
+			Dst:         "{replicas: !lock:prod 3, debug: false}",
+			Expected:    "{replicas: !lock:prod 3, debug: true}",
+		},
+		{
+			Name:        "lock scoped to other environment",
+			Environment: "staging",
+			Src:         "{replicas: 1, debug: true}",
+			Dst:         "{replicas: !lock:prod 3, debug: false}",
+			Expected:    "{replicas: 1, debug: true}",
+		},
+		{
+			Name:        "lock scoped to multiple environments",
+			Environment: "qa",
+			Src:         "{replicas: 1}",
+			Dst:         "{replicas: !lock:prod,qa 3}",
+			Expected:    "{replicas: !lock:prod,qa 3}",
+		},
+		{
+			Name:        "source lock scoped to other environment is promoted with its tag",
+			Environment: "staging",
+			Src:         "{replicas: !lock:prod 1}",
+			Dst:         "{replicas: 3}",
+			Expected:    "{replicas: !lock:prod 1}",
+		},
+		{
+			Name:        "source lock scoped to target environment is disregarded",
+			Environment: "prod",
+			Src:         "{replicas: !lock:prod 1}",
+			Dst:         "{replicas: 3}",
+			Expected:    "{replicas: 3}",
+		},
+		{
+			Name:        "source lock scoped to target environment is marked as todo in empty destination",
+			Environment: "prod",
+			Src:         "{replicas: !lock:prod 1}",
+			Dst:         "{}",
+			Expected:    "{replicas: !lock:prod TODO}",
+		},
+		{
+			Name:        "local scoped to target environment",
+			Environment: "prod",
+			Src:         "{debug: !local:prod true, extra: !local:prod value}",
+			Dst:         "{debug: !local:prod false}",
+			Expected:    "{debug: !local:prod false}",
+		},
+		{
+			Name:        "local scoped to other environment",
+			Environment: "staging",
+			Src:         "{debug: !local:prod true, extra: !local:prod value}",
+			Dst:         "{debug: !local:prod false}",
+			Expected:    "{debug: !local:prod true, extra: !local:prod value}",
+		},
+		{
+			Name:     "scoped lock without environment",
+			Src:      "{replicas: 1}",
+			Dst:      "{replicas: !lock:prod 3}",
+			Expected: "{replicas: 1}",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			var src yaml.Node
+			require.NoError(t, yaml.Unmarshal([]byte(tc.Src), &src))
+
+			var dst yaml.Node
+			require.NoError(t, yaml.Unmarshal([]byte(tc.Dst), &dst))
+
+			result, err := yaml.Marshal(MergeWithScope(&dst, &src, MergeScope{Environment: tc.Environment}).Content[0])
+			require.NoError(t, err)
+
+			require.Equal(t, tc.Expected, string(bytes.TrimSpace(result)))
+		})
+	}
+}
@@ -1,5 +1,10 @@
 package yml
 
+import (
+	"slices"
+	"strings"
+)
+
 var StandardTags = []string{
 	"",
 	"!!int",
@@ -11,6 +16,60 @@ var StandardTags = []string{
 	"!!null",
 }
 
-var CustomTags = []string{"!lock", "!local"}
+const (
+	LockTag    = "!lock"
+	LocalTag   = "!local"
+	ReplaceTag = "!replace"
+	MergeByTag = "!merge-by"
+)
+
+var CustomTags = []string{LockTag, LocalTag, ReplaceTag, MergeByTag}
+
+// ParameterizedTags are the custom tags that require a parameter, in the form of "!tag:param".
+var ParameterizedTags = []string{MergeByTag}
+
+// EnvironmentScopedTags are the custom tags that can optionally be scoped to a comma-separated list of
+// target environments, in the form of "!tag:env1,env2". Unscoped, they apply to all environments.
+var EnvironmentScopedTags = []string{LockTag, LocalTag}
+
+// ParseTag splits given tag into its name and optional parameter, ie: "!merge-by:name" yields "!merge-by" and "name".
+func ParseTag(tag string) (name, param string) {
+	if !strings.HasPrefix(tag, "!") || strings.HasPrefix(tag, "!!") {
+		return tag, ""
+	}
+	name, param, _ = strings.Cut(tag, ":")
+	return name, param
+}
 
-var KnownTags = append(StandardTags, CustomTags...)
+// IsCustomTag returns true if given tag is a valid custom tag, with a parameter only if the tag supports one.
+func IsCustomTag(tag string) bool {
+	name, param := ParseTag(tag)
+	switch {
+	case !slices.Contains(CustomTags, name):
+		return false
+	case slices.Contains(ParameterizedTags, name):
+		return param != ""
+	case slices.Contains(EnvironmentScopedTags, name):
+		if param == "" {
+			return !strings.HasSuffix(tag, ":")
+		}
+		return !slices.Contains(strings.Split(param, ","), "")
+	default:
+		return param == ""
+	}
+}
+
+// GetTagEnvironments returns the environments that given environment-scoped tag applies to,
+// or nil if the tag is not scoped to any environments.
+func GetTagEnvironments(tag string) []string {
+	name, param := ParseTag(tag)
+	if param == "" || !slices.Contains(EnvironmentScopedTags, name) {
+		return nil
+	}
+	return strings.Split(param, ",")
+}
+
+// IsKnownTag returns true if given tag is either a standard yaml tag or a valid custom tag.
+func IsKnownTag(tag string) bool {
+	return slices.Contains(StandardTags, tag) || IsCustomTag(tag)
+}
@@ -6,8 +6,14 @@ func HasLockedTodos(node *yaml.Node) bool {
 	return hasLockedTodos(node, false)
 }
 
+// hasLockTag returns true if node is locked, in any environment.
+func hasLockTag(node *yaml.Node) bool {
+	name, _ := ParseTag(node.Tag)
+	return name == LockTag
+}
+
 func hasLockedTodos(node *yaml.Node, locked bool) bool {
-	locked = locked || isLocked(node)
+	locked = locked || hasLockTag(node)
 
 	switch node.Kind {
 	case yaml.DocumentNode, yaml.MappingNode, yaml.SequenceNode:
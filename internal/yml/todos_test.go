@@ -26,6 +26,11 @@ func TestHasLockedTodos(t *testing.T) {
 			}`,
 			Expected: false,
 		},
+		{
+			Name:     "todo locked in specific environment",
+			Value:    `{replicas: !lock:prod TODO}`,
+			Expected: true,
+		},
 		{
 			Name: "todos not locked",
 			Value: `{
@@ -1,6 +1,7 @@
 package yml_test
 
 import (
+	"slices"
 	"testing"
 
 	"gopkg.in/yaml.v3"
@@ -217,3 +218,170 @@ func TestEqualWithExclusion(t *testing.T) {
 		})
 	}
 }
+
+func TestEqualWithExclusions(t *testing.T) {
+	tests := []struct {
+		name          string
+		yaml1         string
+		yaml2         string
+		excludedPaths []string
+		expected      bool
+	}{
+		{
+			name:          "Identical trees excluding multiple paths",
+			yaml1:         "spec: { version: 1.0.0, digest: sha256:aaa, values: { a: 1 } }",
+			yaml2:         "spec: { version: 2.0.0, digest: sha256:bbb, values: { a: 1 } }",
+			excludedPaths: []string{"spec.version", "spec.digest"},
+			expected:      true,
+		},
+		{
+			name:          "Excluded path present on one side only",
+			yaml1:         "spec: { version: 1.0.0, values: { a: 1 } }",
+			yaml2:         "spec: { version: 1.0.0, digest: sha256:bbb, values: { a: 1 } }",
+			excludedPaths: []string{"spec.digest"},
+			expected:      true,
+		},
+		{
+			name:          "Different trees outside of excluded paths",
+			yaml1:         "spec: { version: 1.0.0, values: { a: 1 } }",
+			yaml2:         "spec: { version: 1.0.0, digest: sha256:bbb, values: { a: 2 } }",
+			excludedPaths: []string{"spec.digest"},
+			expected:      false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var a, b yaml.Node
+			if err := yaml.Unmarshal([]byte(test.yaml1), &a); err != nil {
+				t.Fatalf("error unmarshalling yaml1: %v", err)
+			}
+			if err := yaml.Unmarshal([]byte(test.yaml2), &b); err != nil {
+				t.Fatalf("error unmarshalling yaml2: %v", err)
+			}
+			actual := yml.EqualWithExclusions(&a, &b, test.excludedPaths...)
+			if actual != test.expected {
+				t.Errorf("Expected %v, got %v", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestEquivalentWithEnvironmentSpecificExclusions(t *testing.T) {
+	tests := []struct {
+		name     string
+		yaml1    string
+		yaml2    string
+		expected bool
+	}{
+		{
+			name:     "Locked and local values differ",
+			yaml1:    "{ shared: 1, locked: !lock a, local: !local { x: 1 }, scoped: !lock:prod b }",
+			yaml2:    "{ shared: 1, locked: !lock b, scoped: !lock:prod c }",
+			expected: true,
+		},
+		{
+			name:     "Value locked on one side only",
+			yaml1:    "{ shared: 1, replicas: 2 }",
+			yaml2:    "{ shared: 1, replicas: !lock 5 }",
+			expected: true,
+		},
+		{
+			name:     "Local sequence items differ",
+			yaml1:    "{ items: [a, !local b] }",
+			yaml2:    "{ items: [a] }",
+			expected: true,
+		},
+		{
+			name:     "Keys in different order",
+			yaml1:    "{ a: 1, b: { c: 2, d: 3 } }",
+			yaml2:    "{ b: { d: 3, c: 2 }, a: 1 }",
+			expected: true,
+		},
+		{
+			name:     "Shared values differ",
+			yaml1:    "{ shared: 1, locked: !lock a }",
+			yaml2:    "{ shared: 2, locked: !lock a }",
+			expected: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var a, b yaml.Node
+			if err := yaml.Unmarshal([]byte(test.yaml1), &a); err != nil {
+				t.Fatalf("error unmarshalling yaml1: %v", err)
+			}
+			if err := yaml.Unmarshal([]byte(test.yaml2), &b); err != nil {
+				t.Fatalf("error unmarshalling yaml2: %v", err)
+			}
+			excludedPaths := append(yml.EnvironmentSpecificPaths(&a), yml.EnvironmentSpecificPaths(&b)...)
+			actual := yml.EquivalentWithExclusions(&a, &b, excludedPaths...)
+			if actual != test.expected {
+				t.Errorf("Expected %v, got %v", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestDiffPaths(t *testing.T) {
+	tests := []struct {
+		name     string
+		yaml1    string
+		yaml2    string
+		expected []string
+	}{
+		{
+			name:     "Identical trees",
+			yaml1:    "foo: { bar: baz, nested: [item1, item2] }",
+			yaml2:    "foo: { bar: baz, nested: [item1, item2] }",
+			expected: nil,
+		},
+		{
+			name:     "Different nested scalar",
+			yaml1:    "foo: { bar: baz, qux: quux }",
+			yaml2:    "foo: { bar: bam, qux: quux }",
+			expected: []string{"foo.bar"},
+		},
+		{
+			name:     "Different sequences are reported as a whole",
+			yaml1:    "foo: { nested: [item1, item2] }",
+			yaml2:    "foo: { nested: [item1, item3] }",
+			expected: []string{"foo.nested"},
+		},
+		{
+			name:     "Added and removed keys",
+			yaml1:    "{ foo: { bar: baz }, removed: value }",
+			yaml2:    "{ foo: { bar: baz, added: value } }",
+			expected: []string{"foo.added", "removed"},
+		},
+		{
+			name:     "Different kinds",
+			yaml1:    "foo: { bar: baz }",
+			yaml2:    "foo: [bar, baz]",
+			expected: []string{"foo"},
+		},
+		{
+			name:     "Keys containing dots are escaped",
+			yaml1:    "annotations: { nginx.ingress.kubernetes.io/rewrite-target: old, a\\b: c }",
+			yaml2:    "annotations: { nginx.ingress.kubernetes.io/rewrite-target: new, a\\b: d }",
+			expected: []string{`annotations.a\\b`, `annotations.nginx\.ingress\.kubernetes\.io/rewrite-target`},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var a, b yaml.Node
+			if err := yaml.Unmarshal([]byte(test.yaml1), &a); err != nil {
+				t.Fatalf("error unmarshalling yaml1: %v", err)
+			}
+			if err := yaml.Unmarshal([]byte(test.yaml2), &b); err != nil {
+				t.Fatalf("error unmarshalling yaml2: %v", err)
+			}
+			actual := yml.DiffPaths(&a, &b)
+			if !slices.Equal(actual, test.expected) {
+				t.Errorf("Expected %v, got %v", test.expected, actual)
+			}
+		})
+	}
+}
@@ -36,6 +36,16 @@ type Catalog struct {
 }
 
 func Load(ctx context.Context, dir string, validChartRefs []string) (*Catalog, error) {
+	return load(ctx, dir, validChartRefs, true)
+}
+
+// LoadWithoutValidation loads catalog like Load, but without validating its resources, which is much faster and
+// suited to read-only use cases that can tolerate invalid resources, such as shell completion.
+func LoadWithoutValidation(ctx context.Context, dir string) (*Catalog, error) {
+	return load(ctx, dir, nil, false)
+}
+
+func load(ctx context.Context, dir string, validChartRefs []string, validate bool) (*Catalog, error) {
 	_, span := observability.StartTrace(ctx, "load_catalog")
 	defer span.End()
 
@@ -90,9 +100,11 @@ func Load(ctx context.Context, dir string, validChartRefs []string) (*Catalog, e
 	}
 
 	var errs []error
-	for _, env := range c.Environments {
-		if err := env.Validate(validChartRefs); err != nil {
-			errs = append(errs, fmt.Errorf("%s: %w", env.Name, err))
+	if validate {
+		for _, env := range c.Environments {
+			if err := env.Validate(validChartRefs); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", env.Name, err))
+			}
 		}
 	}
 
@@ -105,9 +117,11 @@ func Load(ctx context.Context, dir string, validChartRefs []string) (*Catalog, e
 		return nil, fmt.Errorf("loading projects: %w", err)
 	}
 
-	for _, project := range c.Projects {
-		if err := project.Validate(); err != nil {
-			errs = append(errs, fmt.Errorf("%s: %w", project.Name, err))
+	if validate {
+		for _, project := range c.Projects {
+			if err := project.Validate(); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", project.Name, err))
+			}
 		}
 	}
 
@@ -117,8 +131,10 @@ func Load(ctx context.Context, dir string, validChartRefs []string) (*Catalog, e
 
 	allReleaseFiles := c.GetFilesByKind(v1alpha1.ReleaseKind)
 
-	if err := validateTagsForFiles(allReleaseFiles); err != nil {
-		return nil, fmt.Errorf("release files with invalid tags: %w", err)
+	if validate {
+		if err := validateTagsForFiles(allReleaseFiles, c.GetEnvironmentNames()); err != nil {
+			return nil, fmt.Errorf("release files with invalid tags: %w", err)
+		}
 	}
 
 	c.Releases, err = cross.LoadReleaseList(allReleaseFiles, c.Environments, c.Projects, nil)
@@ -130,6 +146,10 @@ func Load(ctx context.Context, dir string, validChartRefs []string) (*Catalog, e
 		return nil, fmt.Errorf("resolving references: %w", err)
 	}
 
+	if !validate {
+		return c, nil
+	}
+
 	for _, cross := range c.Releases.Items {
 		for _, release := range cross.Releases {
 			if release == nil {
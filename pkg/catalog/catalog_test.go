@@ -47,6 +47,16 @@ func TestCatalogLoadE2E(t *testing.T) {
 	}
 }
 
+func TestLoadWithoutValidation(t *testing.T) {
+	for _, folder := range []string{"broken-chart-ref-environment", "broken-chart-ref-release"} {
+		t.Run(folder, func(t *testing.T) {
+			cat, err := LoadWithoutValidation(context.Background(), filepath.Join("testdata", folder))
+			require.NoError(t, err)
+			require.NotEmpty(t, cat.Environments)
+		})
+	}
+}
+
 func TestFreeformEnvsAndReleasesLoading(t *testing.T) {
 	catalogDir, err := filepath.Abs("testdata/freeform")
 	require.NoError(t, err)
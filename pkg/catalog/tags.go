@@ -12,27 +12,45 @@ import (
 	"github.com/nestoca/joy/internal/yml"
 )
 
-func validateTagsForFiles(files []*yml.File) error {
+func validateTagsForFiles(files []*yml.File, environments []string) error {
 	var errs []error
 	for _, file := range files {
-		if err := validateTags(file.Tree); err != nil {
+		if err := validateTags(file.Tree, environments); err != nil {
 			errs = append(errs, fmt.Errorf("%s: %w", file.Path, err))
 		}
 	}
 	return errors.Join(errs...)
 }
 
-func validateTags(node *yaml.Node) error {
-	if tags := buildUnknownTagsList(node); len(tags) > 0 {
+func validateTags(node *yaml.Node, environments []string) error {
+	if tags := buildUnknownTagsList(node, isUnknownTag); len(tags) > 0 {
 		sort.Strings(tags)
 		return fmt.Errorf("unknown tag(s): %s", strings.Join(tags, ", "))
 	}
+
+	hasUnknownEnvironment := func(tag string) bool {
+		for _, env := range yml.GetTagEnvironments(tag) {
+			if !slices.Contains(environments, env) {
+				return true
+			}
+		}
+		return false
+	}
+	if tags := buildUnknownTagsList(node, hasUnknownEnvironment); len(tags) > 0 {
+		sort.Strings(tags)
+		return fmt.Errorf("tag(s) scoped to unknown environment(s): %s", strings.Join(tags, ", "))
+	}
+
 	return nil
 }
 
-func buildUnknownTagsList(node *yaml.Node) []string {
+func isUnknownTag(tag string) bool {
+	return !yml.IsKnownTag(tag)
+}
+
+func buildUnknownTagsList(node *yaml.Node, isUnknown func(tag string) bool) []string {
 	var (
-		set  = buildUnknownTagsSet(node, nil)
+		set  = buildUnknownTagsSet(node, isUnknown, nil)
 		list = make([]string, 0, len(set))
 	)
 	for value := range set {
@@ -41,17 +59,17 @@ func buildUnknownTagsList(node *yaml.Node) []string {
 	return list
 }
 
-func buildUnknownTagsSet(node *yaml.Node, set map[string]struct{}) map[string]struct{} {
+func buildUnknownTagsSet(node *yaml.Node, isUnknown func(tag string) bool, set map[string]struct{}) map[string]struct{} {
 	if set == nil {
 		set = make(map[string]struct{})
 	}
 
-	if !slices.Contains(yml.KnownTags, node.Tag) {
+	if isUnknown(node.Tag) {
 		set[node.Tag] = struct{}{}
 	}
 
 	for _, content := range node.Content {
-		set = buildUnknownTagsSet(content, set)
+		set = buildUnknownTagsSet(content, isUnknown, set)
 	}
 
 	return set
@@ -35,7 +35,35 @@ func TestValidateTagsForFiles(t *testing.T) {
 					Path:    "./custom.yaml",
 					Content: "{pad: !lock ''}",
 				},
+				{
+					Path:    "./strategies.yaml",
+					Content: "{env: !merge-by:name [], hosts: !replace []}",
+				},
+				{
+					Path:    "./scoped.yaml",
+					Content: "{replicas: !lock:prod 3, debug: !local:staging,prod true}",
+				},
+			},
+		},
+		{
+			Name: "tags scoped to unknown environments",
+			Files: []File{
+				{
+					Path:    "./scoped.yaml",
+					Content: "{replicas: !lock:prdo 3, debug: !local:staging,qa true, ok: !lock:prod 1}",
+				},
+			},
+			ExpectedErr: "./scoped.yaml: tag(s) scoped to unknown environment(s): !local:staging,qa, !lock:prdo",
+		},
+		{
+			Name: "malformed custom tags",
+			Files: []File{
+				{
+					Path:    "./strategies.yaml",
+					Content: "{env: !merge-by [], hosts: !replace:name [], replicas: !lock: 3}",
+				},
 			},
+			ExpectedErr: "./strategies.yaml: unknown tag(s): !lock:, !merge-by, !replace:name",
 		},
 		{
 			Name: "unknown tags",
@@ -62,7 +90,7 @@ func TestValidateTagsForFiles(t *testing.T) {
 				files[i] = &yml.File{Path: file.Path, Tree: &node}
 			}
 
-			err := validateTagsForFiles(files)
+			err := validateTagsForFiles(files, []string{"staging", "prod"})
 			if tc.ExpectedErr != "" {
 				require.EqualError(t, err, tc.ExpectedErr)
 				return
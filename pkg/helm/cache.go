@@ -11,12 +11,24 @@ import (
 	"path"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/davidmdm/x/xfs"
 
 	"github.com/nestoca/joy/api/v1alpha1"
 )
 
+// chartDirLocks holds a mutex per chart directory of the cache, shared by all ChartCache values.
+var chartDirLocks sync.Map
+
+// lockChartDir locks given chart directory, returning the function to unlock it.
+func lockChartDir(dir string) func() {
+	value, _ := chartDirLocks.LoadOrStore(dir, new(sync.Mutex))
+	mutex := value.(*sync.Mutex)
+	mutex.Lock()
+	return mutex.Unlock
+}
+
 type ChartCache struct {
 	Refs            map[string]Chart
 	DefaultChartRef string
@@ -108,6 +120,11 @@ func (cache ChartCache) GetReleaseChartFS(ctx context.Context, release *v1alpha1
 
 	chartDir := filepath.Join(versionDir, path.Base(uri.Path))
 
+	// Concurrent pulls of the same chart version would untar into the same directory, failing all but one of them,
+	// and readers could otherwise see a partially untarred chart.
+	unlock := lockChartDir(chartDir)
+	defer unlock()
+
 	if _, err := os.Stat(chartDir); err != nil {
 		if !errors.Is(err, os.ErrNotExist) {
 			return nil, fmt.Errorf("verifying cache: %w", err)